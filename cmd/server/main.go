@@ -7,10 +7,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"goapianalyzer/internal/adapter/api/router"
+	domainrepository "goapianalyzer/internal/core/domain/repository"
 	"goapianalyzer/internal/core/domain/service"
 	"goapianalyzer/internal/core/usecase"
 	"goapianalyzer/internal/infrastructure/config"
@@ -43,20 +45,32 @@ func main() {
 	}).Info("Starting GoAPIAnalyzer server")
 
 	// Initialize repository
-	repo := repository.NewMemoryAnalysisRepository()
-	log.Info("Initialized memory repository")
+	repo, err := newRepository(cfg)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize repository")
+	}
+	log.WithField("storage_type", cfg.StorageType).Info("Initialized repository")
 
 	// Initialize services
 	analyzerService := service.NewAnalyzerService()
 	log.Info("Initialized analyzer service")
 
 	// Initialize use cases
-	analyzerUsecase := usecase.NewAnalyzerUsecase(repo, analyzerService)
+	analyzerUsecase := usecase.NewAnalyzerUsecase(repo, analyzerService, &usecase.AnalyzerConfig{
+		MaxCheckedProjects: cfg.MaxStoredProjects,
+		MaxProjectBytes:    cfg.MaxProjectBytes,
+	})
 	filterUsecase := usecase.NewFilterUsecase(repo)
+	scanJobUsecase := usecase.NewScanJobUsecase(analyzerUsecase, &usecase.ScanJobConfig{
+		Concurrency: cfg.ScanConcurrency,
+		QueueSize:   cfg.ScanQueueSize,
+		Timeout:     time.Duration(cfg.ScanTimeout) * time.Second,
+	})
+	scanJobUsecase.Start()
 	log.Info("Initialized use cases")
 
 	// Initialize router
-	r := router.NewRouter(cfg, analyzerUsecase, filterUsecase)
+	r := router.NewRouter(cfg, analyzerUsecase, filterUsecase, scanJobUsecase)
 	engine := r.Setup()
 
 	// Create HTTP server
@@ -96,6 +110,36 @@ func main() {
 	} else {
 		log.Info("Server shutdown completed")
 	}
+
+	if err := scanJobUsecase.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("Scan jobs did not stop in time")
+	}
+
+	if err := repo.Close(); err != nil {
+		log.WithError(err).Error("Failed to close repository")
+	}
+}
+
+// newRepository creates the analysis repository selected by STORAGE_TYPE
+func newRepository(cfg *config.Config) (domainrepository.AnalysisRepository, error) {
+	retention := &repository.RetentionConfig{
+		MaxProjects:     cfg.MaxStoredProjects,
+		TTL:             time.Duration(cfg.ProjectTTL) * time.Second,
+		MaxProjectBytes: cfg.MaxProjectBytes,
+		MaxVersions:     cfg.MaxProjectVersions,
+	}
+
+	switch strings.ToLower(cfg.StorageType) {
+	case "", "memory":
+		return repository.NewMemoryAnalysisRepository(retention), nil
+	case "file", "disk":
+		return repository.NewFileAnalysisRepository(&repository.FileRepositoryConfig{
+			Path:      cfg.StoragePath,
+			Retention: retention,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", cfg.StorageType)
+	}
 }
 
 func printStartupInfo(cfg *config.Config) {
@@ -110,13 +154,38 @@ func printStartupInfo(cfg *config.Config) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 	fmt.Println("📖 Available API Endpoints:")
-	fmt.Println("  POST   /api/v1/analyzer/scan                    - Scan a Go project")
+	fmt.Println("  POST   /api/v1/analyzer/scan                    - Queue a scan of a Go project")
+	fmt.Println("  GET    /api/v1/analyzer/jobs/:id                - Get scan job progress")
+	fmt.Println("  DELETE /api/v1/analyzer/jobs/:id                - Cancel a scan job")
+	fmt.Println("  GET    /api/v1/analyzer/jobs/:id/events         - Stream scan events (SSE)")
+	fmt.Println("  GET    /api/v1/analyzer/jobs/:id/ws             - Stream scan events (WebSocket)")
+	fmt.Println("  GET    /api/v1/analyzer/projects                - List analyzed projects")
 	fmt.Println("  GET    /api/v1/analyzer/projects/:id            - Get project analysis")
 	fmt.Println("  DELETE /api/v1/analyzer/projects/:id            - Delete project analysis")
+	fmt.Println("  POST   /api/v1/analyzer/projects/:id/rescan     - Re-analyze changed files")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/versions   - List stored versions")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/diff       - Compare two versions")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/breaking-changes - Detect API breaking changes")
 	fmt.Println("  GET    /api/v1/analyzer/projects/:id/apis       - List API endpoints")
 	fmt.Println("  GET    /api/v1/analyzer/projects/:id/nodes      - Get all nodes")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/nodes/:nodeId/references - Find references")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/definition?file=&line=&col= - Go to definition")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/nodes/:nodeId/callers?depth= - Callers tree")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/nodes/:nodeId/callees?depth= - Callees tree")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/path?from=&to= - Shortest call path")
+	fmt.Println("  POST   /api/v1/analyzer/projects/:id/impact     - Endpoints affected by a change")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/dependencies?format=dot|graphml|mermaid|cytoscape - Export dependency graph")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/export?format=json|yaml|xml|html - Export project analysis")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/docs?format=markdown|zip - Markdown API reference")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/collection?format=postman|http|rest&base_url= - Request collection")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/sdk?package= - Go and TypeScript client SDK")
+	fmt.Println("  GET    /api/v1/analyzer/schemas/analysis.xsd   - XML schema of analysis exports")
 	fmt.Println("  GET    /api/v1/analyzer/projects/:id/stats      - Get project statistics")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/nodes/search?q= - Search nodes with a node query")
 	fmt.Println("  POST   /api/v1/analyzer/projects/:id/filters    - Apply filters")
+	fmt.Println("  POST   /api/v1/analyzer/projects/:id/patterns/search - Structural code search")
+	fmt.Println("  POST   /api/v1/analyzer/projects/:id/queries    - Save a node query")
+	fmt.Println("  GET    /api/v1/analyzer/projects/:id/queries/:queryId/results - Run a saved query")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 	fmt.Println("💡 Example curl commands:")
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"goapianalyzer/internal/adapter/exporter"
 	"goapianalyzer/internal/core/domain/entity"
 	"goapianalyzer/internal/core/usecase"
 	"goapianalyzer/internal/infrastructure/logger"
@@ -15,17 +19,21 @@ import (
 type AnalyzerHandler struct {
 	analyzerUsecase *usecase.AnalyzerUsecase
 	filterUsecase   *usecase.FilterUsecase
+	scanJobUsecase  *usecase.ScanJobUsecase
 	logger          logger.Logger
 }
 
 type ScanProjectRequest struct {
-	ProjectPath     string   `json:"project_path" binding:"required"`
-	BlacklistFiles  []string `json:"blacklist_files,omitempty"`
-	BlacklistDirs   []string `json:"blacklist_dirs,omitempty"`
-	WhitelistFiles  []string `json:"whitelist_files,omitempty"`
-	WhitelistDirs   []string `json:"whitelist_dirs,omitempty"`
-	IncludeVendor   bool     `json:"include_vendor"`
-	IncludeTestFile bool     `json:"include_test_file"`
+	ProjectPath      string               `json:"project_path" binding:"required"`
+	ProjectID        string               `json:"project_id,omitempty"` // Store the result as a new version of this project
+	BlacklistFiles   []string             `json:"blacklist_files,omitempty"`
+	BlacklistDirs    []string             `json:"blacklist_dirs,omitempty"`
+	WhitelistFiles   []string             `json:"whitelist_files,omitempty"`
+	WhitelistDirs    []string             `json:"whitelist_dirs,omitempty"`
+	IncludeVendor    bool                 `json:"include_vendor"`
+	IncludeTestFile  bool                 `json:"include_test_file"`
+	ExcludeGenerated bool                 `json:"exclude_generated"`
+	BuildContext     *entity.BuildContext `json:"build_context,omitempty"`
 }
 
 type FilterRequest struct {
@@ -37,8 +45,34 @@ type FilterRequest struct {
 	BlacklistDirs  []string `json:"blacklist_dirs,omitempty"`
 	MinComplexity  *int     `json:"min_complexity,omitempty"`
 	MaxComplexity  *int     `json:"max_complexity,omitempty"`
+	Generated      *bool    `json:"generated,omitempty"`
+	Query          string   `json:"query,omitempty"`
 }
 
+type PatternSearchRequest struct {
+	Pattern     string            `json:"pattern" binding:"required"`
+	Constraints map[string]string `json:"constraints,omitempty"` // Metavariable name (without $) -> type
+	Limit       int               `json:"limit,omitempty"`
+}
+
+type ImpactRequest struct {
+	NodeIDs []string            `json:"node_ids,omitempty"`
+	Ranges  []*entity.LineRange `json:"ranges,omitempty"`
+	Diff    string              `json:"diff,omitempty"` // Unified diff, as printed by git diff
+}
+
+type SavedQueryRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Query       string `json:"query" binding:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// Project response views
+const (
+	projectViewSummary = "summary" // Counts, packages, endpoints and file metadata
+	projectViewFull    = "full"    // The complete analysis including sources
+)
+
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
@@ -46,15 +80,21 @@ type APIResponse struct {
 	Error   string      `json:"error,omitempty"`
 }
 
-func NewAnalyzerHandler(analyzerUsecase *usecase.AnalyzerUsecase, filterUsecase *usecase.FilterUsecase) *AnalyzerHandler {
+func NewAnalyzerHandler(
+	analyzerUsecase *usecase.AnalyzerUsecase,
+	filterUsecase *usecase.FilterUsecase,
+	scanJobUsecase *usecase.ScanJobUsecase,
+) *AnalyzerHandler {
 	return &AnalyzerHandler{
 		analyzerUsecase: analyzerUsecase,
 		filterUsecase:   filterUsecase,
+		scanJobUsecase:  scanJobUsecase,
 		logger:          logger.GetLogger(),
 	}
 }
 
-// ScanProject scans a Go project and analyzes its structure
+// ScanProject queues an analysis of a Go project and returns its job.
+// With ?wait=true the response is delayed until the analysis finishes.
 func (h *AnalyzerHandler) ScanProject(c *gin.Context) {
 	var req ScanProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -69,27 +109,120 @@ func (h *AnalyzerHandler) ScanProject(c *gin.Context) {
 		return
 	}
 
+	view, fields, ok := h.projectView(c)
+	if !ok {
+		return
+	}
+
 	h.logger.WithFields(map[string]interface{}{
 		"project_path":    req.ProjectPath,
 		"blacklist_files": req.BlacklistFiles,
 		"blacklist_dirs":  req.BlacklistDirs,
 	}).Info("Starting project scan")
 
-	projectAnalysis, err := h.analyzerUsecase.AnalyzeProject(req.ProjectPath, &entity.AnalysisConfig{
-		BlacklistFiles:  req.BlacklistFiles,
-		BlacklistDirs:   req.BlacklistDirs,
-		WhitelistFiles:  req.WhitelistFiles,
-		WhitelistDirs:   req.WhitelistDirs,
-		IncludeVendor:   req.IncludeVendor,
-		IncludeTestFile: req.IncludeTestFile,
+	job, err := h.scanJobUsecase.Submit(req.ProjectPath, req.ProjectID, &entity.AnalysisConfig{
+		BlacklistFiles:   req.BlacklistFiles,
+		BlacklistDirs:    req.BlacklistDirs,
+		WhitelistFiles:   req.WhitelistFiles,
+		WhitelistDirs:    req.WhitelistDirs,
+		IncludeVendor:    req.IncludeVendor,
+		IncludeTestFile:  req.IncludeTestFile,
+		ExcludeGenerated: req.ExcludeGenerated,
+		BuildContext:     req.BuildContext,
 	})
-
 	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		} else if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsRateLimitError(err) {
+			status = http.StatusTooManyRequests
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if c.Query("wait") != "true" {
+		c.JSON(http.StatusAccepted, APIResponse{
+			Success: true,
+			Message: "Project scan queued",
+			Data:    job,
+		})
+		return
+	}
+
+	// Waiting on a long scan outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.WithError(err).Debug("Failed to clear write deadline while waiting for scan job")
+	}
+
+	job, err = h.scanJobUsecase.WaitJob(c.Request.Context(), job.ID)
+	if job == nil {
+		// The client went away; the job keeps running and can be polled
+		h.logger.WithError(err).Warn("Stopped waiting for scan job")
+		return
+	}
+	if err != nil || job.Status != entity.ScanJobCompleted {
 		h.logger.WithFields(map[string]interface{}{
-			"error":        err.Error(),
+			"job_id":       job.ID,
+			"status":       job.Status,
 			"project_path": req.ProjectPath,
 		}).Error("Failed to analyze project")
 
+		status := http.StatusInternalServerError
+		if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		} else if job.Status == entity.ScanJobCancelled {
+			status = http.StatusConflict
+		}
+
+		message := "scan job " + job.Status
+		if err != nil {
+			message = err.Error()
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   message,
+			Data:    job,
+		})
+		return
+	}
+
+	data, err := h.projectData(job.ProjectID, view)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.streamProject(c, "Project analyzed successfully", data, fields)
+}
+
+// ListProjects lists stored projects as lightweight summaries
+func (h *AnalyzerHandler) ListProjects(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	sortBy := c.DefaultQuery("sort", "created_at")
+	order := c.DefaultQuery("order", "desc")
+	pathFilter := c.Query("path")
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	projects, total, err := h.analyzerUsecase.ListProjects(page, limit, sortBy, order, pathFilter)
+	if err != nil {
 		status := http.StatusInternalServerError
 		if errors.IsValidationError(err) {
 			status = http.StatusBadRequest
@@ -104,8 +237,15 @@ func (h *AnalyzerHandler) ScanProject(c *gin.Context) {
 
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
-		Message: "Project analyzed successfully",
-		Data:    projectAnalysis,
+		Data: map[string]interface{}{
+			"projects":    projects,
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"sort":        sortBy,
+			"order":       order,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
 	})
 }
 
@@ -120,7 +260,12 @@ func (h *AnalyzerHandler) GetProjectAnalysis(c *gin.Context) {
 		return
 	}
 
-	analysis, err := h.analyzerUsecase.GetProjectAnalysis(projectID)
+	view, fields, ok := h.projectView(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.projectData(projectID, view)
 	if err != nil {
 		status := http.StatusNotFound
 		if !errors.IsNotFoundError(err) {
@@ -134,9 +279,142 @@ func (h *AnalyzerHandler) GetProjectAnalysis(c *gin.Context) {
 		return
 	}
 
+	h.streamProject(c, "", data, fields)
+}
+
+// projectView reads the ?view= (summary or full) and ?fields= query parameters
+func (h *AnalyzerHandler) projectView(c *gin.Context) (string, map[string]bool, bool) {
+	view := c.DefaultQuery("view", projectViewSummary)
+	if view != projectViewSummary && view != projectViewFull {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Unsupported view: " + view + " (expected summary or full)",
+		})
+		return "", nil, false
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = true
+		}
+	}
+
+	return view, fields, true
+}
+
+// projectData loads the representation of a project selected by view
+func (h *AnalyzerHandler) projectData(projectID, view string) (interface{}, error) {
+	if view == projectViewFull {
+		return h.analyzerUsecase.GetProjectAnalysis(projectID)
+	}
+	return h.analyzerUsecase.GetProjectOverview(projectID)
+}
+
+// streamProject writes the response envelope around data, encoding files one by one
+func (h *AnalyzerHandler) streamProject(c *gin.Context, message string, data interface{}, fields map[string]bool) {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	stream := newJSONStream(c.Writer)
+	stream.beginObject()
+	stream.field("success", true)
+	if message != "" {
+		stream.field("message", message)
+	}
+	stream.key("data")
+	stream.beginObject()
+	stream.structFields(data, fields, map[string]bool{"files": true})
+	stream.endObject()
+	stream.endObject()
+
+	if err := stream.close(); err != nil {
+		h.logger.WithError(err).WithField("path", c.Request.URL.Path).Warn("Failed to stream project response")
+	}
+}
+
+// GetFileContent returns the source of a single analyzed file
+func (h *AnalyzerHandler) GetFileContent(c *gin.Context) {
+	projectID := c.Param("projectId")
+	filePath := c.Query("path")
+
+	if projectID == "" || filePath == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and file path are required",
+		})
+		return
+	}
+
+	fileInfo, err := h.analyzerUsecase.GetFileContent(projectID, filePath)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if c.Query("raw") == "true" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(fileInfo.Content))
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"path":         fileInfo.Path,
+			"package_name": fileInfo.PackageName,
+			"content_hash": fileInfo.ContentHash,
+			"size":         fileInfo.Size,
+			"content":      fileInfo.Content,
+		},
+	})
+}
+
+// RescanProject re-analyzes only the files of a project that changed since the last scan
+func (h *AnalyzerHandler) RescanProject(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	summary, err := h.analyzerUsecase.RescanProject(projectID)
+	if err != nil {
+		h.logger.WithFields(map[string]interface{}{
+			"error":      err.Error(),
+			"project_id": projectID,
+		}).Error("Failed to rescan project")
+
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
-		Data:    analysis,
+		Message: "Project rescanned successfully",
+		Data:    summary,
 	})
 }
 
@@ -350,7 +628,54 @@ func (h *AnalyzerHandler) GetNode(c *gin.Context) {
 	})
 }
 
-// SearchNodes searches for nodes based on query parameters
+// GetNodeBody returns the source body of a single code node
+func (h *AnalyzerHandler) GetNodeBody(c *gin.Context) {
+	projectID := c.Param("projectId")
+	nodeID := c.Param("nodeId")
+
+	if projectID == "" || nodeID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and Node ID are required",
+		})
+		return
+	}
+
+	node, err := h.analyzerUsecase.GetNode(projectID, nodeID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if c.Query("raw") == "true" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(node.Body))
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"id":       node.ID,
+			"name":     node.Name,
+			"type":     node.Type,
+			"file":     node.File,
+			"position": node.Position,
+			"body":     node.Body,
+		},
+	})
+}
+
+// SearchNodes searches for nodes with a node query. Plain words are ranked by the full-text
+// index; fields, operators and grouping such as `type:function (pkg:usecase OR complexity>10)`
+// filter nodes structurally.
 func (h *AnalyzerHandler) SearchNodes(c *gin.Context) {
 	projectID := c.Param("projectId")
 	if projectID == "" {
@@ -380,9 +705,16 @@ func (h *AnalyzerHandler) SearchNodes(c *gin.Context) {
 		limit = 20
 	}
 
-	nodes, total, err := h.analyzerUsecase.SearchNodes(projectID, query, page, limit)
+	nodes, total, err := h.filterUsecase.QueryNodes(projectID, query, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, APIResponse{
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -402,8 +734,8 @@ func (h *AnalyzerHandler) SearchNodes(c *gin.Context) {
 	})
 }
 
-// ApplyFilters applies filters to the project nodes
-func (h *AnalyzerHandler) ApplyFilters(c *gin.Context) {
+// SearchPattern finds code matching a structural Go pattern with metavariables
+func (h *AnalyzerHandler) SearchPattern(c *gin.Context) {
 	projectID := c.Param("projectId")
 	if projectID == "" {
 		c.JSON(http.StatusBadRequest, APIResponse{
@@ -413,29 +745,25 @@ func (h *AnalyzerHandler) ApplyFilters(c *gin.Context) {
 		return
 	}
 
-	var req FilterRequest
+	var req PatternSearchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, APIResponse{
 			Success: false,
-			Error:   "Invalid filter request: " + err.Error(),
+			Error:   "Invalid pattern search request: " + err.Error(),
 		})
 		return
 	}
 
-	filters := &entity.FilterConfig{
-		NodeTypes:      req.NodeTypes,
-		FileExtensions: req.FileExtensions,
-		PackageNames:   req.PackageNames,
-		FunctionNames:  req.FunctionNames,
-		BlacklistFiles: req.BlacklistFiles,
-		BlacklistDirs:  req.BlacklistDirs,
-		MinComplexity:  req.MinComplexity,
-		MaxComplexity:  req.MaxComplexity,
-	}
-
-	filteredNodes, err := h.filterUsecase.ApplyFilters(projectID, filters)
+	result, err := h.analyzerUsecase.SearchPattern(projectID, req.Pattern, req.Constraints, req.Limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, APIResponse{
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -444,26 +772,31 @@ func (h *AnalyzerHandler) ApplyFilters(c *gin.Context) {
 
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
-		Data:    filteredNodes,
+		Message: fmt.Sprintf("%d matches", result.Total),
+		Data:    result,
 	})
 }
 
-// GetProjectStatistics retrieves project statistics
-func (h *AnalyzerHandler) GetProjectStatistics(c *gin.Context) {
+// FindReferences lists every use of the declaration of a code node
+func (h *AnalyzerHandler) FindReferences(c *gin.Context) {
 	projectID := c.Param("projectId")
-	if projectID == "" {
+	nodeID := c.Param("nodeId")
+
+	if projectID == "" || nodeID == "" {
 		c.JSON(http.StatusBadRequest, APIResponse{
 			Success: false,
-			Error:   "Project ID is required",
+			Error:   "Project ID and Node ID are required",
 		})
 		return
 	}
 
-	stats, err := h.analyzerUsecase.GetProjectStatistics(projectID)
+	result, err := h.analyzerUsecase.FindReferences(projectID, nodeID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if errors.IsNotFoundError(err) {
 			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
 		}
 
 		c.JSON(status, APIResponse{
@@ -475,28 +808,41 @@ func (h *AnalyzerHandler) GetProjectStatistics(c *gin.Context) {
 
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
-		Data:    stats,
+		Message: fmt.Sprintf("%d references", result.Total),
+		Data:    result,
 	})
 }
 
-// GetAPIStatistics retrieves statistics for a specific API endpoint
-func (h *AnalyzerHandler) GetAPIStatistics(c *gin.Context) {
+// FindDefinition resolves the identifier at a file position to its declaration
+func (h *AnalyzerHandler) FindDefinition(c *gin.Context) {
 	projectID := c.Param("projectId")
-	apiID := c.Param("apiId")
+	filePath := c.Query("file")
 
-	if projectID == "" || apiID == "" {
+	if projectID == "" || filePath == "" {
 		c.JSON(http.StatusBadRequest, APIResponse{
 			Success: false,
-			Error:   "Project ID and API ID are required",
+			Error:   "Project ID and file are required",
 		})
 		return
 	}
 
-	stats, err := h.analyzerUsecase.GetAPIStatistics(projectID, apiID)
+	line, lineErr := strconv.Atoi(c.Query("line"))
+	column, columnErr := strconv.Atoi(c.Query("col"))
+	if lineErr != nil || columnErr != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "line and col must be numbers",
+		})
+		return
+	}
+
+	definition, err := h.analyzerUsecase.FindDefinition(projectID, filePath, line, column)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if errors.IsNotFoundError(err) {
 			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
 		}
 
 		c.JSON(status, APIResponse{
@@ -508,14 +854,741 @@ func (h *AnalyzerHandler) GetAPIStatistics(c *gin.Context) {
 
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
-		Data:    stats,
+		Data:    definition,
 	})
 }
 
-// ExportAnalysis exports the complete project analysis
-func (h *AnalyzerHandler) ExportAnalysis(c *gin.Context) {
+// GetCallers returns the tree of functions calling a function node
+func (h *AnalyzerHandler) GetCallers(c *gin.Context) {
+	h.getCallTree(c, usecase.CallTreeCallers)
+}
+
+// GetCallees returns the tree of functions called by a function node
+func (h *AnalyzerHandler) GetCallees(c *gin.Context) {
+	h.getCallTree(c, usecase.CallTreeCallees)
+}
+
+func (h *AnalyzerHandler) getCallTree(c *gin.Context, direction string) {
 	projectID := c.Param("projectId")
-	if projectID == "" {
+	nodeID := c.Param("nodeId")
+
+	if projectID == "" || nodeID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and Node ID are required",
+		})
+		return
+	}
+
+	depth, _ := strconv.Atoi(c.DefaultQuery("depth", "3"))
+
+	tree, err := h.analyzerUsecase.GetCallTree(projectID, nodeID, direction, depth)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    tree,
+	})
+}
+
+// FindCallPath returns the shortest call path between two function nodes
+func (h *AnalyzerHandler) FindCallPath(c *gin.Context) {
+	projectID := c.Param("projectId")
+	from := c.Query("from")
+	to := c.Query("to")
+
+	if projectID == "" || from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID, from and to node IDs are required",
+		})
+		return
+	}
+
+	path, err := h.analyzerUsecase.FindCallPath(projectID, from, to)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("Call path of %d calls", path.Length)
+	if !path.Found {
+		message = "No call path found"
+	}
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    path,
+	})
+}
+
+// AnalyzeImpact lists the API endpoints affected by changing some code
+func (h *AnalyzerHandler) AnalyzeImpact(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	var req ImpactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid impact request: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.analyzerUsecase.AnalyzeImpact(projectID, &entity.ChangeSet{
+		NodeIDs: req.NodeIDs,
+		Ranges:  req.Ranges,
+		Diff:    req.Diff,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d endpoints impacted", len(result.Endpoints)),
+		Data:    result,
+	})
+}
+
+// PinProject protects a project from eviction by retention policies
+func (h *AnalyzerHandler) PinProject(c *gin.Context) {
+	h.setProjectPinned(c, true)
+}
+
+// UnpinProject makes a project subject to retention policies again
+func (h *AnalyzerHandler) UnpinProject(c *gin.Context) {
+	h.setProjectPinned(c, false)
+}
+
+func (h *AnalyzerHandler) setProjectPinned(c *gin.Context, pinned bool) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	if err := h.analyzerUsecase.PinProject(projectID, pinned); err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "Project pinned successfully"
+	if !pinned {
+		message = "Project unpinned successfully"
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"project_id": projectID,
+			"pinned":     pinned,
+		},
+	})
+}
+
+// GetStorageStats reports the memory held by stored analyses
+func (h *AnalyzerHandler) GetStorageStats(c *gin.Context) {
+	stats, err := h.analyzerUsecase.GetStorageStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetNodesBySymbol resolves a symbol key to the code nodes it declares
+func (h *AnalyzerHandler) GetNodesBySymbol(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	key := c.Query("key")
+	if key == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Symbol key is required",
+		})
+		return
+	}
+
+	nodes, err := h.analyzerUsecase.GetNodesBySymbol(projectID, key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"key":   key,
+			"nodes": nodes,
+		},
+	})
+}
+
+// ListProjectVersions lists the stored versions of a project
+func (h *AnalyzerHandler) ListProjectVersions(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	versions, err := h.analyzerUsecase.ListProjectVersions(projectID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"project_id": projectID,
+			"versions":   versions,
+			"total":      len(versions),
+		},
+	})
+}
+
+// DiffProjectVersions compares two versions of a project given by ?from= and ?to=.
+// Without to the current version is used, without from the version before to.
+func (h *AnalyzerHandler) DiffProjectVersions(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	from, to, ok := h.versionRange(c)
+	if !ok {
+		return
+	}
+
+	diff, err := h.analyzerUsecase.DiffProjectVersions(projectID, from, to)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    diff,
+	})
+}
+
+// DetectBreakingChanges classifies the API changes between two versions of a project.
+// The report's exit_code is 1 for breaking changes and 2 for potentially breaking ones.
+func (h *AnalyzerHandler) DetectBreakingChanges(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	from, to, ok := h.versionRange(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.analyzerUsecase.DetectBreakingChanges(projectID, from, to)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "No breaking changes"
+	if report.Breaking {
+		message = fmt.Sprintf("%d breaking changes", report.Summary.Breaking)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// versionRange reads the ?from= and ?to= versions; zero means not given
+func (h *AnalyzerHandler) versionRange(c *gin.Context) (int, int, bool) {
+	var versions [2]int
+	for i, name := range []string{"from", "to"} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		version, err := strconv.Atoi(value)
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Error:   "Invalid " + name + " version: " + value,
+			})
+			return 0, 0, false
+		}
+		versions[i] = version
+	}
+	return versions[0], versions[1], true
+}
+
+// ApplyFilters applies filters to the project nodes
+func (h *AnalyzerHandler) ApplyFilters(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	var req FilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid filter request: " + err.Error(),
+		})
+		return
+	}
+
+	filters := &entity.FilterConfig{
+		NodeTypes:      req.NodeTypes,
+		FileExtensions: req.FileExtensions,
+		PackageNames:   req.PackageNames,
+		FunctionNames:  req.FunctionNames,
+		BlacklistFiles: req.BlacklistFiles,
+		BlacklistDirs:  req.BlacklistDirs,
+		MinComplexity:  req.MinComplexity,
+		MaxComplexity:  req.MaxComplexity,
+		Generated:      req.Generated,
+		Query:          req.Query,
+	}
+
+	filteredNodes, err := h.filterUsecase.ApplyFilters(projectID, filters)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    filteredNodes,
+	})
+}
+
+// ListSavedQueries lists the saved node queries of a project
+func (h *AnalyzerHandler) ListSavedQueries(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	queries, err := h.filterUsecase.ListSavedQueries(projectID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    queries,
+	})
+}
+
+// CreateSavedQuery validates a node query and saves it under a name
+func (h *AnalyzerHandler) CreateSavedQuery(c *gin.Context) {
+	h.storeSavedQuery(c, "")
+}
+
+// UpdateSavedQuery replaces the name, query and description of a saved query
+func (h *AnalyzerHandler) UpdateSavedQuery(c *gin.Context) {
+	queryID := c.Param("queryId")
+	if queryID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Query ID is required",
+		})
+		return
+	}
+	h.storeSavedQuery(c, queryID)
+}
+
+func (h *AnalyzerHandler) storeSavedQuery(c *gin.Context, queryID string) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	var req SavedQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid saved query request: " + err.Error(),
+		})
+		return
+	}
+
+	query, err := h.filterUsecase.SaveQuery(projectID, &entity.SavedQuery{
+		ID:          queryID,
+		Name:        req.Name,
+		Query:       req.Query,
+		Description: req.Description,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusOK
+	message := "Saved query updated"
+	if queryID == "" {
+		status = http.StatusCreated
+		message = "Saved query created"
+	}
+	c.JSON(status, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    query,
+	})
+}
+
+// GetSavedQuery retrieves a saved node query
+func (h *AnalyzerHandler) GetSavedQuery(c *gin.Context) {
+	projectID := c.Param("projectId")
+	queryID := c.Param("queryId")
+	if projectID == "" || queryID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and Query ID are required",
+		})
+		return
+	}
+
+	query, err := h.filterUsecase.GetSavedQuery(projectID, queryID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    query,
+	})
+}
+
+// DeleteSavedQuery removes a saved node query
+func (h *AnalyzerHandler) DeleteSavedQuery(c *gin.Context) {
+	projectID := c.Param("projectId")
+	queryID := c.Param("queryId")
+	if projectID == "" || queryID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and Query ID are required",
+		})
+		return
+	}
+
+	if err := h.filterUsecase.DeleteSavedQuery(projectID, queryID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Saved query deleted",
+	})
+}
+
+// RunSavedQuery runs a saved node query against the current nodes of the project
+func (h *AnalyzerHandler) RunSavedQuery(c *gin.Context) {
+	projectID := c.Param("projectId")
+	queryID := c.Param("queryId")
+	if projectID == "" || queryID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and Query ID are required",
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query, nodes, total, err := h.filterUsecase.RunSavedQuery(projectID, queryID, page, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"nodes":       nodes,
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"query":       query,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetProjectStatistics retrieves project statistics
+func (h *AnalyzerHandler) GetProjectStatistics(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	stats, err := h.analyzerUsecase.GetProjectStatistics(projectID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetAPIStatistics retrieves statistics for a specific API endpoint
+func (h *AnalyzerHandler) GetAPIStatistics(c *gin.Context) {
+	projectID := c.Param("projectId")
+	apiID := c.Param("apiId")
+
+	if projectID == "" || apiID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID and API ID are required",
+		})
+		return
+	}
+
+	stats, err := h.analyzerUsecase.GetAPIStatistics(projectID, apiID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// ExportAnalysis exports the complete project analysis
+func (h *AnalyzerHandler) ExportAnalysis(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
 		c.JSON(http.StatusBadRequest, APIResponse{
 			Success: false,
 			Error:   "Project ID is required",
@@ -530,6 +1603,8 @@ func (h *AnalyzerHandler) ExportAnalysis(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if errors.IsNotFoundError(err) {
 			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
 		}
 
 		c.JSON(status, APIResponse{
@@ -550,6 +1625,8 @@ func (h *AnalyzerHandler) ExportAnalysis(c *gin.Context) {
 		c.Header("Content-Type", "application/x-yaml")
 	case "xml":
 		c.Header("Content-Type", "application/xml")
+	case "html":
+		c.Header("Content-Type", "text/html; charset=utf-8")
 	default:
 		c.Header("Content-Type", "application/octet-stream")
 	}
@@ -577,6 +1654,8 @@ func (h *AnalyzerHandler) ExportAPIAnalysis(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if errors.IsNotFoundError(err) {
 			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
 		}
 
 		c.JSON(status, APIResponse{
@@ -604,6 +1683,124 @@ func (h *AnalyzerHandler) ExportAPIAnalysis(c *gin.Context) {
 	c.String(http.StatusOK, exported)
 }
 
+// GetAnalysisSchema serves the XSD of XML analysis exports
+func (h *AnalyzerHandler) GetAnalysisSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/xml", exporter.AnalysisXSD)
+}
+
+// ExportAPIDocs exports a Markdown API reference of the project, as one document
+// or as a zip archive of pages per router group
+func (h *AnalyzerHandler) ExportAPIDocs(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", usecase.DocsFormatMarkdown))
+
+	data, err := h.analyzerUsecase.ExportAPIDocs(projectID, format)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if format == usecase.DocsFormatZip {
+		c.Header("Content-Disposition", "attachment; filename=api_docs.zip")
+		c.Data(http.StatusOK, "application/zip", data)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=api_docs.md")
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", data)
+}
+
+// ExportRequestCollection exports the project endpoints as a Postman collection
+// or a .http file, with the base_url query parameter as base URL
+func (h *AnalyzerHandler) ExportRequestCollection(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", usecase.CollectionFormatPostman))
+	baseURL := c.DefaultQuery("base_url", usecase.DefaultCollectionBaseURL)
+
+	data, err := h.analyzerUsecase.ExportRequestCollection(projectID, format, baseURL)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if format == usecase.CollectionFormatPostman {
+		c.Header("Content-Disposition", "attachment; filename=postman_collection.json")
+		c.Data(http.StatusOK, "application/json", data)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=requests."+format)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+}
+
+// ExportClientSDK exports typed Go and TypeScript clients of the project API as a
+// zip archive, with the package query parameter as Go package name
+func (h *AnalyzerHandler) ExportClientSDK(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	packageName := c.DefaultQuery("package", usecase.DefaultClientPackage)
+
+	data, err := h.analyzerUsecase.ExportClientSDK(projectID, packageName)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=client_sdk.zip")
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
 // GetDependencyGraph retrieves the dependency graph for the project
 func (h *AnalyzerHandler) GetDependencyGraph(c *gin.Context) {
 	projectID := c.Param("projectId")
@@ -615,6 +1812,11 @@ func (h *AnalyzerHandler) GetDependencyGraph(c *gin.Context) {
 		return
 	}
 
+	if isGraphExport(c) {
+		h.exportDependencyGraph(c, projectID, "")
+		return
+	}
+
 	graph, err := h.analyzerUsecase.GetDependencyGraph(projectID)
 	if err != nil {
 		status := http.StatusInternalServerError
@@ -635,6 +1837,37 @@ func (h *AnalyzerHandler) GetDependencyGraph(c *gin.Context) {
 	})
 }
 
+// GetModuleDependencies retrieves the Go modules and module requirements of the project
+func (h *AnalyzerHandler) GetModuleDependencies(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Project ID is required",
+		})
+		return
+	}
+
+	modules, err := h.analyzerUsecase.GetModuleDependencies(projectID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    modules,
+	})
+}
+
 // GetAPIDependencies retrieves dependencies for a specific API endpoint
 func (h *AnalyzerHandler) GetAPIDependencies(c *gin.Context) {
 	projectID := c.Param("projectId")
@@ -648,6 +1881,11 @@ func (h *AnalyzerHandler) GetAPIDependencies(c *gin.Context) {
 		return
 	}
 
+	if isGraphExport(c) {
+		h.exportDependencyGraph(c, projectID, apiID)
+		return
+	}
+
 	dependencies, err := h.analyzerUsecase.GetAPIDependencies(projectID, apiID)
 	if err != nil {
 		status := http.StatusInternalServerError
@@ -667,3 +1905,83 @@ func (h *AnalyzerHandler) GetAPIDependencies(c *gin.Context) {
 		Data:    dependencies,
 	})
 }
+
+// isGraphExport reports whether a dependencies request asks for a rendered or
+// shaped graph rather than the stored one
+func isGraphExport(c *gin.Context) bool {
+	for _, param := range []string{"format", "kinds", "max_nodes", "cluster"} {
+		if c.Query(param) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// exportDependencyGraph serves a dependency graph as DOT, GraphML, Mermaid or
+// Cytoscape JSON, or as filtered JSON for format=json
+func (h *AnalyzerHandler) exportDependencyGraph(c *gin.Context, projectID, apiID string) {
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && !exporter.IsGraphFormat(format) {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "format must be one of json, dot, graphml, mermaid or cytoscape",
+		})
+		return
+	}
+
+	options := &exporter.GraphOptions{ClusterByPackage: c.DefaultQuery("cluster", "true") != "false"}
+	if kinds := c.Query("kinds"); kinds != "" {
+		for _, kind := range strings.Split(kinds, ",") {
+			if kind = strings.TrimSpace(kind); kind != "" {
+				options.EdgeKinds = append(options.EdgeKinds, kind)
+			}
+		}
+	}
+	if format != "json" {
+		options.MaxNodes = exporter.DefaultMaxNodes
+	}
+	if value := c.Query("max_nodes"); value != "" {
+		maxNodes, err := strconv.Atoi(value)
+		if err != nil || maxNodes < 0 {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Error:   "max_nodes must be a non-negative number",
+			})
+			return
+		}
+		options.MaxNodes = maxNodes
+	}
+
+	var data interface{}
+	var exported []byte
+	var err error
+	if format == "json" {
+		data, err = h.analyzerUsecase.ShapeDependencyGraph(projectID, apiID, options)
+	} else {
+		exported, err = h.analyzerUsecase.ExportDependencyGraph(projectID, apiID, format, options)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, APIResponse{
+			Success: true,
+			Data:    data,
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, exporter.GraphContentType(format), exported)
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/usecase"
+	"goapianalyzer/internal/infrastructure/logger"
+	"goapianalyzer/pkg/errors"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// sseKeepAlive is how often an idle event stream sends a comment to keep proxies from closing it
+const sseKeepAlive = 15 * time.Second
+
+type JobHandler struct {
+	scanJobUsecase *usecase.ScanJobUsecase
+	logger         logger.Logger
+}
+
+func NewJobHandler(scanJobUsecase *usecase.ScanJobUsecase) *JobHandler {
+	return &JobHandler{
+		scanJobUsecase: scanJobUsecase,
+		logger:         logger.GetLogger(),
+	}
+}
+
+// ListJobs lists queued, running and recently finished scan jobs
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	jobs := h.scanJobUsecase.ListJobs()
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"jobs":  jobs,
+			"total": len(jobs),
+		},
+	})
+}
+
+// GetJob reports the phase, progress and errors of a scan job
+func (h *JobHandler) GetJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Job ID is required",
+		})
+		return
+	}
+
+	job, err := h.scanJobUsecase.GetJob(jobID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    job,
+	})
+}
+
+// CancelJob cancels a queued or running scan job
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Job ID is required",
+		})
+		return
+	}
+
+	job, err := h.scanJobUsecase.CancelJob(jobID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		} else if errors.IsValidationError(err) {
+			status = http.StatusConflict
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Running jobs stop at the next file or phase boundary
+	message := "Scan job cancelled"
+	if !job.Finished() {
+		message = "Scan job cancellation requested"
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    job,
+	})
+}
+
+// StreamJobEvents publishes the events of a scan job as Server-Sent Events.
+// Events after the Last-Event-ID header (or ?last_event_id=) are replayed first.
+func (h *JobHandler) StreamJobEvents(c *gin.Context) {
+	lastEventID := h.lastEventID(c, c.GetHeader("Last-Event-ID"))
+
+	replay, events, unsubscribe, ok := h.subscribe(c, lastEventID)
+	if !ok {
+		return
+	}
+	defer unsubscribe()
+
+	// Streams outlive the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.WithError(err).Debug("Failed to clear write deadline for event stream")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, event := range replay {
+		c.Render(-1, sseEvent(event))
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, open := <-events:
+			if !open {
+				return false
+			}
+			c.Render(-1, sseEvent(event))
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// JobWebSocket publishes the events of a scan job as JSON messages over a WebSocket.
+// Events after ?last_event_id= are replayed first.
+func (h *JobHandler) JobWebSocket(c *gin.Context) {
+	lastEventID := h.lastEventID(c, "")
+
+	replay, events, unsubscribe, ok := h.subscribe(c, lastEventID)
+	if !ok {
+		return
+	}
+	defer unsubscribe()
+
+	server := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// Connections outlive the server write timeout
+			_ = ws.SetDeadline(time.Time{})
+
+			// Clients only listen; a read error means they went away
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard []byte
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			for _, event := range replay {
+				if err := websocket.JSON.Send(ws, event); err != nil {
+					return
+				}
+			}
+
+			for {
+				select {
+				case event, open := <-events:
+					if !open {
+						return
+					}
+					if err := websocket.JSON.Send(ws, event); err != nil {
+						return
+					}
+				case <-closed:
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *JobHandler) subscribe(c *gin.Context, lastEventID int64) ([]entity.ScanEvent, <-chan entity.ScanEvent, func(), bool) {
+	jobID := c.Param("jobId")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Job ID is required",
+		})
+		return nil, nil, nil, false
+	}
+
+	replay, events, unsubscribe, err := h.scanJobUsecase.SubscribeJob(jobID, lastEventID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFoundError(err) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return nil, nil, nil, false
+	}
+
+	return replay, events, unsubscribe, true
+}
+
+// lastEventID reads the ID of the last event a client saw, preferring the header value
+func (h *JobHandler) lastEventID(c *gin.Context, header string) int64 {
+	value := header
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	id, _ := strconv.ParseInt(value, 10, 64)
+	return id
+}
+
+func sseEvent(event entity.ScanEvent) sse.Event {
+	return sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: event.Type,
+		Data:  event,
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const streamBufferSize = 64 * 1024
+
+// jsonStream writes a JSON document piece by piece so that large analyses are
+// sent while they are encoded instead of being buffered in full
+type jsonStream struct {
+	w     *bufio.Writer
+	first []bool // whether the next member of each open object is its first
+	err   error
+}
+
+func newJSONStream(w io.Writer) *jsonStream {
+	return &jsonStream{w: bufio.NewWriterSize(w, streamBufferSize)}
+}
+
+func (s *jsonStream) beginObject() {
+	s.raw("{")
+	s.first = append(s.first, true)
+}
+
+func (s *jsonStream) endObject() {
+	s.first = s.first[:len(s.first)-1]
+	s.raw("}")
+}
+
+func (s *jsonStream) key(name string) {
+	if depth := len(s.first); depth > 0 {
+		if !s.first[depth-1] {
+			s.raw(",")
+		}
+		s.first[depth-1] = false
+	}
+	s.value(name)
+	s.raw(":")
+}
+
+func (s *jsonStream) value(v interface{}) {
+	if s.err != nil {
+		return
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	_, s.err = s.w.Write(encoded)
+}
+
+func (s *jsonStream) field(name string, v interface{}) {
+	s.key(name)
+	s.value(v)
+}
+
+func (s *jsonStream) raw(text string) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.WriteString(text)
+}
+
+// structFields writes the JSON members of a struct one at a time. Only names in
+// selected are written unless it is empty; map members named in streamed are
+// written entry by entry in key order.
+func (s *jsonStream) structFields(v interface{}, selected, streamed map[string]bool) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		fieldType := valueType.Field(i)
+		fieldValue := value.Field(i)
+
+		if fieldType.Anonymous {
+			s.structFields(fieldValue.Interface(), selected, streamed)
+			continue
+		}
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := jsonFieldName(fieldType)
+		if name == "-" || (len(selected) > 0 && !selected[name]) || (omitEmpty && fieldValue.IsZero()) {
+			continue
+		}
+
+		if streamed[name] && fieldValue.Kind() == reflect.Map && fieldValue.Type().Key().Kind() == reflect.String {
+			s.key(name)
+			s.mapEntries(fieldValue)
+			continue
+		}
+
+		s.field(name, fieldValue.Interface())
+	}
+}
+
+func (s *jsonStream) mapEntries(value reflect.Value) {
+	if value.IsNil() {
+		s.raw("null")
+		return
+	}
+
+	keys := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	s.beginObject()
+	for _, key := range keys {
+		s.field(key, value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())).Interface())
+	}
+	s.endObject()
+}
+
+// close flushes buffered output and reports the first write or encoding error
+func (s *jsonStream) close() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
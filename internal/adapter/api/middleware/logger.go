@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net/http"
 	"time"
 
 	"goapianalyzer/internal/infrastructure/logger"
@@ -12,16 +13,30 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxLoggedBodySize bounds how much of a response body is kept for the log,
+// so that large or streamed responses are not buffered in memory
+const maxLoggedBodySize = 4 * 1024
+
 type responseBodyWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
 }
 
 func (r responseBodyWriter) Write(b []byte) (int, error) {
-	r.body.Write(b)
+	if remaining := maxLoggedBodySize - r.body.Len(); remaining > 0 {
+		if len(b) < remaining {
+			remaining = len(b)
+		}
+		r.body.Write(b[:remaining])
+	}
 	return r.ResponseWriter.Write(b)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r responseBodyWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // RequestLoggerMiddleware logs incoming HTTP requests and responses
 func RequestLoggerMiddleware() gin.HandlerFunc {
 	log := logger.GetLogger()
@@ -69,6 +84,9 @@ func RequestLoggerMiddleware() gin.HandlerFunc {
 
 		// Get response body
 		responseBody := w.body.String()
+		if c.Writer.Size() > w.body.Len() {
+			responseBody += "...(truncated)"
+		}
 
 		// Log response
 		logFields := map[string]interface{}{
@@ -14,12 +14,14 @@ type Router struct {
 	config          *config.Config
 	analyzerUsecase *usecase.AnalyzerUsecase
 	filterUsecase   *usecase.FilterUsecase
+	scanJobUsecase  *usecase.ScanJobUsecase
 }
 
 func NewRouter(
 	config *config.Config,
 	analyzerUsecase *usecase.AnalyzerUsecase,
 	filterUsecase *usecase.FilterUsecase,
+	scanJobUsecase *usecase.ScanJobUsecase,
 ) *Router {
 	// Set Gin mode based on environment
 	if config.Environment == "production" {
@@ -35,6 +37,7 @@ func NewRouter(
 		config:          config,
 		analyzerUsecase: analyzerUsecase,
 		filterUsecase:   filterUsecase,
+		scanJobUsecase:  scanJobUsecase,
 	}
 }
 
@@ -88,14 +91,30 @@ func (r *Router) setupRoutes() {
 }
 
 func (r *Router) setupAnalyzerRoutes(rg *gin.RouterGroup) {
-	analyzerHandler := handler.NewAnalyzerHandler(r.analyzerUsecase, r.filterUsecase)
+	analyzerHandler := handler.NewAnalyzerHandler(r.analyzerUsecase, r.filterUsecase, r.scanJobUsecase)
+	jobHandler := handler.NewJobHandler(r.scanJobUsecase)
 
 	analyzer := rg.Group("/analyzer")
 	{
 		// Project analysis endpoints
 		analyzer.POST("/scan", analyzerHandler.ScanProject)
+		analyzer.GET("/projects", analyzerHandler.ListProjects)
 		analyzer.GET("/projects/:projectId", analyzerHandler.GetProjectAnalysis)
 		analyzer.DELETE("/projects/:projectId", analyzerHandler.DeleteProjectAnalysis)
+		analyzer.POST("/projects/:projectId/rescan", analyzerHandler.RescanProject)
+		analyzer.PUT("/projects/:projectId/pin", analyzerHandler.PinProject)
+		analyzer.DELETE("/projects/:projectId/pin", analyzerHandler.UnpinProject)
+		analyzer.GET("/storage", analyzerHandler.GetStorageStats)
+		analyzer.GET("/projects/:projectId/versions", analyzerHandler.ListProjectVersions)
+		analyzer.GET("/projects/:projectId/diff", analyzerHandler.DiffProjectVersions)
+		analyzer.GET("/projects/:projectId/breaking-changes", analyzerHandler.DetectBreakingChanges)
+
+		// Scan jobs
+		analyzer.GET("/jobs", jobHandler.ListJobs)
+		analyzer.GET("/jobs/:jobId", jobHandler.GetJob)
+		analyzer.DELETE("/jobs/:jobId", jobHandler.CancelJob)
+		analyzer.GET("/jobs/:jobId/events", jobHandler.StreamJobEvents)
+		analyzer.GET("/jobs/:jobId/ws", jobHandler.JobWebSocket)
 
 		// API endpoints discovery
 		analyzer.GET("/projects/:projectId/apis", analyzerHandler.ListAPIEndpoints)
@@ -105,10 +124,26 @@ func (r *Router) setupAnalyzerRoutes(rg *gin.RouterGroup) {
 		analyzer.GET("/projects/:projectId/apis/:apiId/nodes", analyzerHandler.GetAPINodes)
 		analyzer.GET("/projects/:projectId/nodes", analyzerHandler.GetAllNodes)
 		analyzer.GET("/projects/:projectId/nodes/:nodeId", analyzerHandler.GetNode)
+		analyzer.GET("/projects/:projectId/nodes/:nodeId/body", analyzerHandler.GetNodeBody)
+		analyzer.GET("/projects/:projectId/nodes/:nodeId/references", analyzerHandler.FindReferences)
+		analyzer.GET("/projects/:projectId/definition", analyzerHandler.FindDefinition)
+		analyzer.GET("/projects/:projectId/nodes/:nodeId/callers", analyzerHandler.GetCallers)
+		analyzer.GET("/projects/:projectId/nodes/:nodeId/callees", analyzerHandler.GetCallees)
+		analyzer.GET("/projects/:projectId/path", analyzerHandler.FindCallPath)
+		analyzer.POST("/projects/:projectId/impact", analyzerHandler.AnalyzeImpact)
+		analyzer.GET("/projects/:projectId/files/content", analyzerHandler.GetFileContent)
+		analyzer.GET("/projects/:projectId/symbols", analyzerHandler.GetNodesBySymbol)
 
 		// Filter and search endpoints
 		analyzer.GET("/projects/:projectId/nodes/search", analyzerHandler.SearchNodes)
 		analyzer.POST("/projects/:projectId/filters", analyzerHandler.ApplyFilters)
+		analyzer.POST("/projects/:projectId/patterns/search", analyzerHandler.SearchPattern)
+		analyzer.GET("/projects/:projectId/queries", analyzerHandler.ListSavedQueries)
+		analyzer.POST("/projects/:projectId/queries", analyzerHandler.CreateSavedQuery)
+		analyzer.GET("/projects/:projectId/queries/:queryId", analyzerHandler.GetSavedQuery)
+		analyzer.PUT("/projects/:projectId/queries/:queryId", analyzerHandler.UpdateSavedQuery)
+		analyzer.DELETE("/projects/:projectId/queries/:queryId", analyzerHandler.DeleteSavedQuery)
+		analyzer.GET("/projects/:projectId/queries/:queryId/results", analyzerHandler.RunSavedQuery)
 
 		// Statistics and metrics
 		analyzer.GET("/projects/:projectId/stats", analyzerHandler.GetProjectStatistics)
@@ -117,9 +152,14 @@ func (r *Router) setupAnalyzerRoutes(rg *gin.RouterGroup) {
 		// Export endpoints
 		analyzer.GET("/projects/:projectId/export", analyzerHandler.ExportAnalysis)
 		analyzer.GET("/projects/:projectId/apis/:apiId/export", analyzerHandler.ExportAPIAnalysis)
+		analyzer.GET("/projects/:projectId/docs", analyzerHandler.ExportAPIDocs)
+		analyzer.GET("/projects/:projectId/collection", analyzerHandler.ExportRequestCollection)
+		analyzer.GET("/projects/:projectId/sdk", analyzerHandler.ExportClientSDK)
+		analyzer.GET("/schemas/analysis.xsd", analyzerHandler.GetAnalysisSchema)
 
 		// Dependency analysis
 		analyzer.GET("/projects/:projectId/dependencies", analyzerHandler.GetDependencyGraph)
 		analyzer.GET("/projects/:projectId/apis/:apiId/dependencies", analyzerHandler.GetAPIDependencies)
+		analyzer.GET("/projects/:projectId/modules", analyzerHandler.GetModuleDependencies)
 	}
 }
@@ -0,0 +1,979 @@
+package exporter
+
+import (
+	_ "embed"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// AnalysisNamespace is the XML namespace of exported analyses, described by AnalysisXSD
+const AnalysisNamespace = "urn:goapianalyzer:analysis:1"
+
+// AnalysisXSD is the published schema of MarshalAnalysisXML and MarshalAPIAnalysisXML output
+//
+//go:embed schema/analysis.xsd
+var AnalysisXSD []byte
+
+// Metadata value types
+const (
+	metadataString = "string"
+	metadataBool   = "bool"
+	metadataInt    = "int"
+	metadataFloat  = "float"
+	metadataJSON   = "json" // Lists and objects, kept as JSON text
+)
+
+// The XML document model. Maps of the entities become lists sorted by key and
+// source text is written as CDATA, so that exports are deterministic and readable.
+
+type xmlAnalysis struct {
+	XMLName            xml.Name               `xml:"urn:goapianalyzer:analysis:1 analysis"`
+	ID                 string                 `xml:"id,attr"`
+	Version            int                    `xml:"version,attr"`
+	ProjectPath        string                 `xml:"project_path,attr"`
+	Pinned             bool                   `xml:"pinned,attr"`
+	ScanDurationMs     int64                  `xml:"scan_duration_ms,attr"`
+	CreatedAt          time.Time              `xml:"created_at,attr"`
+	UpdatedAt          time.Time              `xml:"updated_at,attr"`
+	BuildContext       *xmlBuildContext       `xml:"build_context"`
+	Config             *xmlAnalysisConfig     `xml:"config"`
+	Modules            []*xmlModule           `xml:"modules>module,omitempty"`
+	Workspace          *xmlWorkspace          `xml:"workspace"`
+	ModuleDependencies []*xmlModuleDependency `xml:"module_dependencies>module_dependency,omitempty"`
+	Packages           []*xmlPackage          `xml:"packages>package,omitempty"`
+	Files              []*xmlFile             `xml:"files>file,omitempty"`
+	APIEndpoints       []*xmlEndpoint         `xml:"api_endpoints>endpoint,omitempty"`
+	DependencyGraph    *xmlDependencyGraph    `xml:"dependency_graph"`
+}
+
+type xmlAPIAnalysis struct {
+	XMLName  xml.Name       `xml:"urn:goapianalyzer:analysis:1 api_analysis"`
+	Endpoint *xmlEndpoint   `xml:"endpoint"`
+	Nodes    []*xmlCodeNode `xml:"nodes>node,omitempty"`
+}
+
+// xmlText is an element whose text is written as CDATA
+type xmlText struct {
+	Text string `xml:",cdata"`
+}
+
+type xmlBuildContext struct {
+	GOOS           string   `xml:"goos,attr,omitempty"`
+	GOARCH         string   `xml:"goarch,attr,omitempty"`
+	CgoEnabled     bool     `xml:"cgo_enabled,attr"`
+	AllConstraints bool     `xml:"all_constraints,attr"`
+	Tags           []string `xml:"tag"`
+}
+
+type xmlAnalysisConfig struct {
+	IncludeVendor    bool             `xml:"include_vendor,attr"`
+	IncludeTestFile  bool             `xml:"include_test_file,attr"`
+	ExcludeGenerated bool             `xml:"exclude_generated,attr"`
+	BlacklistFiles   []string         `xml:"blacklist_file"`
+	BlacklistDirs    []string         `xml:"blacklist_dir"`
+	WhitelistFiles   []string         `xml:"whitelist_file"`
+	WhitelistDirs    []string         `xml:"whitelist_dir"`
+	BuildContext     *xmlBuildContext `xml:"build_context"`
+}
+
+type xmlModule struct {
+	Path      string                  `xml:"path,attr"`
+	Dir       string                  `xml:"dir,attr"`
+	GoVersion string                  `xml:"go_version,attr,omitempty"`
+	Toolchain string                  `xml:"toolchain,attr,omitempty"`
+	Workspace bool                    `xml:"workspace,attr"`
+	Requires  []*xmlModuleRequirement `xml:"require"`
+	Replaces  []*xmlModuleReplace     `xml:"replace"`
+}
+
+type xmlModuleRequirement struct {
+	Path     string `xml:"path,attr"`
+	Version  string `xml:"version,attr"`
+	Indirect bool   `xml:"indirect,attr"`
+}
+
+type xmlModuleReplace struct {
+	OldPath    string `xml:"old_path,attr"`
+	OldVersion string `xml:"old_version,attr,omitempty"`
+	NewPath    string `xml:"new_path,attr"`
+	NewVersion string `xml:"new_version,attr,omitempty"`
+}
+
+type xmlWorkspace struct {
+	GoVersion string              `xml:"go_version,attr,omitempty"`
+	Uses      []string            `xml:"use"`
+	Replaces  []*xmlModuleReplace `xml:"replace"`
+}
+
+type xmlModuleDependency struct {
+	Path       string            `xml:"path,attr"`
+	Version    string            `xml:"version,attr"`
+	Indirect   bool              `xml:"indirect,attr"`
+	Sum        string            `xml:"sum,attr,omitempty"`
+	GoModSum   string            `xml:"go_mod_sum,attr,omitempty"`
+	Replace    *xmlModuleReplace `xml:"replace"`
+	RequiredBy []string          `xml:"required_by"`
+}
+
+type xmlPackage struct {
+	Key        string   `xml:"key,attr"`
+	Name       string   `xml:"name,attr"`
+	Path       string   `xml:"path,attr"`
+	ImportPath string   `xml:"import_path,attr"`
+	Module     string   `xml:"module,attr,omitempty"`
+	Files      []string `xml:"file"`
+}
+
+type xmlFile struct {
+	Key             string          `xml:"key,attr"`
+	Path            string          `xml:"path,attr"`
+	AbsolutePath    string          `xml:"absolute_path,attr"`
+	PackageName     string          `xml:"package_name,attr"`
+	ImportPath      string          `xml:"import_path,attr"`
+	ContentHash     string          `xml:"content_hash,attr"`
+	Size            int64           `xml:"size,attr"`
+	ModTime         time.Time       `xml:"mod_time,attr"`
+	Generated       bool            `xml:"generated,attr"`
+	BuildConstraint string          `xml:"build_constraint,attr,omitempty"`
+	Imports         []*xmlImport    `xml:"imports>import,omitempty"`
+	Functions       []*xmlFunction  `xml:"functions>function,omitempty"`
+	Types           []*xmlTypeDef   `xml:"types>type,omitempty"`
+	Variables       []*xmlValue     `xml:"variables>variable,omitempty"`
+	Constants       []*xmlValue     `xml:"constants>constant,omitempty"`
+	Interfaces      []*xmlInterface `xml:"interfaces>interface,omitempty"`
+	Structs         []*xmlStruct    `xml:"structs>struct,omitempty"`
+	Content         *xmlText        `xml:"content"`
+}
+
+// xmlImport merges an import path with its details, when the file has them
+type xmlImport struct {
+	Path   string `xml:"path,attr"`
+	Name   string `xml:"name,attr,omitempty"`
+	Kind   string `xml:"kind,attr,omitempty"`
+	Module string `xml:"module,attr,omitempty"`
+}
+
+type xmlPosition struct {
+	Line   int `xml:"line,attr"`
+	Column int `xml:"column,attr"`
+	Offset int `xml:"offset,attr"`
+}
+
+type xmlParameter struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type xmlCall struct {
+	Name      string       `xml:"name,attr"`
+	Position  *xmlPosition `xml:"position"`
+	Arguments []string     `xml:"argument"`
+}
+
+type xmlFunction struct {
+	Name       string          `xml:"name,attr"`
+	Receiver   string          `xml:"receiver,attr,omitempty"`
+	IsMethod   bool            `xml:"is_method,attr"`
+	Complexity int             `xml:"complexity,attr"`
+	Position   *xmlPosition    `xml:"position"`
+	Parameters []*xmlParameter `xml:"parameters>parameter,omitempty"`
+	Returns    []*xmlParameter `xml:"returns>return,omitempty"`
+	CallsTo    []*xmlCall      `xml:"calls>call,omitempty"`
+	UsedTypes  []string        `xml:"used_types>type,omitempty"`
+	Doc        *xmlText        `xml:"doc"`
+	Body       *xmlText        `xml:"body"`
+}
+
+type xmlTypeDef struct {
+	Name string   `xml:"name,attr"`
+	Type string   `xml:"type,attr"`
+	Doc  *xmlText `xml:"doc"`
+	Body *xmlText `xml:"body"`
+}
+
+type xmlValue struct {
+	Name  string   `xml:"name,attr"`
+	Type  string   `xml:"type,attr"`
+	Value string   `xml:"value,attr,omitempty"`
+	Body  *xmlText `xml:"body"`
+}
+
+type xmlInterfaceMethod struct {
+	Name       string          `xml:"name,attr"`
+	Parameters []*xmlParameter `xml:"parameters>parameter,omitempty"`
+	Returns    []*xmlParameter `xml:"returns>return,omitempty"`
+}
+
+type xmlInterface struct {
+	Name    string                `xml:"name,attr"`
+	Methods []*xmlInterfaceMethod `xml:"methods>method,omitempty"`
+	Doc     *xmlText              `xml:"doc"`
+	Body    *xmlText              `xml:"body"`
+}
+
+type xmlStructField struct {
+	Name     string `xml:"name,attr"`
+	Type     string `xml:"type,attr"`
+	Tag      string `xml:"tag,attr,omitempty"`
+	Embedded bool   `xml:"embedded,attr"`
+}
+
+type xmlStruct struct {
+	Name   string            `xml:"name,attr"`
+	Fields []*xmlStructField `xml:"fields>field,omitempty"`
+	Doc    *xmlText          `xml:"doc"`
+	Body   *xmlText          `xml:"body"`
+}
+
+type xmlEndpoint struct {
+	ID            string              `xml:"id,attr"`
+	Method        string              `xml:"method,attr"`
+	Path          string              `xml:"path,attr"`
+	File          string              `xml:"file,attr"`
+	Handler       string              `xml:"handler,attr,omitempty"`
+	HandlerSymbol string              `xml:"handler_symbol,attr,omitempty"`
+	RequestType   string              `xml:"request_type,attr,omitempty"`
+	Middlewares   []string            `xml:"middlewares>middleware,omitempty"`
+	PathParams    []string            `xml:"path_params>param,omitempty"`
+	QueryParams   []*xmlEndpointParam `xml:"query_params>param,omitempty"`
+	ResponseTypes []string            `xml:"response_types>type,omitempty"`
+}
+
+type xmlEndpointParam struct {
+	Name     string `xml:"name,attr"`
+	Required bool   `xml:"required,attr"`
+}
+
+type xmlCodeNode struct {
+	ID              string         `xml:"id,attr"`
+	Name            string         `xml:"name,attr"`
+	Type            string         `xml:"type,attr"`
+	File            string         `xml:"file,attr"`
+	Package         string         `xml:"package,attr"`
+	PackagePath     string         `xml:"package_path,attr,omitempty"`
+	SymbolKey       string         `xml:"symbol_key,attr"`
+	Generated       bool           `xml:"generated,attr"`
+	BuildConstraint string         `xml:"build_constraint,attr,omitempty"`
+	Position        *xmlPosition   `xml:"position"`
+	Metadata        []*xmlMetadata `xml:"metadata>entry,omitempty"`
+	Doc             *xmlText       `xml:"doc"`
+	Body            *xmlText       `xml:"body"`
+}
+
+type xmlMetadata struct {
+	Key   string `xml:"key,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",cdata"`
+}
+
+type xmlDependencyGraph struct {
+	Nodes        []*xmlDependencyNode `xml:"nodes>node,omitempty"`
+	Dependencies []*xmlDependency     `xml:"dependencies>dependency,omitempty"`
+}
+
+type xmlDependencyNode struct {
+	ID      string `xml:"id,attr"`
+	Name    string `xml:"name,attr"`
+	Type    string `xml:"type,attr"`
+	File    string `xml:"file,attr,omitempty"`
+	Package string `xml:"package,attr,omitempty"`
+}
+
+type xmlDependency struct {
+	From     string `xml:"from,attr"`
+	To       string `xml:"to,attr"`
+	Type     string `xml:"type,attr"`
+	Strength int    `xml:"strength,attr"`
+}
+
+// MarshalAnalysisXML renders a project analysis as XML valid against AnalysisXSD
+func MarshalAnalysisXML(analysis *entity.ProjectAnalysis) ([]byte, error) {
+	document := &xmlAnalysis{
+		ID:                 analysis.ID,
+		Version:            analysis.Version,
+		ProjectPath:        analysis.ProjectPath,
+		Pinned:             analysis.Pinned,
+		ScanDurationMs:     analysis.ScanDurationMs,
+		CreatedAt:          analysis.CreatedAt,
+		UpdatedAt:          analysis.UpdatedAt,
+		BuildContext:       toXMLBuildContext(analysis.BuildContext),
+		Workspace:          toXMLWorkspace(analysis.Workspace),
+		DependencyGraph:    toXMLDependencyGraph(analysis.DependencyGraph),
+		Modules:            make([]*xmlModule, 0, len(analysis.Modules)),
+		ModuleDependencies: make([]*xmlModuleDependency, 0, len(analysis.ModuleDependencies)),
+	}
+	if config := analysis.Config; config != nil {
+		document.Config = &xmlAnalysisConfig{
+			IncludeVendor:    config.IncludeVendor,
+			IncludeTestFile:  config.IncludeTestFile,
+			ExcludeGenerated: config.ExcludeGenerated,
+			BlacklistFiles:   config.BlacklistFiles,
+			BlacklistDirs:    config.BlacklistDirs,
+			WhitelistFiles:   config.WhitelistFiles,
+			WhitelistDirs:    config.WhitelistDirs,
+			BuildContext:     toXMLBuildContext(config.BuildContext),
+		}
+	}
+	for _, module := range analysis.Modules {
+		converted := &xmlModule{
+			Path:      module.Path,
+			Dir:       module.Dir,
+			GoVersion: module.GoVersion,
+			Toolchain: module.Toolchain,
+			Workspace: module.Workspace,
+			Replaces:  toXMLReplaces(module.Replaces),
+		}
+		for _, requirement := range module.Requires {
+			converted.Requires = append(converted.Requires, &xmlModuleRequirement{
+				Path:     requirement.Path,
+				Version:  requirement.Version,
+				Indirect: requirement.Indirect,
+			})
+		}
+		document.Modules = append(document.Modules, converted)
+	}
+	for _, dependency := range analysis.ModuleDependencies {
+		document.ModuleDependencies = append(document.ModuleDependencies, &xmlModuleDependency{
+			Path:       dependency.Path,
+			Version:    dependency.Version,
+			Indirect:   dependency.Indirect,
+			Sum:        dependency.Sum,
+			GoModSum:   dependency.GoModSum,
+			Replace:    toXMLReplace(dependency.Replace),
+			RequiredBy: dependency.RequiredBy,
+		})
+	}
+
+	for _, key := range sortedKeys(analysis.Packages) {
+		pkg := analysis.Packages[key]
+		document.Packages = append(document.Packages, &xmlPackage{
+			Key:        key,
+			Name:       pkg.Name,
+			Path:       pkg.Path,
+			ImportPath: pkg.ImportPath,
+			Module:     pkg.Module,
+			Files:      pkg.Files,
+		})
+	}
+	for _, key := range sortedKeys(analysis.Files) {
+		document.Files = append(document.Files, toXMLFile(key, analysis.Files[key]))
+	}
+	for _, endpoint := range analysis.APIEndpoints {
+		document.APIEndpoints = append(document.APIEndpoints, toXMLEndpoint(endpoint))
+	}
+
+	return marshalXML(document)
+}
+
+// MarshalAPIAnalysisXML renders an endpoint and the code nodes it uses as XML
+func MarshalAPIAnalysisXML(endpoint *entity.APIEndpoint, nodes []*entity.CodeNode) ([]byte, error) {
+	document := &xmlAPIAnalysis{
+		Endpoint: toXMLEndpoint(endpoint),
+		Nodes:    make([]*xmlCodeNode, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		xmlNode := &xmlCodeNode{
+			ID:              node.ID,
+			Name:            node.Name,
+			Type:            node.Type,
+			File:            node.File,
+			Package:         node.Package,
+			PackagePath:     node.PackagePath,
+			SymbolKey:       node.SymbolKey,
+			Generated:       node.Generated,
+			BuildConstraint: node.BuildConstraint,
+			Position:        toXMLPosition(node.Position),
+			Doc:             toXMLText(node.Doc),
+			Body:            toXMLText(node.Body),
+		}
+		for _, key := range sortedKeys(node.Metadata) {
+			entry, err := toXMLMetadata(key, node.Metadata[key])
+			if err != nil {
+				return nil, err
+			}
+			xmlNode.Metadata = append(xmlNode.Metadata, entry)
+		}
+		document.Nodes = append(document.Nodes, xmlNode)
+	}
+
+	return marshalXML(document)
+}
+
+// UnmarshalAnalysisXML reads back a project analysis exported by MarshalAnalysisXML
+func UnmarshalAnalysisXML(data []byte) (*entity.ProjectAnalysis, error) {
+	var document xmlAnalysis
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, errors.NewValidationError("invalid analysis XML: " + err.Error())
+	}
+	if document.XMLName.Space != AnalysisNamespace {
+		return nil, errors.NewValidationError("invalid analysis XML: unexpected namespace " + document.XMLName.Space)
+	}
+
+	analysis := &entity.ProjectAnalysis{
+		ID:                 document.ID,
+		Version:            document.Version,
+		ProjectPath:        document.ProjectPath,
+		Pinned:             document.Pinned,
+		ScanDurationMs:     document.ScanDurationMs,
+		CreatedAt:          document.CreatedAt,
+		UpdatedAt:          document.UpdatedAt,
+		BuildContext:       fromXMLBuildContext(document.BuildContext),
+		Files:              make(map[string]*entity.FileInfo, len(document.Files)),
+		Packages:           make(map[string]*entity.PackageInfo, len(document.Packages)),
+		APIEndpoints:       make([]*entity.APIEndpoint, 0, len(document.APIEndpoints)),
+		Modules:            make([]*entity.ModuleInfo, 0, len(document.Modules)),
+		ModuleDependencies: make([]*entity.ModuleDependency, 0, len(document.ModuleDependencies)),
+	}
+	if config := document.Config; config != nil {
+		analysis.Config = &entity.AnalysisConfig{
+			IncludeVendor:    config.IncludeVendor,
+			IncludeTestFile:  config.IncludeTestFile,
+			ExcludeGenerated: config.ExcludeGenerated,
+			BlacklistFiles:   config.BlacklistFiles,
+			BlacklistDirs:    config.BlacklistDirs,
+			WhitelistFiles:   config.WhitelistFiles,
+			WhitelistDirs:    config.WhitelistDirs,
+			BuildContext:     fromXMLBuildContext(config.BuildContext),
+		}
+	}
+	for _, module := range document.Modules {
+		converted := &entity.ModuleInfo{
+			Path:      module.Path,
+			Dir:       module.Dir,
+			GoVersion: module.GoVersion,
+			Toolchain: module.Toolchain,
+			Workspace: module.Workspace,
+			Requires:  make([]*entity.ModuleRequirement, 0, len(module.Requires)),
+			Replaces:  fromXMLReplaces(module.Replaces),
+		}
+		for _, requirement := range module.Requires {
+			converted.Requires = append(converted.Requires, &entity.ModuleRequirement{
+				Path:     requirement.Path,
+				Version:  requirement.Version,
+				Indirect: requirement.Indirect,
+			})
+		}
+		analysis.Modules = append(analysis.Modules, converted)
+	}
+	if workspace := document.Workspace; workspace != nil {
+		analysis.Workspace = &entity.WorkspaceInfo{
+			GoVersion: workspace.GoVersion,
+			Uses:      workspace.Uses,
+			Replaces:  fromXMLReplaces(workspace.Replaces),
+		}
+	}
+	for _, dependency := range document.ModuleDependencies {
+		analysis.ModuleDependencies = append(analysis.ModuleDependencies, &entity.ModuleDependency{
+			Path:       dependency.Path,
+			Version:    dependency.Version,
+			Indirect:   dependency.Indirect,
+			Sum:        dependency.Sum,
+			GoModSum:   dependency.GoModSum,
+			Replace:    fromXMLReplace(dependency.Replace),
+			RequiredBy: dependency.RequiredBy,
+		})
+	}
+	for _, pkg := range document.Packages {
+		analysis.Packages[pkg.Key] = &entity.PackageInfo{
+			Name:       pkg.Name,
+			Path:       pkg.Path,
+			ImportPath: pkg.ImportPath,
+			Module:     pkg.Module,
+			Files:      pkg.Files,
+		}
+	}
+	for _, file := range document.Files {
+		analysis.Files[file.Key] = fromXMLFile(file)
+	}
+	for _, endpoint := range document.APIEndpoints {
+		analysis.APIEndpoints = append(analysis.APIEndpoints, fromXMLEndpoint(endpoint))
+	}
+	if graph := document.DependencyGraph; graph != nil {
+		analysis.DependencyGraph = &entity.DependencyGraph{
+			Nodes:        make([]*entity.DependencyNode, 0, len(graph.Nodes)),
+			Dependencies: make([]*entity.Dependency, 0, len(graph.Dependencies)),
+		}
+		for _, node := range graph.Nodes {
+			analysis.DependencyGraph.Nodes = append(analysis.DependencyGraph.Nodes, &entity.DependencyNode{
+				ID: node.ID, Name: node.Name, Type: node.Type, File: node.File, Package: node.Package,
+			})
+		}
+		for _, dependency := range graph.Dependencies {
+			analysis.DependencyGraph.Dependencies = append(analysis.DependencyGraph.Dependencies, &entity.Dependency{
+				From: dependency.From, To: dependency.To, Type: dependency.Type, Strength: dependency.Strength,
+			})
+		}
+	}
+
+	return analysis, nil
+}
+
+func marshalXML(document interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, errors.NewSystemError("failed to marshal XML: " + err.Error())
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toXMLText(text string) *xmlText {
+	if text == "" {
+		return nil
+	}
+	return &xmlText{Text: text}
+}
+
+func fromXMLText(text *xmlText) string {
+	if text == nil {
+		return ""
+	}
+	return text.Text
+}
+
+func toXMLPosition(position *entity.Position) *xmlPosition {
+	if position == nil {
+		return nil
+	}
+	return &xmlPosition{Line: position.Line, Column: position.Column, Offset: position.Offset}
+}
+
+func fromXMLPosition(position *xmlPosition) *entity.Position {
+	if position == nil {
+		return nil
+	}
+	return &entity.Position{Line: position.Line, Column: position.Column, Offset: position.Offset}
+}
+
+func toXMLBuildContext(context *entity.BuildContext) *xmlBuildContext {
+	if context == nil {
+		return nil
+	}
+	return &xmlBuildContext{
+		GOOS:           context.GOOS,
+		GOARCH:         context.GOARCH,
+		CgoEnabled:     context.CgoEnabled,
+		AllConstraints: context.AllConstraints,
+		Tags:           context.Tags,
+	}
+}
+
+func fromXMLBuildContext(context *xmlBuildContext) *entity.BuildContext {
+	if context == nil {
+		return nil
+	}
+	return &entity.BuildContext{
+		GOOS:           context.GOOS,
+		GOARCH:         context.GOARCH,
+		CgoEnabled:     context.CgoEnabled,
+		AllConstraints: context.AllConstraints,
+		Tags:           context.Tags,
+	}
+}
+
+func toXMLWorkspace(workspace *entity.WorkspaceInfo) *xmlWorkspace {
+	if workspace == nil {
+		return nil
+	}
+	return &xmlWorkspace{GoVersion: workspace.GoVersion, Uses: workspace.Uses, Replaces: toXMLReplaces(workspace.Replaces)}
+}
+
+func toXMLReplace(replace *entity.ModuleReplace) *xmlModuleReplace {
+	if replace == nil {
+		return nil
+	}
+	return &xmlModuleReplace{
+		OldPath:    replace.OldPath,
+		OldVersion: replace.OldVersion,
+		NewPath:    replace.NewPath,
+		NewVersion: replace.NewVersion,
+	}
+}
+
+func fromXMLReplace(replace *xmlModuleReplace) *entity.ModuleReplace {
+	if replace == nil {
+		return nil
+	}
+	return &entity.ModuleReplace{
+		OldPath:    replace.OldPath,
+		OldVersion: replace.OldVersion,
+		NewPath:    replace.NewPath,
+		NewVersion: replace.NewVersion,
+	}
+}
+
+func toXMLReplaces(replaces []*entity.ModuleReplace) []*xmlModuleReplace {
+	var converted []*xmlModuleReplace
+	for _, replace := range replaces {
+		converted = append(converted, toXMLReplace(replace))
+	}
+	return converted
+}
+
+func fromXMLReplaces(replaces []*xmlModuleReplace) []*entity.ModuleReplace {
+	var converted []*entity.ModuleReplace
+	for _, replace := range replaces {
+		converted = append(converted, fromXMLReplace(replace))
+	}
+	return converted
+}
+
+func toXMLFile(key string, file *entity.FileInfo) *xmlFile {
+	converted := &xmlFile{
+		Key:             key,
+		Path:            file.Path,
+		AbsolutePath:    file.AbsolutePath,
+		PackageName:     file.PackageName,
+		ImportPath:      file.ImportPath,
+		ContentHash:     file.ContentHash,
+		Size:            file.Size,
+		ModTime:         file.ModTime,
+		Generated:       file.Generated,
+		BuildConstraint: file.BuildConstraint,
+		Content:         toXMLText(file.Content),
+	}
+
+	details := make(map[string]*entity.ImportInfo, len(file.ImportDetails))
+	for _, detail := range file.ImportDetails {
+		details[detail.Path] = detail
+	}
+	for _, importPath := range file.Imports {
+		imported := &xmlImport{Path: importPath}
+		if detail := details[importPath]; detail != nil {
+			imported.Name, imported.Kind, imported.Module = detail.Name, detail.Kind, detail.Module
+		}
+		converted.Imports = append(converted.Imports, imported)
+	}
+
+	for _, function := range file.Functions {
+		xmlFunction := &xmlFunction{
+			Name:       function.Name,
+			Receiver:   function.Receiver,
+			IsMethod:   function.IsMethod,
+			Complexity: function.Complexity,
+			Position:   toXMLPosition(function.Position),
+			UsedTypes:  function.UsedTypes,
+			Doc:        toXMLText(function.Doc),
+			Body:       toXMLText(function.Body),
+		}
+		for _, parameter := range function.Parameters {
+			xmlFunction.Parameters = append(xmlFunction.Parameters, &xmlParameter{Name: parameter.Name, Type: parameter.Type})
+		}
+		for _, result := range function.Returns {
+			xmlFunction.Returns = append(xmlFunction.Returns, &xmlParameter{Name: result.Name, Type: result.Type})
+		}
+		for _, call := range function.CallsTo {
+			xmlFunction.CallsTo = append(xmlFunction.CallsTo, &xmlCall{
+				Name:      call.Name,
+				Position:  toXMLPosition(call.Position),
+				Arguments: call.Arguments,
+			})
+		}
+		converted.Functions = append(converted.Functions, xmlFunction)
+	}
+	for _, typeInfo := range file.Types {
+		converted.Types = append(converted.Types, &xmlTypeDef{
+			Name: typeInfo.Name,
+			Type: typeInfo.Type,
+			Doc:  toXMLText(typeInfo.Doc),
+			Body: toXMLText(typeInfo.Body),
+		})
+	}
+	for _, variable := range file.Variables {
+		converted.Variables = append(converted.Variables, &xmlValue{
+			Name:  variable.Name,
+			Type:  variable.Type,
+			Value: variable.Value,
+			Body:  toXMLText(variable.Body),
+		})
+	}
+	for _, constant := range file.Constants {
+		converted.Constants = append(converted.Constants, &xmlValue{
+			Name:  constant.Name,
+			Type:  constant.Type,
+			Value: constant.Value,
+			Body:  toXMLText(constant.Body),
+		})
+	}
+	for _, iface := range file.Interfaces {
+		xmlInterface := &xmlInterface{
+			Name: iface.Name,
+			Doc:  toXMLText(iface.Doc),
+			Body: toXMLText(iface.Body),
+		}
+		for _, method := range iface.Methods {
+			xmlMethod := &xmlInterfaceMethod{Name: method.Name}
+			for _, parameter := range method.Parameters {
+				xmlMethod.Parameters = append(xmlMethod.Parameters, &xmlParameter{Name: parameter.Name, Type: parameter.Type})
+			}
+			for _, result := range method.Returns {
+				xmlMethod.Returns = append(xmlMethod.Returns, &xmlParameter{Name: result.Name, Type: result.Type})
+			}
+			xmlInterface.Methods = append(xmlInterface.Methods, xmlMethod)
+		}
+		converted.Interfaces = append(converted.Interfaces, xmlInterface)
+	}
+	for _, structInfo := range file.Structs {
+		xmlStruct := &xmlStruct{
+			Name: structInfo.Name,
+			Doc:  toXMLText(structInfo.Doc),
+			Body: toXMLText(structInfo.Body),
+		}
+		for _, field := range structInfo.Fields {
+			xmlStruct.Fields = append(xmlStruct.Fields, &xmlStructField{
+				Name:     field.Name,
+				Type:     field.Type,
+				Tag:      field.Tag,
+				Embedded: field.Embedded,
+			})
+		}
+		converted.Structs = append(converted.Structs, xmlStruct)
+	}
+
+	return converted
+}
+
+func fromXMLFile(file *xmlFile) *entity.FileInfo {
+	converted := &entity.FileInfo{
+		Path:            file.Path,
+		AbsolutePath:    file.AbsolutePath,
+		PackageName:     file.PackageName,
+		ImportPath:      file.ImportPath,
+		Content:         fromXMLText(file.Content),
+		ContentHash:     file.ContentHash,
+		Size:            file.Size,
+		ModTime:         file.ModTime,
+		Generated:       file.Generated,
+		BuildConstraint: file.BuildConstraint,
+		Imports:         make([]string, 0, len(file.Imports)),
+		ImportDetails:   make([]*entity.ImportInfo, 0, len(file.Imports)),
+		Functions:       make([]*entity.FunctionInfo, 0, len(file.Functions)),
+		Types:           make([]*entity.TypeInfo, 0, len(file.Types)),
+		Variables:       make([]*entity.VariableInfo, 0, len(file.Variables)),
+		Constants:       make([]*entity.ConstantInfo, 0, len(file.Constants)),
+		Interfaces:      make([]*entity.InterfaceInfo, 0, len(file.Interfaces)),
+		Structs:         make([]*entity.StructInfo, 0, len(file.Structs)),
+	}
+
+	for _, imported := range file.Imports {
+		converted.Imports = append(converted.Imports, imported.Path)
+		if imported.Kind != "" {
+			converted.ImportDetails = append(converted.ImportDetails, &entity.ImportInfo{
+				Path:   imported.Path,
+				Name:   imported.Name,
+				Kind:   imported.Kind,
+				Module: imported.Module,
+			})
+		}
+	}
+
+	for _, function := range file.Functions {
+		converted.Functions = append(converted.Functions, &entity.FunctionInfo{
+			Name:       function.Name,
+			Receiver:   function.Receiver,
+			IsMethod:   function.IsMethod,
+			Parameters: fromXMLParameters(function.Parameters),
+			Returns:    fromXMLReturns(function.Returns),
+			Doc:        fromXMLText(function.Doc),
+			Body:       fromXMLText(function.Body),
+			Position:   fromXMLPosition(function.Position),
+			CallsTo:    fromXMLCalls(function.CallsTo),
+			UsedTypes:  stringList(function.UsedTypes),
+			Complexity: function.Complexity,
+		})
+	}
+	for _, typeInfo := range file.Types {
+		converted.Types = append(converted.Types, &entity.TypeInfo{
+			Name: typeInfo.Name,
+			Type: typeInfo.Type,
+			Doc:  fromXMLText(typeInfo.Doc),
+			Body: fromXMLText(typeInfo.Body),
+		})
+	}
+	for _, variable := range file.Variables {
+		converted.Variables = append(converted.Variables, &entity.VariableInfo{
+			Name:  variable.Name,
+			Type:  variable.Type,
+			Value: variable.Value,
+			Body:  fromXMLText(variable.Body),
+		})
+	}
+	for _, constant := range file.Constants {
+		converted.Constants = append(converted.Constants, &entity.ConstantInfo{
+			Name:  constant.Name,
+			Type:  constant.Type,
+			Value: constant.Value,
+			Body:  fromXMLText(constant.Body),
+		})
+	}
+	for _, iface := range file.Interfaces {
+		interfaceInfo := &entity.InterfaceInfo{
+			Name:    iface.Name,
+			Methods: make([]*entity.InterfaceMethod, 0, len(iface.Methods)),
+			Doc:     fromXMLText(iface.Doc),
+			Body:    fromXMLText(iface.Body),
+		}
+		for _, method := range iface.Methods {
+			interfaceInfo.Methods = append(interfaceInfo.Methods, &entity.InterfaceMethod{
+				Name:       method.Name,
+				Parameters: fromXMLParameters(method.Parameters),
+				Returns:    fromXMLReturns(method.Returns),
+			})
+		}
+		converted.Interfaces = append(converted.Interfaces, interfaceInfo)
+	}
+	for _, structInfo := range file.Structs {
+		converted.Structs = append(converted.Structs, &entity.StructInfo{
+			Name:   structInfo.Name,
+			Fields: fromXMLFields(structInfo.Fields),
+			Doc:    fromXMLText(structInfo.Doc),
+			Body:   fromXMLText(structInfo.Body),
+		})
+	}
+
+	return converted
+}
+
+// stringList keeps lists the parser always sets from decoding as nil
+func stringList(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func fromXMLParameters(parameters []*xmlParameter) []*entity.Parameter {
+	converted := make([]*entity.Parameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		converted = append(converted, &entity.Parameter{Name: parameter.Name, Type: parameter.Type})
+	}
+	return converted
+}
+
+func fromXMLReturns(returns []*xmlParameter) []*entity.Return {
+	converted := make([]*entity.Return, 0, len(returns))
+	for _, result := range returns {
+		converted = append(converted, &entity.Return{Name: result.Name, Type: result.Type})
+	}
+	return converted
+}
+
+func fromXMLCalls(calls []*xmlCall) []*entity.FunctionCall {
+	converted := make([]*entity.FunctionCall, 0, len(calls))
+	for _, call := range calls {
+		converted = append(converted, &entity.FunctionCall{
+			Name:      call.Name,
+			Arguments: stringList(call.Arguments),
+			Position:  fromXMLPosition(call.Position),
+		})
+	}
+	return converted
+}
+
+func fromXMLFields(fields []*xmlStructField) []*entity.StructField {
+	converted := make([]*entity.StructField, 0, len(fields))
+	for _, field := range fields {
+		converted = append(converted, &entity.StructField{
+			Name:     field.Name,
+			Type:     field.Type,
+			Tag:      field.Tag,
+			Embedded: field.Embedded,
+		})
+	}
+	return converted
+}
+
+func toXMLEndpoint(endpoint *entity.APIEndpoint) *xmlEndpoint {
+	converted := &xmlEndpoint{
+		ID:            endpoint.ID,
+		Method:        endpoint.Method,
+		Path:          endpoint.Path,
+		File:          endpoint.File,
+		Handler:       endpoint.Handler,
+		HandlerSymbol: endpoint.HandlerSymbol,
+		RequestType:   endpoint.RequestType,
+		Middlewares:   endpoint.Middlewares,
+		PathParams:    endpoint.PathParams,
+		ResponseTypes: endpoint.ResponseTypes,
+	}
+	for _, param := range endpoint.QueryParams {
+		converted.QueryParams = append(converted.QueryParams, &xmlEndpointParam{Name: param.Name, Required: param.Required})
+	}
+	return converted
+}
+
+func fromXMLEndpoint(endpoint *xmlEndpoint) *entity.APIEndpoint {
+	converted := &entity.APIEndpoint{
+		ID:            endpoint.ID,
+		Method:        endpoint.Method,
+		Path:          endpoint.Path,
+		File:          endpoint.File,
+		Handler:       endpoint.Handler,
+		HandlerSymbol: endpoint.HandlerSymbol,
+		RequestType:   endpoint.RequestType,
+		Middlewares:   endpoint.Middlewares,
+		PathParams:    endpoint.PathParams,
+		ResponseTypes: endpoint.ResponseTypes,
+	}
+	for _, param := range endpoint.QueryParams {
+		converted.QueryParams = append(converted.QueryParams, &entity.EndpointParam{Name: param.Name, Required: param.Required})
+	}
+	return converted
+}
+
+func toXMLDependencyGraph(graph *entity.DependencyGraph) *xmlDependencyGraph {
+	if graph == nil {
+		return nil
+	}
+	converted := &xmlDependencyGraph{}
+	for _, node := range graph.Nodes {
+		converted.Nodes = append(converted.Nodes, &xmlDependencyNode{
+			ID: node.ID, Name: node.Name, Type: node.Type, File: node.File, Package: node.Package,
+		})
+	}
+	for _, dependency := range graph.Dependencies {
+		converted.Dependencies = append(converted.Dependencies, &xmlDependency{
+			From: dependency.From, To: dependency.To, Type: dependency.Type, Strength: dependency.Strength,
+		})
+	}
+	return converted
+}
+
+// toXMLMetadata writes scalar metadata as text and anything else as JSON
+func toXMLMetadata(key string, value interface{}) (*xmlMetadata, error) {
+	entry := &xmlMetadata{Key: key}
+	switch value := value.(type) {
+	case string:
+		entry.Type, entry.Value = metadataString, value
+	case bool:
+		entry.Type, entry.Value = metadataBool, strconv.FormatBool(value)
+	case int:
+		entry.Type, entry.Value = metadataInt, strconv.Itoa(value)
+	case int64:
+		entry.Type, entry.Value = metadataInt, strconv.FormatInt(value, 10)
+	case float64:
+		entry.Type, entry.Value = metadataFloat, strconv.FormatFloat(value, 'g', -1, 64)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.NewSystemError(fmt.Sprintf("failed to marshal metadata %s: %v", key, err))
+		}
+		entry.Type, entry.Value = metadataJSON, string(data)
+	}
+	return entry, nil
+}
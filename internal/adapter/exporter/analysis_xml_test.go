@@ -0,0 +1,279 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureAnalysis fills every field the exports carry, so that a field dropped by
+// one of them fails the round trip
+func fixtureAnalysis() *entity.ProjectAnalysis {
+	scanned := time.Date(2024, 5, 17, 9, 30, 15, 123000000, time.UTC)
+	buildContext := &entity.BuildContext{GOOS: "linux", GOARCH: "amd64", Tags: []string{"integration"}, CgoEnabled: true}
+	replace := &entity.ModuleReplace{OldPath: "example.com/lib", OldVersion: "v1.0.0", NewPath: "../lib"}
+
+	return &entity.ProjectAnalysis{
+		ID:          "project-1",
+		Version:     3,
+		ProjectPath: "/src/shop",
+		Files: map[string]*entity.FileInfo{
+			"api/handler.go": {
+				Path:            "api/handler.go",
+				AbsolutePath:    "/src/shop/api/handler.go",
+				PackageName:     "api",
+				ImportPath:      "example.com/shop/api",
+				Content:         "package api\n\n// Ping answers health checks\nfunc Ping(c *gin.Context) {\n\tif c != nil && len(c.Keys) > 0 {\n\t\tc.String(200, \"<pong>\")\n\t}\n}\n",
+				ContentHash:     "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				Size:            128,
+				ModTime:         scanned.Add(-time.Hour),
+				Generated:       false,
+				BuildConstraint: "linux && !race",
+				Imports:         []string{"net/http", "github.com/gin-gonic/gin"},
+				ImportDetails: []*entity.ImportInfo{
+					{Path: "net/http", Kind: "stdlib"},
+					{Path: "github.com/gin-gonic/gin", Name: "gin", Kind: "third_party", Module: "github.com/gin-gonic/gin"},
+				},
+				Functions: []*entity.FunctionInfo{{
+					Name:       "Ping",
+					Receiver:   "*Server",
+					IsMethod:   true,
+					Parameters: []*entity.Parameter{{Name: "c", Type: "*gin.Context"}},
+					Returns:    []*entity.Return{{Name: "err", Type: "error"}},
+					Doc:        "Ping answers health checks",
+					Body:       "{\n\tc.String(200, \"<pong>\")\n}",
+					Position:   &entity.Position{Line: 4, Column: 1, Offset: 42},
+					CallsTo: []*entity.FunctionCall{{
+						Name:      "c.String",
+						Arguments: []string{"200", "\"<pong>\""},
+						Position:  &entity.Position{Line: 6, Column: 3, Offset: 90},
+					}},
+					UsedTypes:  []string{"gin.Context"},
+					Complexity: 3,
+				}},
+				Types:     []*entity.TypeInfo{{Name: "Status", Type: "string", Doc: "Status of an order", Body: "type Status string"}},
+				Variables: []*entity.VariableInfo{{Name: "started", Type: "time.Time", Value: "time.Now()", Body: "var started = time.Now()"}},
+				Constants: []*entity.ConstantInfo{{Name: "Open", Type: "Status", Value: "\"open\"", Body: "const Open Status = \"open\""}},
+				Interfaces: []*entity.InterfaceInfo{{
+					Name: "Store",
+					Methods: []*entity.InterfaceMethod{{
+						Name:       "Get",
+						Parameters: []*entity.Parameter{{Name: "id", Type: "string"}},
+						Returns:    []*entity.Return{{Name: "", Type: "error"}},
+					}},
+					Doc:  "Store keeps orders",
+					Body: "interface {\n\tGet(id string) error\n}",
+				}},
+				Structs: []*entity.StructInfo{{
+					Name: "Order",
+					Fields: []*entity.StructField{
+						{Name: "ID", Type: "string", Tag: "`json:\"id\"`"},
+						{Name: "Base", Type: "Base", Embedded: true},
+					},
+					Doc:  "Order is a placed order",
+					Body: "struct {\n\tBase\n\tID string `json:\"id\"`\n}",
+				}},
+			},
+		},
+		Packages: map[string]*entity.PackageInfo{
+			"api": {Name: "api", Path: "api", ImportPath: "example.com/shop/api", Module: "example.com/shop", Files: []string{"api/handler.go"}},
+		},
+		APIEndpoints: []*entity.APIEndpoint{{
+			ID:            "endpoint-1",
+			Method:        "GET",
+			Path:          "/orders/:id",
+			File:          "api/router.go",
+			Handler:       "server.GetOrder",
+			Middlewares:   []string{"auth()"},
+			HandlerSymbol: "example.com/shop/api.(*Server).GetOrder",
+			PathParams:    []string{"id"},
+			QueryParams:   []*entity.EndpointParam{{Name: "expand", Required: true}},
+			RequestType:   "GetOrderRequest",
+			ResponseTypes: []string{"Order"},
+		}},
+		BuildContext: buildContext,
+		Modules: []*entity.ModuleInfo{{
+			Path:      "example.com/shop",
+			Dir:       ".",
+			GoVersion: "1.22",
+			Toolchain: "go1.22.3",
+			Requires:  []*entity.ModuleRequirement{{Path: "github.com/gin-gonic/gin", Version: "v1.10.0", Indirect: true}},
+			Replaces:  []*entity.ModuleReplace{replace},
+			Workspace: true,
+		}},
+		Workspace: &entity.WorkspaceInfo{GoVersion: "1.22", Uses: []string{".", "../lib"}, Replaces: []*entity.ModuleReplace{replace}},
+		ModuleDependencies: []*entity.ModuleDependency{{
+			Path:       "github.com/gin-gonic/gin",
+			Version:    "v1.10.0",
+			Indirect:   true,
+			Sum:        "h1:abc=",
+			GoModSum:   "h1:def=",
+			Replace:    replace,
+			RequiredBy: []string{"example.com/shop"},
+		}},
+		DependencyGraph: &entity.DependencyGraph{
+			Nodes: []*entity.DependencyNode{
+				{ID: "file_api/handler.go", Name: "api/handler.go", Type: "file", File: "api/handler.go", Package: "api"},
+				{ID: "import_net/http", Name: "net/http", Type: "import"},
+			},
+			Dependencies: []*entity.Dependency{{From: "file_api/handler.go", To: "import_net/http", Type: "import", Strength: 5}},
+		},
+		Config: &entity.AnalysisConfig{
+			BlacklistFiles:   []string{"*_mock.go"},
+			BlacklistDirs:    []string{"testdata"},
+			WhitelistFiles:   []string{"api/*.go"},
+			WhitelistDirs:    []string{"api"},
+			IncludeVendor:    true,
+			IncludeTestFile:  true,
+			ExcludeGenerated: true,
+			BuildContext:     buildContext,
+		},
+		Pinned:         true,
+		ScanDurationMs: 1500,
+		CreatedAt:      scanned.Add(-24 * time.Hour),
+		UpdatedAt:      scanned,
+	}
+}
+
+// assertSameAnalysis compares a decoded analysis with the fixture, reporting the
+// first differing part by its JSON encoding
+func assertSameAnalysis(t *testing.T, want, got *entity.ProjectAnalysis) {
+	t.Helper()
+	if reflect.DeepEqual(want, got) {
+		return
+	}
+	wantJSON, _ := json.MarshalIndent(want, "", "  ")
+	gotJSON, _ := json.MarshalIndent(got, "", "  ")
+	wantLines, gotLines := strings.Split(string(wantJSON), "\n"), strings.Split(string(gotJSON), "\n")
+	for i, wantLine := range wantLines {
+		gotLine := ""
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if wantLine != gotLine {
+			t.Fatalf("round trip changed the analysis at line %d:\nwant: %s\ngot:  %s", i+1, wantLine, gotLine)
+		}
+	}
+	t.Fatalf("round trip changed the analysis:\nwant: %s\ngot:  %s", wantJSON, gotJSON)
+}
+
+// validateXML checks a document against AnalysisXSD with xmllint, which the Go
+// standard library has no equivalent of
+func validateXML(t *testing.T, data []byte) {
+	t.Helper()
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Log("xmllint not found, skipping schema validation")
+		return
+	}
+
+	schema := filepath.Join(t.TempDir(), "analysis.xsd")
+	if err := os.WriteFile(schema, AnalysisXSD, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(xmllint, "--noout", "--schema", schema, "-")
+	cmd.Stdin = bytes.NewReader(data)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("export is not valid against analysis.xsd: %v\n%s", err, output)
+	}
+}
+
+func TestAnalysisXMLRoundTrip(t *testing.T) {
+	analysis := fixtureAnalysis()
+
+	data, err := MarshalAnalysisXML(analysis)
+	if err != nil {
+		t.Fatalf("MarshalAnalysisXML: %v", err)
+	}
+	validateXML(t, data)
+
+	decoded, err := UnmarshalAnalysisXML(data)
+	if err != nil {
+		t.Fatalf("UnmarshalAnalysisXML: %v", err)
+	}
+	assertSameAnalysis(t, analysis, decoded)
+
+	again, err := MarshalAnalysisXML(decoded)
+	if err != nil {
+		t.Fatalf("MarshalAnalysisXML: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Error("exporting a decoded analysis again gave a different document")
+	}
+}
+
+func TestAPIAnalysisXMLIsValid(t *testing.T) {
+	analysis := fixtureAnalysis()
+	nodes := []*entity.CodeNode{{
+		ID:        "node-1",
+		Name:      "GetOrder",
+		Type:      "function",
+		File:      "api/handler.go",
+		Package:   "api",
+		SymbolKey: "example.com/shop/api.(*Server).GetOrder",
+		Position:  &entity.Position{Line: 12, Column: 1, Offset: 240},
+		Metadata: map[string]interface{}{
+			"complexity": 4,
+			"exported":   true,
+			"receiver":   "*Server",
+			"calls":      []string{"store.Get"},
+		},
+		Body: "{\n\treturn nil\n}",
+	}}
+
+	data, err := MarshalAPIAnalysisXML(analysis.APIEndpoints[0], nodes)
+	if err != nil {
+		t.Fatalf("MarshalAPIAnalysisXML: %v", err)
+	}
+	validateXML(t, data)
+}
+
+func TestUnmarshalAnalysisXMLRejectsOtherDocuments(t *testing.T) {
+	for name, data := range map[string]string{
+		"malformed":       "<analysis",
+		"wrong namespace": `<analysis xmlns="urn:example" id="project-1"></analysis>`,
+	} {
+		if _, err := UnmarshalAnalysisXML([]byte(data)); !errors.IsValidationError(err) {
+			t.Errorf("%s: got %v, want a validation error", name, err)
+		}
+	}
+}
+
+func TestAnalysisJSONRoundTrip(t *testing.T) {
+	analysis := fixtureAnalysis()
+
+	data, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent: %v", err)
+	}
+	var decoded entity.ProjectAnalysis
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	assertSameAnalysis(t, analysis, &decoded)
+}
+
+func TestAnalysisYAMLRoundTrip(t *testing.T) {
+	analysis := fixtureAnalysis()
+
+	data, err := yaml.Marshal(analysis)
+	if err != nil {
+		t.Fatalf("yaml.Marshal: %v", err)
+	}
+	var decoded entity.ProjectAnalysis
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("yaml.Unmarshal: %v", err)
+	}
+	assertSameAnalysis(t, analysis, &decoded)
+}
@@ -0,0 +1,34 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+
+	"goapianalyzer/pkg/errors"
+)
+
+// ArchiveFile is a file of a generated archive, named by its slash separated path
+type ArchiveFile struct {
+	Name string
+	Data []byte
+}
+
+// ZipArchive packs files into a zip archive in the given order. Entries carry no
+// modification time so the same files always give the same archive.
+func ZipArchive(files []*ArchiveFile) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Deflate})
+		if err != nil {
+			return nil, errors.NewSystemError("failed to add " + file.Name + " to archive: " + err.Error())
+		}
+		if _, err := writer.Write(file.Data); err != nil {
+			return nil, errors.NewSystemError("failed to write " + file.Name + " to archive: " + err.Error())
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, errors.NewSystemError("failed to write archive: " + err.Error())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,341 @@
+package exporter
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	"goapianalyzer/pkg/errors"
+)
+
+const goClientRuntime = `
+// Client calls the %[1]s API
+type Client struct {
+	BaseURL    string       // Scheme, host and path prefix of the API
+	HTTPClient *http.Client // http.DefaultClient when nil
+	Header     http.Header  // Sent with every request, for example for authentication
+}
+
+// NewClient returns a client of the API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL, Header: make(http.Header)}
+}
+
+// Error is returned for responses with a status outside of 2xx
+type Error struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("unexpected status %%d: %%s", e.StatusCode, strings.TrimSpace(string(e.Body)))
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into result. A *json.RawMessage result receives the response body as is.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
+	endpoint := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return &Error{StatusCode: response.StatusCode, Body: data}
+	}
+	if raw, ok := result.(*json.RawMessage); ok {
+		*raw = data
+		return nil
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+`
+
+// goWriter collects a Go source file and the packages it imports
+type goWriter struct {
+	strings.Builder
+	imports map[string]bool
+}
+
+// renderGoClient writes the Go client package: client.go with the Client and a
+// method per endpoint, models.go with the request, response and query types
+func renderGoClient(m *clientModel) ([]*ArchiveFile, error) {
+	pkg := m.sdk.PackageName
+
+	client := &goWriter{imports: map[string]bool{
+		"bytes": true, "context": true, "encoding/json": true, "fmt": true,
+		"io": true, "net/http": true, "net/url": true, "strings": true,
+	}}
+	fmt.Fprintf(client, goClientRuntime, m.title)
+	for _, e := range m.endpoints {
+		client.writeMethod(e)
+	}
+
+	models := &goWriter{imports: map[string]bool{}}
+	for _, t := range m.types {
+		models.writeType(m, t)
+	}
+	for _, e := range m.endpoints {
+		if e.paramsType != "" {
+			models.writeParams(e)
+		}
+	}
+
+	files := []*ArchiveFile{
+		{Name: "go/" + pkg + "/client.go", Data: []byte(client.source(pkg, "Package "+pkg+" is a client of the "+m.title+" API."))},
+		{Name: "go/" + pkg + "/models.go", Data: []byte(models.source(pkg, ""))},
+	}
+
+	// Generated code that does not parse is a bug of the generator
+	for _, file := range files {
+		formatted, err := format.Source(file.Data)
+		if err != nil {
+			return nil, errors.NewSystemError("generated Go client of " + file.Name + " is invalid: " + err.Error())
+		}
+		file.Data = formatted
+	}
+	return files, nil
+}
+
+// source prefixes the collected code with the header, package clause and imports
+func (w *goWriter) source(pkg, packageDoc string) string {
+	var b strings.Builder
+	b.WriteString("// Code generated by goapianalyzer. DO NOT EDIT.\n\n")
+	if packageDoc != "" {
+		b.WriteString("// " + packageDoc + "\n")
+	}
+	b.WriteString("package " + pkg + "\n\n")
+	if len(w.imports) > 0 {
+		b.WriteString("import (\n")
+		for _, path := range sortedKeys(w.imports) {
+			b.WriteString("\t" + strconv.Quote(path) + "\n")
+		}
+		b.WriteString(")\n")
+	}
+	b.WriteString(w.String())
+	return b.String()
+}
+
+// goType writes a resolved type, importing the packages it needs
+func (w *goWriter) goType(t *typeExpr) string {
+	switch t.kind {
+	case kindRaw:
+		w.imports["encoding/json"] = true
+	case kindTime, kindDuration:
+		w.imports["time"] = true
+	case kindPointer, kindSlice:
+		w.goType(t.elem)
+	case kindMap:
+		w.goType(t.key)
+		w.goType(t.elem)
+	}
+	return t.goString()
+}
+
+func (t *typeExpr) goString() string {
+	switch t.kind {
+	case kindBasic, kindNamed:
+		return t.name
+	case kindPointer:
+		return "*" + t.elem.goString()
+	case kindSlice:
+		return "[]" + t.elem.goString()
+	case kindMap:
+		return "map[" + t.key.goString() + "]" + t.elem.goString()
+	case kindAny:
+		return "interface{}"
+	case kindTime:
+		return "time.Time"
+	case kindDuration:
+		return "time.Duration"
+	case kindBytes:
+		return "[]byte"
+	default:
+		return "json.RawMessage"
+	}
+}
+
+func (w *goWriter) comment(lines []string) {
+	for _, line := range lines {
+		w.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
+
+func (w *goWriter) writeType(m *clientModel, t *clientType) {
+	w.WriteString("\n")
+	lines := docLines(t.Doc)
+	if len(lines) == 0 {
+		lines = []string{t.name + " mirrors " + t.Package + "." + t.Name + "."}
+	}
+	w.comment(lines)
+
+	if !t.Struct {
+		underlying, ok := m.resolve(t.Package, t.Underlying)
+		if !ok {
+			underlying = &typeExpr{kind: kindAny}
+		}
+		fmt.Fprintf(w, "type %s %s\n", t.name, w.goType(underlying))
+		return
+	}
+
+	fmt.Fprintf(w, "type %s struct {\n", t.name)
+	for _, field := range m.fields(t) {
+		typ := w.goType(field.typ)
+		if field.Embedded || field.Name == "" {
+			w.WriteString("\t" + typ)
+		} else {
+			w.WriteString("\t" + field.goName + " " + typ)
+		}
+		if field.jsonTag != "" {
+			w.WriteString(" `json:" + strconv.Quote(field.jsonTag) + "`")
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("}\n")
+}
+
+func (w *goWriter) writeParams(e *clientEndpoint) {
+	fmt.Fprintf(w, "\n// %s holds the query parameters of %s\ntype %s struct {\n", e.paramsType, e.name, e.paramsType)
+	for _, param := range e.query {
+		fmt.Fprintf(w, "\t%s string // %s", param.goName, param.wire)
+		if param.required {
+			w.WriteString(", required")
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("}\n")
+}
+
+func (w *goWriter) writeMethod(e *clientEndpoint) {
+	w.WriteString("\n")
+	lines := []string{fmt.Sprintf("%s sends %s %s, handled by %s.", e.name, e.Method, e.Path, strings.TrimSpace(e.Handler))}
+	if doc := docLines(e.Doc); len(doc) > 0 {
+		lines = append(append(lines, ""), doc...)
+	}
+	w.comment(lines)
+
+	args := []string{"ctx context.Context"}
+	method := strconv.Quote(e.Method)
+	if e.Method == "ANY" {
+		args = append(args, "method string")
+		method = "method"
+	}
+	for i := range e.segments {
+		if param := e.pathParams[i]; param != nil {
+			args = append(args, param.goName+" string")
+		}
+	}
+	if e.body != nil {
+		body := w.goType(e.body)
+		if e.body.kind == kindNamed {
+			body = "*" + body
+		}
+		args = append(args, "body "+body)
+	}
+	if e.paramsType != "" {
+		args = append(args, "params *"+e.paramsType)
+	}
+
+	resultVar := w.goType(e.result)
+	result := resultVar
+	if e.result.kind == kindNamed {
+		result = "*" + resultVar
+	}
+	fmt.Fprintf(w, "func (c *Client) %s(%s) (%s, error) {\n", e.name, strings.Join(args, ", "), result)
+
+	// Path with escaped parameters; wildcards may span segments and stay as given
+	var path []string
+	literal := ""
+	for i, segment := range e.segments {
+		param := e.pathParams[i]
+		if param == nil {
+			literal += "/" + segment
+			continue
+		}
+		path = append(path, strconv.Quote(literal+"/"))
+		literal = ""
+		if param.wildcard {
+			path = append(path, "strings.TrimPrefix("+param.goName+", \"/\")")
+		} else {
+			path = append(path, "url.PathEscape("+param.goName+")")
+		}
+	}
+	if literal != "" || len(path) == 0 {
+		if literal == "" {
+			literal = "/"
+		}
+		path = append(path, strconv.Quote(literal))
+	}
+
+	query := "nil"
+	if e.paramsType != "" {
+		query = "query"
+		w.WriteString("\tquery := url.Values{}\n\tif params != nil {\n")
+		for _, param := range e.query {
+			if param.required {
+				fmt.Fprintf(w, "\t\tquery.Set(%q, params.%s)\n", param.wire, param.goName)
+			} else {
+				fmt.Fprintf(w, "\t\tif params.%s != \"\" {\n\t\t\tquery.Set(%q, params.%s)\n\t\t}\n", param.goName, param.wire, param.goName)
+			}
+		}
+		w.WriteString("\t}\n")
+	}
+
+	payload := "nil"
+	if e.body != nil {
+		payload = "body"
+		if e.body.kind == kindNamed || (e.body.nillable() && e.body.kind != kindAny) {
+			// A nil pointer, slice or map sends no body rather than null
+			payload = "payload"
+			w.WriteString("\tvar payload interface{}\n\tif body != nil {\n\t\tpayload = body\n\t}\n")
+		}
+	}
+
+	call := fmt.Sprintf("c.do(ctx, %s, %s, %s, %s, &result)", method, strings.Join(path, " + "), query, payload)
+	fmt.Fprintf(w, "\tvar result %s\n", resultVar)
+	if e.result.kind == kindNamed {
+		fmt.Fprintf(w, "\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n\treturn &result, nil\n}\n", call)
+	} else {
+		fmt.Fprintf(w, "\terr := %s\n\treturn result, err\n}\n", call)
+	}
+}
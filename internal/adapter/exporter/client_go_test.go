@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// fixtureClientSDK describes a project whose types and routes exercise the naming
+// and type mapping of the generators: clashing and keyword names, embedded and
+// pointer fields, named map keys, interfaces, wildcard paths and ANY routes
+func fixtureClientSDK() *ClientSDK {
+	endpoint := func(method, path, handler, request string, responses []string, query ...*entity.EndpointParam) *DocEndpoint {
+		return &DocEndpoint{
+			APIEndpoint: &entity.APIEndpoint{
+				ID:            method + " " + path,
+				Method:        method,
+				Path:          path,
+				File:          "api/router.go",
+				Handler:       handler,
+				QueryParams:   query,
+				RequestType:   request,
+				ResponseTypes: responses,
+			},
+			Doc: handler + " is a fixture handler.\nIts comment has */ in it.",
+		}
+	}
+
+	return &ClientSDK{
+		PackageName: "shopclient",
+		Types: []*ClientType{
+			{Name: "Status", Package: "api", Underlying: "string", Doc: "Status of an order"},
+			{Name: "Base", Package: "api", Struct: true, Fields: []*entity.StructField{
+				{Name: "ID", Type: "string", Tag: "`json:\"id\"`"},
+				{Name: "CreatedAt", Type: "time.Time", Tag: "`json:\"created_at\"`"},
+			}},
+			{Name: "Order", Package: "api", Struct: true, Doc: "Order is a placed order", Fields: []*entity.StructField{
+				{Name: "Base", Type: "Base", Embedded: true},
+				{Name: "Status", Type: "Status", Tag: "`json:\"status\"`"},
+				{Name: "Total", Type: "int64", Tag: "`json:\"total,string\"`"},
+				{Name: "Lines", Type: "[]*Line", Tag: "`json:\"lines,omitempty\"`"},
+				{Name: "ByStatus", Type: "map[Status]int", Tag: "`json:\"by_status\"`"},
+				{Name: "Extra", Type: "Payload", Tag: "`json:\"extra\"`"},
+				{Name: "Raw", Type: "json.RawMessage", Tag: "`json:\"raw\"`"},
+				{Name: "Timeout", Type: "time.Duration", Tag: "`json:\"timeout\"`"},
+				{Name: "Signature", Type: "[]byte", Tag: "`json:\"signature\"`"},
+				{Name: "Notify", Type: "func()", Tag: "`json:\"-\"`"},
+				{Name: "internal", Type: "string"},
+			}},
+			{Name: "Line", Package: "api", Struct: true, Fields: []*entity.StructField{
+				{Name: "SKU", Type: "string", Tag: "`json:\"sku\"`"},
+				{Name: "Quantity", Type: "int", Tag: "`json:\"quantity\"`"},
+			}},
+			{Name: "Payload", Package: "api", Underlying: "interface"},
+			{Name: "Client", Package: "api", Struct: true, Fields: []*entity.StructField{
+				{Name: "Name", Type: "string", Tag: "`json:\"name\"`"},
+			}},
+			{Name: "CreateOrderRequest", Package: "api", Struct: true, Fields: []*entity.StructField{
+				{Name: "Lines", Type: "[]Line", Tag: "`json:\"lines\" binding:\"required\"`"},
+				{Name: "Buyer", Type: "*Client", Tag: "`json:\"buyer\"`"},
+			}},
+		},
+		Docs: &APIDocs{
+			Title: "Shop",
+			Groups: []*DocGroup{{
+				Name: "orders",
+				Path: "/api/orders",
+				Endpoints: []*DocEndpoint{
+					endpoint("GET", "/api/orders", "h.ListOrders", "", []string{"[]Order"},
+						&entity.EndpointParam{Name: "status"}, &entity.EndpointParam{Name: "page-size"}),
+					endpoint("POST", "/api/orders", "h.CreateOrder", "CreateOrderRequest", []string{"Order"}),
+					endpoint("GET", "/api/orders/:id", "h.GetOrder", "", []string{"*Order"},
+						&entity.EndpointParam{Name: "type", Required: true}),
+					endpoint("DELETE", "/api/orders/:id", "h.DeleteOrder", "", nil),
+				},
+				Groups: []*DocGroup{{
+					Name: "files",
+					Path: "/api/orders/:id/files",
+					Endpoints: []*DocEndpoint{
+						endpoint("GET", "/api/orders/:id/files/*path", "func(c *gin.Context)", "", []string{"map[string]Payload"}),
+						endpoint("ANY", "/api/orders/:type/proxy", "h.Header", "", []string{"Order", "Line"}),
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func renderFixtureClients(t *testing.T) map[string][]byte {
+	t.Helper()
+	files, err := RenderClientSDK(fixtureClientSDK())
+	if err != nil {
+		t.Fatalf("RenderClientSDK: %v", err)
+	}
+	rendered := make(map[string][]byte, len(files))
+	for _, file := range files {
+		rendered[file.Name] = file.Data
+	}
+	return rendered
+}
+
+func TestGoClientTypeChecks(t *testing.T) {
+	rendered := renderFixtureClients(t)
+
+	fileSet := token.NewFileSet()
+	var parsed []*ast.File
+	for _, name := range []string{"go/shopclient/client.go", "go/shopclient/models.go"} {
+		data, exists := rendered[name]
+		if !exists {
+			t.Fatalf("%s was not generated", name)
+		}
+		file, err := parser.ParseFile(fileSet, name, data, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("%s does not parse: %v", name, err)
+		}
+		parsed = append(parsed, file)
+	}
+
+	config := &types.Config{Importer: importer.ForCompiler(fileSet, "source", nil)}
+	pkg, err := config.Check("shopclient", fileSet, parsed, nil)
+	if err != nil {
+		t.Fatalf("generated Go client does not type-check: %v\n%s\n%s", err, rendered["go/shopclient/client.go"], rendered["go/shopclient/models.go"])
+	}
+
+	// Clashing names are renamed rather than dropped
+	for _, name := range []string{"Client", "ApiClient", "Order", "Status", "ListOrdersParams", "GetOrderParams"} {
+		if pkg.Scope().Lookup(name) == nil {
+			t.Errorf("generated Go client declares no %s", name)
+		}
+	}
+	client, _ := pkg.Scope().Lookup("Client").Type().(*types.Named)
+	methods := map[string]bool{}
+	for i := 0; client != nil && i < client.NumMethods(); i++ {
+		methods[client.Method(i).Name()] = true
+	}
+	for _, name := range []string{"ListOrders", "CreateOrder", "GetOrder", "DeleteOrder", "GetApiOrdersIdFilesPath", "AnyApiOrdersTypeProxy"} {
+		if !methods[name] {
+			t.Errorf("generated Client has no method %s", name)
+		}
+	}
+}
+
+func TestTSClientTypeChecks(t *testing.T) {
+	rendered := renderFixtureClients(t)
+
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc not found, skipping the TypeScript client check")
+	}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "client.ts")
+	if err := os.WriteFile(source, rendered["typescript/client.ts"], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(tsc, "--noEmit", "--strict", "--target", "es2020", "--lib", "es2020,dom", source)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated TypeScript client does not type-check: %v\n%s", err, strings.TrimSpace(string(output)))
+	}
+}
@@ -0,0 +1,582 @@
+package exporter
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// ClientSDK is what the client generators write clients of
+type ClientSDK struct {
+	Docs        *APIDocs      // Endpoints by router group
+	Types       []*ClientType // Request and response types and the project types they refer to
+	PackageName string        // Package of the Go client
+}
+
+// ClientType is a project type reproduced in the clients
+type ClientType struct {
+	Name       string
+	Package    string // Package name; unqualified names in Fields and Underlying belong to it
+	Doc        string
+	Fields     []*entity.StructField // Fields of structs
+	Struct     bool
+	Underlying string // Type expression of other types; "interface" for interfaces
+}
+
+// RenderClientSDK generates a Go client package and a TypeScript client with one
+// method per endpoint, plus a README. The Go client is gofmt-formatted.
+func RenderClientSDK(sdk *ClientSDK) ([]*ArchiveFile, error) {
+	model := newClientModel(sdk)
+
+	goFiles, err := renderGoClient(model)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*ArchiveFile{{Name: "README.md", Data: renderClientReadme(model)}}
+	files = append(files, goFiles...)
+	files = append(files, &ArchiveFile{Name: "typescript/client.ts", Data: renderTSClient(model)})
+	return files, nil
+}
+
+// typeKind classifies the resolved type expressions of client fields and bodies
+type typeKind int
+
+const (
+	kindBasic    typeKind = iota // Predeclared string, bool and number types
+	kindNamed                    // A generated type
+	kindPointer                  // *elem
+	kindSlice                    // []elem
+	kindMap                      // map[key]elem
+	kindAny                      // Any JSON value
+	kindRaw                      // JSON of a type the clients do not know, kept undecoded
+	kindTime                     // time.Time
+	kindDuration                 // time.Duration
+	kindBytes                    // []byte, base64 in JSON
+)
+
+// typeExpr is a type expression resolved against the generated types
+type typeExpr struct {
+	kind typeKind
+	name string // Of basic and named types
+	key  *typeExpr
+	elem *typeExpr
+}
+
+// clientType is a project type with its name in the clients
+type clientType struct {
+	*ClientType
+	name    string
+	keyable bool // Named basic type, usable as map key
+}
+
+// clientParam is a path or query parameter of an endpoint
+type clientParam struct {
+	wire     string // Name in the path or query string
+	goName   string // Go variable of path parameters, field of query parameters
+	tsName   string // TypeScript variable of path parameters
+	required bool
+	wildcard bool // *path parameters matching several segments
+}
+
+// clientEndpoint is an endpoint with the names of its client method and parameters
+type clientEndpoint struct {
+	*DocEndpoint
+	group      *DocGroup
+	name       string // Exported Go method name
+	segments   []string
+	pathParams map[int]*clientParam // By segment index
+	query      []*clientParam
+	paramsType string // Struct of the query parameters, if any
+	body       *typeExpr
+	result     *typeExpr
+}
+
+// clientModel names the generated types, methods and parameters shared by the clients
+type clientModel struct {
+	sdk       *ClientSDK
+	title     string
+	types     []*clientType
+	byName    map[string][]*clientType // Project type name -> declarations
+	endpoints []*clientEndpoint
+	taken     map[string]bool // Exported package level Go identifiers in use
+	methods   map[string]bool // Methods and fields of Client
+}
+
+// goRuntimeNames are the identifiers of the Go client runtime
+var goRuntimeNames = []string{"Client", "NewClient", "Error"}
+
+func newClientModel(sdk *ClientSDK) *clientModel {
+	m := &clientModel{
+		sdk:    sdk,
+		title:  sdk.Docs.Title,
+		byName: make(map[string][]*clientType),
+		taken:  make(map[string]bool),
+		methods: map[string]bool{
+			"BaseURL": true, "HTTPClient": true, "Header": true,
+		},
+	}
+	for _, name := range goRuntimeNames {
+		m.taken[name] = true
+	}
+
+	for _, t := range sdk.Types {
+		if t.Underlying == "interface" {
+			// Interfaces hold any JSON value and are not declared in the clients
+			m.byName[t.Name] = append(m.byName[t.Name], &clientType{ClientType: t})
+			continue
+		}
+		if !t.Struct && !encodableType(t.Underlying) {
+			continue
+		}
+		name := exportedName(t.Name)
+		if m.taken[name] {
+			name = exportedName(t.Package) + name
+		}
+		ct := &clientType{ClientType: t, name: m.unique(name)}
+		if ident, ok := parseTypeExpr(t.Underlying).(*ast.Ident); ok && basicTypes[ident.Name] != "" {
+			ct.keyable = true
+		}
+		m.types = append(m.types, ct)
+		m.byName[t.Name] = append(m.byName[t.Name], ct)
+	}
+
+	var collect func(groups []*DocGroup)
+	collect = func(groups []*DocGroup) {
+		for _, group := range groups {
+			for _, endpoint := range group.Endpoints {
+				m.endpoints = append(m.endpoints, m.newEndpoint(group, endpoint))
+			}
+			collect(group.Groups)
+		}
+	}
+	collect(sdk.Docs.Groups)
+	return m
+}
+
+func (m *clientModel) newEndpoint(group *DocGroup, endpoint *DocEndpoint) *clientEndpoint {
+	e := &clientEndpoint{DocEndpoint: endpoint, group: group, pathParams: make(map[int]*clientParam)}
+
+	name := ""
+	handler := strings.TrimSuffix(strings.TrimSpace(endpoint.Handler), "()")
+	if i := strings.LastIndex(handler, "."); i >= 0 {
+		handler = handler[i+1:]
+	}
+	if token.IsIdentifier(handler) {
+		name = exportedName(handler)
+	}
+	if name == "" || m.methods[name] {
+		name = exportedName(strings.ToLower(endpoint.Method) + " " + endpoint.Path)
+	}
+	e.name = uniqueLocal(name, m.methods)
+
+	goLocals, tsLocals := map[string]bool{}, map[string]bool{}
+	e.segments = strings.Split(strings.Trim(endpoint.Path, "/"), "/")
+	for i, segment := range e.segments {
+		wire := ""
+		wildcard := false
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			wire = segment[1:]
+		case strings.HasPrefix(segment, "*"):
+			wire, wildcard = segment[1:], true
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			wire = strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+			wildcard = strings.HasSuffix(segment, "...}")
+		default:
+			continue
+		}
+		e.pathParams[i] = &clientParam{
+			wire:     wire,
+			goName:   uniqueLocal(localName(wire, goKeywords), goLocals),
+			tsName:   uniqueLocal(localName(wire, tsKeywords), tsLocals),
+			required: true,
+			wildcard: wildcard,
+		}
+	}
+
+	if len(endpoint.QueryParams) > 0 {
+		e.paramsType = m.unique(e.name + "Params")
+		fields := map[string]bool{}
+		for _, param := range endpoint.QueryParams {
+			e.query = append(e.query, &clientParam{
+				wire:     param.Name,
+				goName:   uniqueLocal(exportedName(param.Name), fields),
+				required: param.Required,
+			})
+		}
+	}
+
+	if endpoint.RequestType != "" {
+		if body, ok := m.resolve("", endpoint.RequestType); ok {
+			e.body = body
+		} else {
+			e.body = &typeExpr{kind: kindAny}
+		}
+	}
+
+	var results []*typeExpr
+	seen := map[string]bool{}
+	for _, response := range endpoint.ResponseTypes {
+		if result, ok := m.resolve("", response); ok && !seen[result.goString()] {
+			seen[result.goString()] = true
+			results = append(results, result)
+		}
+	}
+	if len(results) == 1 && results[0].kind != kindRaw && results[0].kind != kindAny {
+		e.result = results[0]
+	} else {
+		e.result = &typeExpr{kind: kindRaw}
+	}
+	return e
+}
+
+// unique reserves an exported Go identifier, numbering it when already in use
+func (m *clientModel) unique(name string) string {
+	candidate := name
+	for i := 2; m.taken[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	m.taken[candidate] = true
+	return candidate
+}
+
+// lookup finds a generated type; an empty package matches any package
+func (m *clientModel) lookup(pkg, name string) *clientType {
+	for _, t := range m.byName[name] {
+		if pkg == "" || t.Package == pkg {
+			return t
+		}
+	}
+	return nil
+}
+
+// resolve resolves a type expression written in package pkg. It fails for
+// types that cannot be encoded as JSON, such as functions and channels.
+func (m *clientModel) resolve(pkg, expression string) (*typeExpr, bool) {
+	expr := parseTypeExpr(expression)
+	if expr == nil {
+		return &typeExpr{kind: kindRaw}, true
+	}
+	return m.resolveExpr(pkg, expr)
+}
+
+func (m *clientModel) resolveExpr(pkg string, expr ast.Expr) (*typeExpr, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return m.resolveExpr(pkg, e.X)
+	case *ast.Ident:
+		switch {
+		case basicTypes[e.Name] != "":
+			return &typeExpr{kind: kindBasic, name: e.Name}, true
+		case e.Name == "complex64" || e.Name == "complex128":
+			return nil, false
+		case e.Name == "any" || e.Name == "error":
+			return &typeExpr{kind: kindAny}, true
+		}
+		return m.named(pkg, e.Name), true
+	case *ast.SelectorExpr:
+		qualifier, _ := e.X.(*ast.Ident)
+		if qualifier == nil {
+			return &typeExpr{kind: kindRaw}, true
+		}
+		switch qualifier.Name + "." + e.Sel.Name {
+		case "time.Time":
+			return &typeExpr{kind: kindTime}, true
+		case "time.Duration":
+			return &typeExpr{kind: kindDuration}, true
+		case "json.RawMessage":
+			return &typeExpr{kind: kindRaw}, true
+		}
+		if m.lookup(qualifier.Name, e.Sel.Name) == nil {
+			return &typeExpr{kind: kindRaw}, true
+		}
+		return m.named(qualifier.Name, e.Sel.Name), true
+	case *ast.StarExpr:
+		elem, ok := m.resolveExpr(pkg, e.X)
+		if !ok {
+			return nil, false
+		}
+		return &typeExpr{kind: kindPointer, elem: elem}, true
+	case *ast.Ellipsis:
+		return m.resolveExpr(pkg, &ast.ArrayType{Elt: e.Elt})
+	case *ast.ArrayType:
+		if ident, isIdent := e.Elt.(*ast.Ident); isIdent && e.Len == nil && (ident.Name == "byte" || ident.Name == "uint8") {
+			return &typeExpr{kind: kindBytes}, true
+		}
+		elem, ok := m.resolveExpr(pkg, e.Elt)
+		if !ok {
+			return nil, false
+		}
+		return &typeExpr{kind: kindSlice, elem: elem}, true
+	case *ast.MapType:
+		elem, ok := m.resolveExpr(pkg, e.Value)
+		if !ok {
+			return nil, false
+		}
+		key, ok := m.resolveExpr(pkg, e.Key)
+		if !ok || !(key.kind == kindBasic || (key.kind == kindNamed && m.keyable(key.name))) {
+			key = &typeExpr{kind: kindBasic, name: "string"}
+		}
+		return &typeExpr{kind: kindMap, key: key, elem: elem}, true
+	case *ast.InterfaceType:
+		return &typeExpr{kind: kindAny}, true
+	case *ast.FuncType, *ast.ChanType:
+		return nil, false
+	default:
+		// Inline structs and generic instantiations
+		return &typeExpr{kind: kindRaw}, true
+	}
+}
+
+// named resolves a project type name; unknown types stay undecoded
+func (m *clientModel) named(pkg, name string) *typeExpr {
+	t := m.lookup(pkg, name)
+	if t == nil && pkg != "" {
+		t = m.lookup("", name)
+	}
+	switch {
+	case t == nil:
+		return &typeExpr{kind: kindRaw}
+	case t.Underlying == "interface":
+		return &typeExpr{kind: kindAny}
+	default:
+		return &typeExpr{kind: kindNamed, name: t.name}
+	}
+}
+
+func (m *clientModel) keyable(name string) bool {
+	for _, t := range m.types {
+		if t.name == name {
+			return t.keyable
+		}
+	}
+	return false
+}
+
+// nillable reports whether a Go value of the type can be nil
+func (t *typeExpr) nillable() bool {
+	return t.kind == kindPointer || t.kind == kindSlice || t.kind == kindMap || t.kind == kindAny || t.kind == kindRaw || t.kind == kindBytes
+}
+
+// clientField is a struct field as the clients see it
+type clientField struct {
+	*entity.StructField
+	goName   string
+	jsonTag  string // Value of the json tag, empty without one
+	wireName string
+	optional bool // omitempty
+	asString bool // ",string" option
+	embedded bool // Promoted fields of an embedded generated struct
+	typ      *typeExpr
+}
+
+// fields lists the fields of a struct that survive JSON encoding
+func (m *clientModel) fields(t *clientType) []*clientField {
+	var fields []*clientField
+	for _, field := range t.Fields {
+		tag, hasTag := reflect.StructTag(strings.Trim(field.Tag, "`")).Lookup("json")
+		options := strings.Split(tag, ",")
+		if tag == "-" {
+			continue
+		}
+
+		typ, ok := m.resolve(t.Package, field.Type)
+		if !ok {
+			continue
+		}
+
+		cf := &clientField{StructField: field, typ: typ, wireName: options[0], goName: field.Name}
+		if hasTag {
+			cf.jsonTag = tag
+		}
+		for _, option := range options[1:] {
+			cf.optional = cf.optional || option == "omitempty" || option == "omitzero"
+			cf.asString = cf.asString || option == "string"
+		}
+
+		if field.Embedded || field.Name == "" {
+			target := typ
+			if target.kind == kindPointer {
+				target = target.elem
+			}
+			if target.kind != kindNamed {
+				continue
+			}
+			cf.goName = target.name
+			cf.embedded = cf.wireName == ""
+		} else if !ast.IsExported(field.Name) {
+			continue
+		}
+		if cf.wireName == "" {
+			cf.wireName = cf.goName
+		}
+		fields = append(fields, cf)
+	}
+	return fields
+}
+
+// basicTypes maps predeclared Go types that encode as JSON strings, booleans or numbers
+var basicTypes = map[string]string{
+	"string": "string", "bool": "boolean",
+	"int": "number", "int8": "number", "int16": "number", "int32": "number", "int64": "number",
+	"uint": "number", "uint8": "number", "uint16": "number", "uint32": "number", "uint64": "number", "uintptr": "number",
+	"float32": "number", "float64": "number", "byte": "number", "rune": "number",
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+	// Names of the generated methods
+	"c": true, "ctx": true, "method": true, "body": true, "params": true, "query": true,
+	"payload": true, "result": true, "err": true, "url": true,
+}
+
+var tsKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"let": true, "static": true, "yield": true, "await": true, "implements": true,
+	"interface": true, "package": true, "private": true, "protected": true, "public": true,
+	// Names of the generated methods
+	"method": true, "body": true, "params": true, "options": true,
+}
+
+// parseTypeExpr parses a Go type expression, nil when it is not one
+func parseTypeExpr(expression string) ast.Expr {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return nil
+	}
+	return expr
+}
+
+// encodableType reports whether a type expression has no function or channel parts
+func encodableType(expression string) bool {
+	expr := parseTypeExpr(expression)
+	if expr == nil {
+		return false
+	}
+	encodable := true
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FuncType, *ast.ChanType:
+			encodable = false
+		}
+		return encodable
+	})
+	return encodable
+}
+
+// words splits a name or path into its alphanumeric words
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// exportedName turns a name or path into an exported Go identifier
+func exportedName(text string) string {
+	var b strings.Builder
+	for _, word := range words(text) {
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// localName turns a parameter name into a lower camel case variable name
+func localName(text string, reserved map[string]bool) string {
+	name := []rune(exportedName(text))
+	name[0] = unicode.ToLower(name[0])
+	if reserved[string(name)] {
+		return string(name) + "Param"
+	}
+	return string(name)
+}
+
+func uniqueLocal(name string, taken map[string]bool) string {
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// sortedTypes returns the generated types by name
+func (m *clientModel) sortedTypes() []*clientType {
+	sorted := append([]*clientType(nil), m.types...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	return sorted
+}
+
+// docLines splits a doc comment into lines, without trailing blank ones
+func docLines(doc string) []string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return nil
+	}
+	return strings.Split(doc, "\n")
+}
+
+// renderClientReadme explains how to use the generated clients and lists their methods
+func renderClientReadme(m *clientModel) []byte {
+	var b strings.Builder
+	pkg := m.sdk.PackageName
+	b.WriteString("# " + m.title + " API clients\n\n")
+	b.WriteString("Generated by goapianalyzer from the routes and handlers of the project. ")
+	b.WriteString("Regenerate the clients rather than editing them.\n\n")
+
+	b.WriteString("## Go\n\n")
+	b.WriteString("Copy " + code("go/"+pkg) + " into your module. It depends on the standard library only.\n\n")
+	b.WriteString("```go\nclient := " + pkg + ".NewClient(\"http://localhost:8080\")\n")
+	b.WriteString("client.Header.Set(\"Authorization\", \"Bearer <token>\")\n")
+	if len(m.endpoints) > 0 {
+		b.WriteString("result, err := client." + m.endpoints[0].name + "(ctx, ...)\n")
+	}
+	b.WriteString("```\n\n")
+	b.WriteString("Responses with a status outside of 2xx are returned as " + code("*"+pkg+".Error") + ".\n\n")
+
+	b.WriteString("## TypeScript\n\n")
+	b.WriteString("Copy " + code("typescript/client.ts") + " into your project. It uses the global " + code("fetch") + ".\n\n")
+	b.WriteString("```ts\nconst client = new Client({ baseUrl: \"http://localhost:8080\" });\n")
+	if len(m.endpoints) > 0 {
+		b.WriteString("const result = await client." + tsMethodName(m.endpoints[0]) + "(...);\n")
+	}
+	b.WriteString("```\n\n")
+	b.WriteString("Responses with a status outside of 2xx reject with " + code("ApiError") + ".\n\n")
+
+	b.WriteString("## Methods\n\n")
+	if len(m.endpoints) == 0 {
+		b.WriteString("No endpoints were found.\n")
+		return []byte(b.String())
+	}
+	b.WriteString("| Endpoint | Go | TypeScript |\n|---|---|---|\n")
+	for _, e := range m.endpoints {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", code(e.Method+" "+e.Path), code(e.name), code(tsMethodName(e)))
+	}
+	return []byte(b.String())
+}
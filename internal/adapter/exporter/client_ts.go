@@ -0,0 +1,279 @@
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tsIdentifier matches property names that need no quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+const tsClientRuntime = `
+export class ApiError extends Error {
+  readonly status: number;
+  readonly body: string;
+
+  constructor(status: number, body: string) {
+    super(` + "`unexpected status ${status}: ${body.trim()}`" + `);
+    this.name = "ApiError";
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export interface ClientOptions {
+  /** Scheme, host and path prefix of the API */
+  baseUrl: string;
+  /** Sent with every request, for example for authentication */
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  signal?: AbortSignal;
+}
+
+type Query = { [name: string]: string | undefined };
+
+/** Client calls the %s API */
+export class Client {
+  private readonly baseUrl: string;
+  private readonly headers: Record<string, string>;
+  private readonly fetchFn: typeof fetch;
+
+  constructor(options: ClientOptions) {
+    this.baseUrl = options.baseUrl.replace(/\/+$/, "");
+    this.headers = options.headers ?? {};
+    this.fetchFn = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  /**
+   * Sends a request with an optional JSON body. JSON responses are parsed, others
+   * are returned as text.
+   */
+  private async request<T>(
+    method: string,
+    path: string,
+    query: Query | undefined,
+    body: unknown,
+    options: RequestOptions | undefined,
+  ): Promise<T> {
+    let url = this.baseUrl + path;
+    if (query) {
+      const search = new URLSearchParams();
+      for (const [name, value] of Object.entries(query)) {
+        if (value !== undefined && value !== "") {
+          search.set(name, value);
+        }
+      }
+      const encoded = search.toString();
+      if (encoded) {
+        url += "?" + encoded;
+      }
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json", ...this.headers };
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const response = await this.fetchFn(url, {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+      signal: options?.signal,
+    });
+
+    const text = await response.text();
+    if (!response.ok) {
+      throw new ApiError(response.status, text);
+    }
+    const contentType = response.headers.get("Content-Type") ?? "";
+    return (contentType.includes("json") && text ? JSON.parse(text) : text) as T;
+  }
+`
+
+// renderTSClient writes a TypeScript client: interfaces mirroring the JSON of the
+// generated types and a Client class with a method per endpoint
+func renderTSClient(m *clientModel) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by goapianalyzer. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Client of the %s API.\n", m.title)
+
+	for _, t := range m.types {
+		writeTSType(&b, m, t)
+	}
+	for _, e := range m.endpoints {
+		if e.paramsType == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n/** Query parameters of %s */\nexport interface %s {\n", tsMethodName(e), e.paramsType)
+		for _, param := range e.query {
+			optional := "?"
+			if param.required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: string;\n", tsKey(param.wire), optional)
+		}
+		b.WriteString("}\n")
+	}
+
+	fmt.Fprintf(&b, tsClientRuntime, m.title)
+	var group *DocGroup
+	for _, e := range m.endpoints {
+		if e.group != group {
+			group = e.group
+			fmt.Fprintf(&b, "\n  // %s\n", groupName(group))
+		}
+		writeTSMethod(&b, e)
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+func (t *typeExpr) tsString() string {
+	switch t.kind {
+	case kindBasic:
+		return basicTypes[t.name]
+	case kindNamed:
+		return t.name
+	case kindPointer:
+		return t.elem.tsString() + " | null"
+	case kindSlice:
+		elem := t.elem.tsString()
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case kindMap:
+		return "Record<string, " + t.elem.tsString() + ">"
+	case kindTime, kindBytes:
+		return "string"
+	case kindDuration:
+		return "number"
+	default:
+		return "unknown"
+	}
+}
+
+func writeTSType(b *strings.Builder, m *clientModel, t *clientType) {
+	b.WriteString("\n")
+	writeTSDoc(b, "", docLines(t.Doc))
+
+	if !t.Struct {
+		underlying, ok := m.resolve(t.Package, t.Underlying)
+		if !ok {
+			underlying = &typeExpr{kind: kindAny}
+		}
+		fmt.Fprintf(b, "export type %s = %s;\n", t.name, underlying.tsString())
+		return
+	}
+
+	var extends []string
+	var lines []string
+	for _, field := range m.fields(t) {
+		if field.embedded {
+			extends = append(extends, field.goName)
+			continue
+		}
+		typ := field.typ.tsString()
+		if field.asString {
+			typ = "string"
+		}
+		optional := ""
+		if field.optional {
+			optional = "?"
+		}
+		lines = append(lines, fmt.Sprintf("  %s%s: %s;\n", tsKey(field.wireName), optional, typ))
+	}
+
+	b.WriteString("export interface " + t.name)
+	if len(extends) > 0 {
+		b.WriteString(" extends " + strings.Join(extends, ", "))
+	}
+	b.WriteString(" {\n" + strings.Join(lines, "") + "}\n")
+}
+
+func writeTSMethod(b *strings.Builder, e *clientEndpoint) {
+	b.WriteString("\n")
+	lines := []string{fmt.Sprintf("%s %s, handled by %s.", e.Method, e.Path, strings.TrimSpace(e.Handler))}
+	if doc := docLines(e.Doc); len(doc) > 0 {
+		lines = append(append(lines, ""), doc...)
+	}
+	writeTSDoc(b, "  ", lines)
+
+	var args []string
+	method := strconv.Quote(e.Method)
+	if e.Method == "ANY" {
+		args = append(args, "method: string")
+		method = "method"
+	}
+
+	path := ""
+	for i, segment := range e.segments {
+		param := e.pathParams[i]
+		if param == nil {
+			path += "/" + strings.NewReplacer("`", "\\`", "${", "\\${").Replace(segment)
+			continue
+		}
+		args = append(args, param.tsName+": string")
+		if param.wildcard {
+			path += "/${" + param.tsName + ".replace(/^\\//, \"\")}"
+		} else {
+			path += "/${encodeURIComponent(" + param.tsName + ")}"
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	body := "undefined"
+	if e.body != nil {
+		args = append(args, "body: "+e.body.tsString())
+		body = "body"
+	}
+	query := "undefined"
+	if e.paramsType != "" {
+		required := false
+		for _, param := range e.query {
+			required = required || param.required
+		}
+		if required {
+			args = append(args, "params: "+e.paramsType)
+		} else {
+			args = append(args, "params?: "+e.paramsType)
+		}
+		query = "params as Query | undefined"
+	}
+	args = append(args, "options?: RequestOptions")
+
+	result := e.result.tsString()
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", tsMethodName(e), strings.Join(args, ", "), result)
+	fmt.Fprintf(b, "    return this.request<%s>(%s, `%s`, %s, %s, options);\n  }\n", result, method, path, query, body)
+}
+
+func writeTSDoc(b *strings.Builder, indent string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "*/", "*\\/")
+		b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+}
+
+// tsMethodName is the Go method name in lower camel case
+func tsMethodName(e *clientEndpoint) string {
+	return strings.ToLower(e.name[:1]) + e.name[1:]
+}
+
+// tsKey quotes property names that are not identifiers
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
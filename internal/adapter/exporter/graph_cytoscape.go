@@ -0,0 +1,75 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// cytoscapeElement is a node or edge in the Cytoscape.js elements JSON format
+type cytoscapeElement struct {
+	Data    map[string]interface{} `json:"data"`
+	Classes string                 `json:"classes,omitempty"`
+}
+
+type cytoscapeGraph struct {
+	Elements struct {
+		Nodes []*cytoscapeElement `json:"nodes"`
+		Edges []*cytoscapeElement `json:"edges"`
+	} `json:"elements"`
+}
+
+// writeCytoscape renders a graph for Cytoscape.js; packages become compound parent nodes
+func writeCytoscape(graph *entity.DependencyGraph, options *GraphOptions) ([]byte, error) {
+	var document cytoscapeGraph
+	document.Elements.Nodes = make([]*cytoscapeElement, 0, len(graph.Nodes))
+	document.Elements.Edges = make([]*cytoscapeElement, 0, len(graph.Dependencies))
+
+	if options.ClusterByPackage {
+		order, _, _ := packageClusters(graph.Nodes)
+		for _, pkg := range order {
+			document.Elements.Nodes = append(document.Elements.Nodes, &cytoscapeElement{
+				Data:    map[string]interface{}{"id": "package_" + pkg, "label": pkg, "type": "package"},
+				Classes: "package",
+			})
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		style := styleOf(node)
+		data := map[string]interface{}{
+			"id":    node.ID,
+			"label": nodeLabel(node),
+			"name":  node.Name,
+			"type":  node.Type,
+			"shape": style.shape,
+			"color": style.color,
+		}
+		if node.File != "" {
+			data["file"] = node.File
+		}
+		if node.Package != "" {
+			data["package"] = node.Package
+			if options.ClusterByPackage {
+				data["parent"] = "package_" + node.Package
+			}
+		}
+		document.Elements.Nodes = append(document.Elements.Nodes, &cytoscapeElement{Data: data, Classes: node.Type})
+	}
+
+	for i, dependency := range graph.Dependencies {
+		document.Elements.Edges = append(document.Elements.Edges, &cytoscapeElement{
+			Data: map[string]interface{}{
+				"id":       fmt.Sprintf("e%d", i),
+				"source":   dependency.From,
+				"target":   dependency.To,
+				"kind":     dependency.Type,
+				"strength": dependency.Strength,
+			},
+			Classes: dependency.Type,
+		})
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
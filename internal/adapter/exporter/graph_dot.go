@@ -0,0 +1,66 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// writeDOT renders a graph for Graphviz, one cluster subgraph per package
+func writeDOT(graph *entity.DependencyGraph, options *GraphOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("digraph dependencies {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  compound=true;\n")
+	buf.WriteString("  node [fontname=\"Helvetica\", fontsize=10, style=filled];\n")
+	buf.WriteString("  edge [fontname=\"Helvetica\", fontsize=8, color=\"#6b7280\"];\n")
+
+	writeNode := func(node *entity.DependencyNode, indent string) {
+		style := styleOf(node)
+		fmt.Fprintf(&buf, "%s%s [label=%s, shape=%s, fillcolor=%s, tooltip=%s];\n",
+			indent, dotID(node.ID), dotID(nodeLabel(node)), style.shape, dotID(style.color), dotID(node.Name))
+	}
+
+	if options.ClusterByPackage {
+		order, clusters, loose := packageClusters(graph.Nodes)
+		for i, pkg := range order {
+			fmt.Fprintf(&buf, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(&buf, "    label=%s;\n", dotID(pkg))
+			buf.WriteString("    style=rounded;\n")
+			buf.WriteString("    color=\"#9ca3af\";\n")
+			for _, node := range clusters[pkg] {
+				writeNode(node, "    ")
+			}
+			buf.WriteString("  }\n")
+		}
+		for _, node := range loose {
+			writeNode(node, "  ")
+		}
+	} else {
+		for _, node := range graph.Nodes {
+			writeNode(node, "  ")
+		}
+	}
+
+	labeled := len(edgeKinds(graph.Dependencies)) > 1
+	for _, dependency := range graph.Dependencies {
+		fmt.Fprintf(&buf, "  %s -> %s", dotID(dependency.From), dotID(dependency.To))
+		if labeled {
+			fmt.Fprintf(&buf, " [label=%s]", dotID(dependency.Type))
+		}
+		buf.WriteString(";\n")
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// dotID quotes an identifier or label for DOT
+func dotID(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
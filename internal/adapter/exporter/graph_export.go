@@ -0,0 +1,280 @@
+package exporter
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// Graph formats
+const (
+	FormatDOT       = "dot"
+	FormatGraphML   = "graphml"
+	FormatMermaid   = "mermaid"
+	FormatCytoscape = "cytoscape"
+)
+
+// DefaultMaxNodes bounds rendered graphs when no limit is given; larger graphs
+// are unreadable in Graphviz or Mermaid anyway
+const DefaultMaxNodes = 300
+
+const collapsedNodeType = "collapsed"
+
+// GraphOptions select and shape the part of a dependency graph that is exported
+type GraphOptions struct {
+	EdgeKinds        []string // Only keep edges of these kinds; empty keeps all
+	MaxNodes         int      // Collapse the least connected nodes beyond this count; 0 keeps all
+	ClusterByPackage bool     // Group nodes of the same package into subgraphs
+}
+
+// graphWriter renders a prepared graph
+type graphWriter func(graph *entity.DependencyGraph, options *GraphOptions) ([]byte, error)
+
+var graphWriters = map[string]graphWriter{
+	FormatDOT:       writeDOT,
+	FormatGraphML:   writeGraphML,
+	FormatMermaid:   writeMermaid,
+	FormatCytoscape: writeCytoscape,
+}
+
+var graphContentTypes = map[string]string{
+	FormatDOT:       "text/vnd.graphviz; charset=utf-8",
+	FormatGraphML:   "application/graphml+xml",
+	FormatMermaid:   "text/plain; charset=utf-8",
+	FormatCytoscape: "application/json",
+}
+
+// IsGraphFormat reports whether a format is one ExportGraph renders
+func IsGraphFormat(format string) bool {
+	_, exists := graphWriters[strings.ToLower(format)]
+	return exists
+}
+
+// GraphContentType returns the media type of a graph format
+func GraphContentType(format string) string {
+	return graphContentTypes[strings.ToLower(format)]
+}
+
+// ExportGraph renders a dependency graph as DOT, GraphML, Mermaid or Cytoscape JSON
+func ExportGraph(graph *entity.DependencyGraph, format string, options *GraphOptions) ([]byte, error) {
+	writer, exists := graphWriters[strings.ToLower(format)]
+	if !exists {
+		return nil, errors.NewValidationError("unsupported graph format: " + format)
+	}
+	if options == nil {
+		options = &GraphOptions{}
+	}
+	return writer(PrepareGraph(graph, options), options)
+}
+
+// PrepareGraph removes duplicate nodes, keeps the edges of the selected kinds and
+// collapses the least connected nodes of each package once MaxNodes is exceeded
+func PrepareGraph(graph *entity.DependencyGraph, options *GraphOptions) *entity.DependencyGraph {
+	prepared := &entity.DependencyGraph{
+		Nodes:        make([]*entity.DependencyNode, 0),
+		Dependencies: make([]*entity.Dependency, 0),
+	}
+	if graph == nil {
+		return prepared
+	}
+
+	nodes := make(map[string]*entity.DependencyNode)
+	for _, node := range graph.Nodes {
+		if _, exists := nodes[node.ID]; !exists {
+			nodes[node.ID] = node
+			prepared.Nodes = append(prepared.Nodes, node)
+		}
+	}
+
+	kinds := make(map[string]bool, len(options.EdgeKinds))
+	for _, kind := range options.EdgeKinds {
+		kinds[strings.ToLower(kind)] = true
+	}
+	connected := make(map[string]bool)
+	for _, dependency := range graph.Dependencies {
+		if len(kinds) > 0 && !kinds[strings.ToLower(dependency.Type)] {
+			continue
+		}
+		if nodes[dependency.From] == nil || nodes[dependency.To] == nil {
+			continue
+		}
+		prepared.Dependencies = append(prepared.Dependencies, dependency)
+		connected[dependency.From] = true
+		connected[dependency.To] = true
+	}
+
+	// Filtering edges by kind also drops the nodes it leaves alone
+	if len(kinds) > 0 {
+		kept := prepared.Nodes[:0]
+		for _, node := range prepared.Nodes {
+			if connected[node.ID] {
+				kept = append(kept, node)
+			}
+		}
+		prepared.Nodes = kept
+	}
+
+	if options.MaxNodes > 0 && len(prepared.Nodes) > options.MaxNodes {
+		collapseGraph(prepared, options.MaxNodes)
+	}
+	return prepared
+}
+
+// collapseGraph keeps the most connected nodes and merges the others into one
+// node per package, so that at most maxNodes remain
+func collapseGraph(graph *entity.DependencyGraph, maxNodes int) {
+	degree := make(map[string]int)
+	for _, dependency := range graph.Dependencies {
+		degree[dependency.From]++
+		degree[dependency.To]++
+	}
+
+	nodes := append([]*entity.DependencyNode(nil), graph.Nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if degree[nodes[i].ID] != degree[nodes[j].ID] {
+			return degree[nodes[i].ID] > degree[nodes[j].ID]
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	// Move nodes to the collapsed part until the kept nodes and one group per
+	// package of the collapsed ones fit
+	kept := maxNodes
+	groups := make(map[string]int)
+	for i := len(nodes) - 1; i >= kept; i-- {
+		groups[nodes[i].Package]++
+	}
+	for kept > 0 && kept+len(groups) > maxNodes {
+		kept--
+		groups[nodes[kept].Package]++
+	}
+	merged := kept == 0 || kept+len(groups) > maxNodes
+	if merged {
+		// Too many packages: a single group takes every collapsed node
+		kept = maxNodes - 1
+		groups = map[string]int{"": len(nodes) - kept}
+	}
+
+	alias := make(map[string]string)
+	graph.Nodes = nodes[:kept:kept]
+	groupNodes := make(map[string]*entity.DependencyNode)
+	for _, node := range nodes[kept:] {
+		pkg := node.Package
+		if merged {
+			pkg = ""
+		}
+		group, exists := groupNodes[pkg]
+		if !exists {
+			id := collapsedNodeType
+			if pkg != "" {
+				id += "_" + pkg
+			}
+			group = &entity.DependencyNode{
+				ID:      id,
+				Name:    fmt.Sprintf("%d more", groups[pkg]),
+				Type:    collapsedNodeType,
+				Package: pkg,
+			}
+			groupNodes[pkg] = group
+			graph.Nodes = append(graph.Nodes, group)
+		}
+		alias[node.ID] = group.ID
+	}
+
+	// Redirect edges to the groups, merging the duplicates this creates
+	type edgeKey struct{ from, to, kind string }
+	dependencies := make([]*entity.Dependency, 0, len(graph.Dependencies))
+	index := make(map[edgeKey]*entity.Dependency)
+	for _, dependency := range graph.Dependencies {
+		from, to := dependency.From, dependency.To
+		if target, exists := alias[from]; exists {
+			from = target
+		}
+		if target, exists := alias[to]; exists {
+			to = target
+		}
+		if from == to {
+			continue
+		}
+		key := edgeKey{from: from, to: to, kind: dependency.Type}
+		if existing, exists := index[key]; exists {
+			if dependency.Strength > existing.Strength {
+				existing.Strength = dependency.Strength
+			}
+			continue
+		}
+		edge := &entity.Dependency{From: from, To: to, Type: dependency.Type, Strength: dependency.Strength}
+		index[key] = edge
+		dependencies = append(dependencies, edge)
+	}
+	graph.Dependencies = dependencies
+}
+
+// nodeLabel is the text shown for a node: files are named within their package cluster
+func nodeLabel(node *entity.DependencyNode) string {
+	if node.Type == "file" {
+		name := node.File
+		if name == "" {
+			name = node.Name
+		}
+		return path.Base(name)
+	}
+	return node.Name
+}
+
+// nodeStyle is the rendering of a node type shared by the formats
+type nodeStyle struct {
+	shape string // Graphviz shape; other formats map it to their own
+	color string
+}
+
+var nodeStyles = map[string]nodeStyle{
+	"file":            {shape: "box", color: "#dbeafe"},
+	"import":          {shape: "ellipse", color: "#e5e7eb"},
+	"function":        {shape: "box", color: "#dcfce7"},
+	"struct":          {shape: "component", color: "#fef3c7"},
+	"interface":       {shape: "hexagon", color: "#fae8ff"},
+	"api":             {shape: "cds", color: "#fee2e2"},
+	collapsedNodeType: {shape: "folder", color: "#f3f4f6"},
+}
+
+func styleOf(node *entity.DependencyNode) nodeStyle {
+	if style, exists := nodeStyles[node.Type]; exists {
+		return style
+	}
+	return nodeStyle{shape: "ellipse", color: "#ffffff"}
+}
+
+// packageClusters groups nodes by package in order of first appearance; nodes
+// without a package are returned apart
+func packageClusters(nodes []*entity.DependencyNode) ([]string, map[string][]*entity.DependencyNode, []*entity.DependencyNode) {
+	var order []string
+	clusters := make(map[string][]*entity.DependencyNode)
+	var loose []*entity.DependencyNode
+	for _, node := range nodes {
+		if node.Package == "" {
+			loose = append(loose, node)
+			continue
+		}
+		if _, exists := clusters[node.Package]; !exists {
+			order = append(order, node.Package)
+		}
+		clusters[node.Package] = append(clusters[node.Package], node)
+	}
+	sort.Strings(order)
+	return order, clusters, loose
+}
+
+// edgeKinds returns the distinct kinds of the edges; labels are only worth
+// drawing when there is more than one
+func edgeKinds(dependencies []*entity.Dependency) map[string]bool {
+	kinds := make(map[string]bool)
+	for _, dependency := range dependencies {
+		kinds[dependency.Type] = true
+	}
+	return kinds
+}
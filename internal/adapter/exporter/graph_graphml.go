@@ -0,0 +1,126 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphMLDocument struct {
+	XMLName xml.Name      `xml:"graphml"`
+	XMLNS   string        `xml:"xmlns,attr"`
+	Keys    []*graphMLKey `xml:"key"`
+	Graph   *graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Type    string `xml:"attr.type,attr"`
+	Default string `xml:"default,omitempty"`
+}
+
+type graphMLGraph struct {
+	ID          string         `xml:"id,attr"`
+	EdgeDefault string         `xml:"edgedefault,attr"`
+	Nodes       []*graphMLNode `xml:"node"`
+	Edges       []*graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID    string         `xml:"id,attr"`
+	Data  []*graphMLData `xml:"data"`
+	Graph *graphMLGraph  `xml:"graph,omitempty"`
+}
+
+type graphMLEdge struct {
+	ID     string         `xml:"id,attr"`
+	Source string         `xml:"source,attr"`
+	Target string         `xml:"target,attr"`
+	Data   []*graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+var graphMLKeys = []*graphMLKey{
+	{ID: "label", For: "node", Name: "label", Type: "string"},
+	{ID: "type", For: "node", Name: "type", Type: "string"},
+	{ID: "package", For: "node", Name: "package", Type: "string"},
+	{ID: "file", For: "node", Name: "file", Type: "string"},
+	{ID: "color", For: "node", Name: "color", Type: "string"},
+	{ID: "kind", For: "edge", Name: "kind", Type: "string"},
+	{ID: "strength", For: "edge", Name: "strength", Type: "int"},
+}
+
+// writeGraphML renders a graph as GraphML; packages become nested group graphs,
+// which yEd shows as folder nodes
+func writeGraphML(graph *entity.DependencyGraph, options *GraphOptions) ([]byte, error) {
+	root := &graphMLGraph{ID: "dependencies", EdgeDefault: "directed"}
+
+	if options.ClusterByPackage {
+		order, clusters, loose := packageClusters(graph.Nodes)
+		for i, pkg := range order {
+			group := &graphMLNode{
+				ID: fmt.Sprintf("package_%d", i),
+				Data: []*graphMLData{
+					{Key: "label", Value: pkg},
+					{Key: "type", Value: "package"},
+				},
+				Graph: &graphMLGraph{ID: fmt.Sprintf("package_%d:", i), EdgeDefault: "directed"},
+			}
+			for _, node := range clusters[pkg] {
+				group.Graph.Nodes = append(group.Graph.Nodes, graphMLNodeOf(node))
+			}
+			root.Nodes = append(root.Nodes, group)
+		}
+		for _, node := range loose {
+			root.Nodes = append(root.Nodes, graphMLNodeOf(node))
+		}
+	} else {
+		for _, node := range graph.Nodes {
+			root.Nodes = append(root.Nodes, graphMLNodeOf(node))
+		}
+	}
+
+	for i, dependency := range graph.Dependencies {
+		root.Edges = append(root.Edges, &graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: dependency.From,
+			Target: dependency.To,
+			Data: []*graphMLData{
+				{Key: "kind", Value: dependency.Type},
+				{Key: "strength", Value: strconv.Itoa(dependency.Strength)},
+			},
+		})
+	}
+
+	document := &graphMLDocument{XMLNS: graphMLNamespace, Keys: graphMLKeys, Graph: root}
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+func graphMLNodeOf(node *entity.DependencyNode) *graphMLNode {
+	data := []*graphMLData{
+		{Key: "label", Value: nodeLabel(node)},
+		{Key: "type", Value: node.Type},
+	}
+	if node.Package != "" {
+		data = append(data, &graphMLData{Key: "package", Value: node.Package})
+	}
+	if node.File != "" {
+		data = append(data, &graphMLData{Key: "file", Value: node.File})
+	}
+	data = append(data, &graphMLData{Key: "color", Value: styleOf(node).color})
+	return &graphMLNode{ID: node.ID, Data: data}
+}
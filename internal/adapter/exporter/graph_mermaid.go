@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// mermaidShapes maps Graphviz shapes to Mermaid flowchart node delimiters
+var mermaidShapes = map[string][2]string{
+	"box":       {"[", "]"},
+	"ellipse":   {"([", "])"},
+	"component": {"[[", "]]"},
+	"hexagon":   {"{{", "}}"},
+	"cds":       {">", "]"},
+	"folder":    {"[/", "/]"},
+}
+
+// writeMermaid renders a graph as a Mermaid flowchart, one subgraph per package.
+// Node IDs are replaced by short generated ones, which Mermaid requires.
+func writeMermaid(graph *entity.DependencyGraph, options *GraphOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	types := make(map[string][]string)
+	writeNode := func(node *entity.DependencyNode, indent string) {
+		shape, exists := mermaidShapes[styleOf(node).shape]
+		if !exists {
+			shape = mermaidShapes["ellipse"]
+		}
+		fmt.Fprintf(&buf, "%s%s%s%s%s\n", indent, ids[node.ID], shape[0], mermaidText(nodeLabel(node)), shape[1])
+		types[node.Type] = append(types[node.Type], ids[node.ID])
+	}
+
+	if options.ClusterByPackage {
+		order, clusters, loose := packageClusters(graph.Nodes)
+		for i, pkg := range order {
+			fmt.Fprintf(&buf, "  subgraph p%d[%s]\n", i, mermaidText(pkg))
+			for _, node := range clusters[pkg] {
+				writeNode(node, "    ")
+			}
+			buf.WriteString("  end\n")
+		}
+		for _, node := range loose {
+			writeNode(node, "  ")
+		}
+	} else {
+		for _, node := range graph.Nodes {
+			writeNode(node, "  ")
+		}
+	}
+
+	labeled := len(edgeKinds(graph.Dependencies)) > 1
+	for _, dependency := range graph.Dependencies {
+		if labeled {
+			fmt.Fprintf(&buf, "  %s -->|%s| %s\n", ids[dependency.From], mermaidText(dependency.Type), ids[dependency.To])
+		} else {
+			fmt.Fprintf(&buf, "  %s --> %s\n", ids[dependency.From], ids[dependency.To])
+		}
+	}
+
+	// One class per node type carries its style
+	typeNames := make([]string, 0, len(types))
+	for nodeType := range types {
+		typeNames = append(typeNames, nodeType)
+	}
+	sort.Strings(typeNames)
+	for _, nodeType := range typeNames {
+		class := "t_" + mermaidClass(nodeType)
+		fmt.Fprintf(&buf, "  classDef %s fill:%s,stroke:#6b7280\n", class, styleOf(&entity.DependencyNode{Type: nodeType}).color)
+		fmt.Fprintf(&buf, "  class %s %s\n", strings.Join(types[nodeType], ","), class)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// mermaidText quotes a label, escaping the characters Mermaid would parse
+func mermaidText(text string) string {
+	text = strings.ReplaceAll(text, `"`, "#quot;")
+	text = strings.ReplaceAll(text, "\n", " ")
+	return `"` + text + `"`
+}
+
+func mermaidClass(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
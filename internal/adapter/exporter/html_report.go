@@ -0,0 +1,323 @@
+package exporter
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// Limits keeping reports of large projects readable
+const (
+	reportHotspots      = 25
+	reportMaxGraphNodes = 150
+)
+
+var (
+	//go:embed report/report.html.tmpl
+	reportTemplateSource string
+	//go:embed report/report.css
+	reportCSS string
+	//go:embed report/report.js
+	reportJS string
+
+	reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+	}).Parse(reportTemplateSource))
+)
+
+// Report is what the HTML report shows of a project analysis
+type Report struct {
+	Analysis    *entity.ProjectAnalysis
+	Nodes       []*entity.CodeNode
+	CallTrees   map[string]*entity.CallTree // Callees of each endpoint handler, by endpoint ID
+	GeneratedAt time.Time
+}
+
+// reportView is the data of the report template
+type reportView struct {
+	Title       string
+	Analysis    *entity.ProjectAnalysis
+	GeneratedAt time.Time
+	Summary     []reportFigure
+	Endpoints   []*reportEndpoint
+	Packages    []*packageMetrics
+	Hotspots    []*complexityHotspot
+	Data        *reportData // Rendered by the script: symbol list and dependency graph
+	CSS         template.CSS
+	JS          template.JS
+}
+
+type reportFigure struct {
+	Label string
+	Value int
+}
+
+type reportEndpoint struct {
+	*entity.APIEndpoint
+	Anchor   string
+	CallTree *entity.CallTree
+}
+
+type reportData struct {
+	Symbols []*reportSymbol `json:"symbols"`
+	Graph   *reportGraph    `json:"graph"`
+}
+
+type reportSymbol struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Package    string `json:"package"`
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Complexity int    `json:"complexity,omitempty"`
+}
+
+type reportGraph struct {
+	Nodes     []*reportGraphNode `json:"nodes"`
+	Edges     [][2]int           `json:"edges"`
+	Collapsed bool               `json:"collapsed"`
+}
+
+type reportGraphNode struct {
+	Label   string `json:"label"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Package string `json:"package,omitempty"`
+	Color   string `json:"color"`
+}
+
+// packageMetrics sums up the size and complexity of a package
+type packageMetrics struct {
+	Package       string  `json:"package"` // Import path, or name when unknown
+	Files         int     `json:"files"`
+	Lines         int     `json:"lines"`
+	Functions     int     `json:"functions"`
+	Methods       int     `json:"methods"`
+	Structs       int     `json:"structs"`
+	Interfaces    int     `json:"interfaces"`
+	Endpoints     int     `json:"endpoints"`
+	AvgComplexity float64 `json:"avg_complexity"`
+	MaxComplexity int     `json:"max_complexity"`
+}
+
+// complexityHotspot is one of the most complex functions of a project
+type complexityHotspot struct {
+	Function   string `json:"function"`
+	Package    string `json:"package"`
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Complexity int    `json:"complexity"`
+	Lines      int    `json:"lines"`
+}
+
+// RenderHTMLReport renders a single self-contained HTML page describing a project:
+// endpoints with their call trees, package metrics, complexity hotspots, a
+// searchable symbol list and a dependency graph. Styles and scripts are inlined.
+func RenderHTMLReport(report *Report) ([]byte, error) {
+	analysis := report.Analysis
+	view := &reportView{
+		Title:       path.Base(analysis.ProjectPath),
+		Analysis:    analysis,
+		GeneratedAt: report.GeneratedAt,
+		Packages:    computePackageMetrics(analysis),
+		Hotspots:    computeHotspots(analysis, reportHotspots),
+		Data: &reportData{
+			Symbols: reportSymbols(report.Nodes),
+			Graph:   reportDependencyGraph(analysis.DependencyGraph),
+		},
+		CSS: template.CSS(reportCSS),
+		JS:  template.JS(reportJS),
+	}
+
+	endpoints := append([]*entity.APIEndpoint(nil), analysis.APIEndpoints...)
+	sortEndpoints(endpoints)
+	for i, endpoint := range endpoints {
+		view.Endpoints = append(view.Endpoints, &reportEndpoint{
+			APIEndpoint: endpoint,
+			Anchor:      "endpoint-" + strconv.Itoa(i+1),
+			CallTree:    report.CallTrees[endpoint.ID],
+		})
+	}
+
+	functions := 0
+	for _, fileInfo := range analysis.Files {
+		functions += len(fileInfo.Functions)
+	}
+	view.Summary = []reportFigure{
+		{Label: "Endpoints", Value: len(analysis.APIEndpoints)},
+		{Label: "Packages", Value: len(view.Packages)},
+		{Label: "Files", Value: len(analysis.Files)},
+		{Label: "Functions", Value: functions},
+		{Label: "Symbols", Value: len(report.Nodes)},
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, view); err != nil {
+		return nil, errors.NewSystemError("failed to render HTML report: " + err.Error())
+	}
+	return buf.Bytes(), nil
+}
+
+// computePackageMetrics sums up the files of an analysis per package, sorted by package
+func computePackageMetrics(analysis *entity.ProjectAnalysis) []*packageMetrics {
+	metrics := make(map[string]*packageMetrics)
+	complexity := make(map[string]int)
+	for _, filePath := range sortedKeys(analysis.Files) {
+		fileInfo := analysis.Files[filePath]
+		pkg := fileInfo.ImportPath
+		if pkg == "" {
+			pkg = fileInfo.PackageName
+		}
+		m, exists := metrics[pkg]
+		if !exists {
+			m = &packageMetrics{Package: pkg}
+			metrics[pkg] = m
+		}
+
+		m.Files++
+		m.Lines += strings.Count(fileInfo.Content, "\n")
+		m.Structs += len(fileInfo.Structs)
+		m.Interfaces += len(fileInfo.Interfaces)
+		for _, function := range fileInfo.Functions {
+			if function.IsMethod {
+				m.Methods++
+			} else {
+				m.Functions++
+			}
+			complexity[pkg] += function.Complexity
+			if function.Complexity > m.MaxComplexity {
+				m.MaxComplexity = function.Complexity
+			}
+		}
+	}
+
+	for _, endpoint := range analysis.APIEndpoints {
+		if fileInfo := analysis.Files[endpoint.File]; fileInfo != nil {
+			pkg := fileInfo.ImportPath
+			if pkg == "" {
+				pkg = fileInfo.PackageName
+			}
+			metrics[pkg].Endpoints++
+		}
+	}
+
+	result := make([]*packageMetrics, 0, len(metrics))
+	for _, pkg := range sortedKeys(metrics) {
+		m := metrics[pkg]
+		if count := m.Functions + m.Methods; count > 0 {
+			m.AvgComplexity = float64(complexity[pkg]*10/count) / 10
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// computeHotspots returns the most complex functions of an analysis
+func computeHotspots(analysis *entity.ProjectAnalysis, limit int) []*complexityHotspot {
+	var hotspots []*complexityHotspot
+	for _, filePath := range sortedKeys(analysis.Files) {
+		fileInfo := analysis.Files[filePath]
+		for _, function := range fileInfo.Functions {
+			name := function.Name
+			if function.Receiver != "" {
+				name = "(" + function.Receiver + ")." + name
+			}
+			hotspot := &complexityHotspot{
+				Function:   name,
+				Package:    fileInfo.PackageName,
+				File:       filePath,
+				Complexity: function.Complexity,
+				Lines:      strings.Count(function.Body, "\n") + 1,
+			}
+			if function.Position != nil {
+				hotspot.Line = function.Position.Line
+			}
+			hotspots = append(hotspots, hotspot)
+		}
+	}
+
+	sort.SliceStable(hotspots, func(i, j int) bool {
+		if hotspots[i].Complexity != hotspots[j].Complexity {
+			return hotspots[i].Complexity > hotspots[j].Complexity
+		}
+		return hotspots[i].Lines > hotspots[j].Lines
+	})
+	if len(hotspots) > limit {
+		hotspots = hotspots[:limit]
+	}
+	return hotspots
+}
+
+func reportSymbols(nodes []*entity.CodeNode) []*reportSymbol {
+	symbols := make([]*reportSymbol, 0, len(nodes))
+	for _, node := range nodes {
+		symbol := &reportSymbol{Name: node.Name, Type: node.Type, Package: node.Package, File: node.File}
+		if node.Position != nil {
+			symbol.Line = node.Position.Line
+		}
+		// Snapshots loaded from disk decode numbers as float64
+		switch complexity := node.Metadata["complexity"].(type) {
+		case int:
+			symbol.Complexity = complexity
+		case float64:
+			symbol.Complexity = int(complexity)
+		}
+		if receiver, ok := node.Metadata["receiver"].(string); ok && receiver != "" {
+			symbol.Name = "(" + receiver + ")." + node.Name
+		}
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Name != symbols[j].Name {
+			return symbols[i].Name < symbols[j].Name
+		}
+		return symbols[i].File < symbols[j].File
+	})
+	return symbols
+}
+
+// reportDependencyGraph indexes the nodes of the collapsed dependency graph for the script
+func reportDependencyGraph(graph *entity.DependencyGraph) *reportGraph {
+	prepared := PrepareGraph(graph, &GraphOptions{MaxNodes: reportMaxGraphNodes})
+	result := &reportGraph{
+		Nodes: make([]*reportGraphNode, 0, len(prepared.Nodes)),
+		Edges: make([][2]int, 0, len(prepared.Dependencies)),
+	}
+
+	index := make(map[string]int, len(prepared.Nodes))
+	for i, node := range prepared.Nodes {
+		index[node.ID] = i
+		if node.Type == collapsedNodeType {
+			result.Collapsed = true
+		}
+		result.Nodes = append(result.Nodes, &reportGraphNode{
+			Label:   nodeLabel(node),
+			Title:   node.Name,
+			Type:    node.Type,
+			Package: node.Package,
+			Color:   styleOf(node).color,
+		})
+	}
+	for _, dependency := range prepared.Dependencies {
+		result.Edges = append(result.Edges, [2]int{index[dependency.From], index[dependency.To]})
+	}
+	return result
+}
+
+func sortEndpoints(endpoints []*entity.APIEndpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+}
@@ -0,0 +1,23 @@
+package exporter
+
+import (
+	"testing"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+func TestReportSymbolsComplexity(t *testing.T) {
+	nodes := []*entity.CodeNode{
+		{ID: "n1", Name: "Scan", Type: "function", Metadata: map[string]interface{}{"complexity": 7}},
+		// A node of a snapshot reloaded from disk
+		{ID: "n2", Name: "Store", Type: "function", Metadata: map[string]interface{}{"complexity": float64(4)}},
+	}
+
+	complexity := map[string]int{}
+	for _, symbol := range reportSymbols(nodes) {
+		complexity[symbol.Name] = symbol.Complexity
+	}
+	if complexity["Scan"] != 7 || complexity["Store"] != 4 {
+		t.Errorf("report complexities %v, want Scan 7 and Store 4", complexity)
+	}
+}
@@ -0,0 +1,86 @@
+package exporter
+
+import (
+	"sort"
+	"strings"
+)
+
+// RenderHTTPFile writes the endpoints of APIDocs as a .http file for REST clients
+// such as VS Code REST Client. Requests address the baseUrl file variable, set to
+// baseURL, and path parameters are file variables to fill in.
+func RenderHTTPFile(docs *APIDocs, baseURL string) []byte {
+	var b strings.Builder
+	b.WriteString("# " + docs.Title + " API\n")
+	b.WriteString("# Set the base URL and the path parameters below before sending requests.\n\n")
+	b.WriteString("@" + baseURLVariable + " = " + baseURL + "\n")
+
+	params := make(map[string]bool)
+	var collect func(groups []*DocGroup)
+	collect = func(groups []*DocGroup) {
+		for _, group := range groups {
+			for _, endpoint := range group.Endpoints {
+				for _, name := range endpoint.PathParams {
+					params[name] = true
+				}
+			}
+			collect(group.Groups)
+		}
+	}
+	collect(docs.Groups)
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString("@" + name + " =\n")
+	}
+	b.WriteString("\n")
+
+	var write func(groups []*DocGroup)
+	write = func(groups []*DocGroup) {
+		for _, group := range groups {
+			// A delimiter line ends the body of the previous request
+			b.WriteString("### Router group: " + groupName(group) + "\n\n")
+			for _, endpoint := range group.Endpoints {
+				writeHTTPRequest(&b, endpoint)
+			}
+			write(group.Groups)
+		}
+	}
+	write(docs.Groups)
+	return []byte(b.String())
+}
+
+func writeHTTPRequest(b *strings.Builder, endpoint *DocEndpoint) {
+	b.WriteString("### " + endpoint.Method + " " + endpoint.Path + "\n")
+	for _, line := range strings.Split(requestDescription(endpoint), "\n") {
+		b.WriteString(strings.TrimSpace("# "+line) + "\n")
+	}
+
+	var optional []string
+	for _, param := range endpoint.QueryParams {
+		if !param.Required {
+			optional = append(optional, param.Name)
+		}
+	}
+	if len(optional) > 0 {
+		b.WriteString("# Optional query parameters: " + strings.Join(optional, ", ") + "\n")
+	}
+
+	segments := requestPath(endpoint.Path, func(name string) string { return "{{" + name + "}}" })
+	b.WriteString(endpoint.Method + " {{" + baseURLVariable + "}}/" + strings.Join(segments, "/") + "\n")
+	separator := "?"
+	for _, param := range endpoint.QueryParams {
+		if param.Required {
+			b.WriteString("    " + separator + param.Name + "=\n")
+			separator = "&"
+		}
+	}
+
+	if endpoint.Request != nil {
+		b.WriteString("Content-Type: application/json\n\n")
+		b.WriteString(exampleBody(endpoint.Request) + "\n")
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,354 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// MarkdownIndexFile is the index page of a Markdown documentation tree
+const MarkdownIndexFile = "README.md"
+
+// APIDocs is what the Markdown API reference describes of a project
+type APIDocs struct {
+	Title  string
+	Groups []*DocGroup // Root router groups
+}
+
+// DocGroup is a router group with the endpoints registered on it
+type DocGroup struct {
+	Name      string // Variable the group is assigned to; empty for endpoints outside any group
+	Path      string // Full path prefix
+	File      string
+	Line      int
+	Groups    []*DocGroup
+	Endpoints []*DocEndpoint
+}
+
+// DocEndpoint is an endpoint with its handler documentation and body schemas
+type DocEndpoint struct {
+	*entity.APIEndpoint
+	Doc       string // Doc comment of the handler
+	Request   *DocSchema
+	Responses []*DocSchema
+}
+
+// DocSchema is a request or response type with its wire fields
+type DocSchema struct {
+	Type   string
+	Fields []*DocField
+}
+
+// DocField is a field of a schema; nested fields have dotted names
+type DocField struct {
+	Name     string
+	Type     string
+	Required bool
+}
+
+// markdownDocs renders APIDocs either as a tree of pages linked together or as a
+// single document where the pages become sections
+type markdownDocs struct {
+	docs   *APIDocs
+	single bool
+	pages  map[*DocGroup]string // Page file name of each group
+	groups []*DocGroup          // Groups in page order: depth first, parents before children
+}
+
+// RenderMarkdownDocs renders an index page and one page per router group, ready
+// to be committed into a docs folder. The index comes first.
+func RenderMarkdownDocs(docs *APIDocs) []*ArchiveFile {
+	m := newMarkdownDocs(docs, false)
+
+	var b strings.Builder
+	m.writeIndex(&b)
+	files := []*ArchiveFile{{Name: MarkdownIndexFile, Data: []byte(b.String())}}
+	for _, group := range m.groups {
+		b.Reset()
+		m.writeGroup(&b, group)
+		files = append(files, &ArchiveFile{Name: m.pages[group], Data: []byte(b.String())})
+	}
+	return files
+}
+
+// RenderMarkdownDocument renders the index and all router group pages as one document
+func RenderMarkdownDocument(docs *APIDocs) []byte {
+	m := newMarkdownDocs(docs, true)
+
+	var b strings.Builder
+	m.writeIndex(&b)
+	for _, group := range m.groups {
+		b.WriteString("\n---\n\n")
+		m.writeGroup(&b, group)
+	}
+	return []byte(b.String())
+}
+
+func newMarkdownDocs(docs *APIDocs, single bool) *markdownDocs {
+	m := &markdownDocs{docs: docs, single: single, pages: make(map[*DocGroup]string)}
+	used := map[string]bool{strings.TrimSuffix(MarkdownIndexFile, ".md"): true}
+
+	var visit func(group *DocGroup)
+	visit = func(group *DocGroup) {
+		name := slug(group.Path)
+		if name == "" {
+			name = slug(group.Name)
+		}
+		if name == "" {
+			name = "root"
+		}
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = name + "-" + strconv.Itoa(i)
+		}
+		used[unique] = true
+		m.pages[group] = unique + ".md"
+		m.groups = append(m.groups, group)
+
+		for _, child := range group.Groups {
+			visit(child)
+		}
+	}
+	for _, group := range docs.Groups {
+		visit(group)
+	}
+	return m
+}
+
+// heading writes a heading, one level deeper in a single document
+func (m *markdownDocs) heading(b *strings.Builder, level int, text string) {
+	if m.single {
+		level++
+	}
+	b.WriteString(strings.Repeat("#", level) + " " + text + "\n\n")
+}
+
+func (m *markdownDocs) groupAnchor(group *DocGroup) string {
+	return "group-" + strings.TrimSuffix(m.pages[group], ".md")
+}
+
+func (m *markdownDocs) groupLink(group *DocGroup) string {
+	if m.single {
+		return "#" + m.groupAnchor(group)
+	}
+	return m.pages[group]
+}
+
+func (m *markdownDocs) indexLink() string {
+	if m.single {
+		return "#api-reference"
+	}
+	return MarkdownIndexFile
+}
+
+func (m *markdownDocs) endpointLink(group *DocGroup, endpoint *DocEndpoint) string {
+	if m.single {
+		return "#" + endpointAnchor(endpoint)
+	}
+	return m.pages[group] + "#" + endpointAnchor(endpoint)
+}
+
+func (m *markdownDocs) writeIndex(b *strings.Builder) {
+	// The index stays at the top level of a single document
+	if m.single {
+		b.WriteString(`<a id="api-reference"></a>` + "\n\n")
+	}
+	b.WriteString("# " + m.docs.Title + " API reference\n\n")
+
+	endpoints := 0
+	for _, group := range m.groups {
+		endpoints += len(group.Endpoints)
+	}
+	fmt.Fprintf(b, "%d endpoints in %d router groups.\n\n", endpoints, len(m.groups))
+
+	b.WriteString("## Router groups\n\n")
+	var tree func(groups []*DocGroup, indent string)
+	tree = func(groups []*DocGroup, indent string) {
+		for _, group := range groups {
+			fmt.Fprintf(b, "%s- [%s](%s) — %s\n", indent, groupTitle(group), m.groupLink(group), plural(len(group.Endpoints), "endpoint"))
+			tree(group.Groups, indent+"  ")
+		}
+	}
+	tree(m.docs.Groups, "")
+	b.WriteString("\n")
+
+	b.WriteString("## Endpoints\n\n")
+	if endpoints == 0 {
+		b.WriteString("No endpoints were discovered.\n")
+		return
+	}
+	b.WriteString("| Method | Path | Handler | Group |\n|---|---|---|---|\n")
+	for _, group := range m.groups {
+		for _, endpoint := range group.Endpoints {
+			fmt.Fprintf(b, "| %s | [%s](%s) | %s | [%s](%s) |\n",
+				endpoint.Method, code(endpoint.Path), m.endpointLink(group, endpoint),
+				code(endpoint.Handler), groupTitle(group), m.groupLink(group))
+		}
+	}
+}
+
+func (m *markdownDocs) writeGroup(b *strings.Builder, group *DocGroup) {
+	if m.single {
+		fmt.Fprintf(b, "<a id=\"%s\"></a>\n\n", m.groupAnchor(group))
+	}
+	b.WriteString("[API reference](" + m.indexLink() + ")\n\n")
+	m.heading(b, 1, groupTitle(group))
+
+	if group.Name != "" {
+		fmt.Fprintf(b, "Routes registered on %s in %s.\n\n", code(group.Name), code(location(group.File, group.Line)))
+	} else {
+		b.WriteString("Endpoints not registered on a known router group.\n\n")
+	}
+
+	if len(group.Groups) > 0 {
+		m.heading(b, 2, "Subgroups")
+		b.WriteString("| Group | Endpoints |\n|---|---|\n")
+		for _, child := range group.Groups {
+			fmt.Fprintf(b, "| [%s](%s) | %d |\n", groupTitle(child), m.groupLink(child), len(child.Endpoints))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(group.Endpoints) == 0 {
+		return
+	}
+	m.heading(b, 2, "Endpoints")
+	b.WriteString("| Method | Path | Handler |\n|---|---|---|\n")
+	for _, endpoint := range group.Endpoints {
+		fmt.Fprintf(b, "| %s | [%s](#%s) | %s |\n", endpoint.Method, code(endpoint.Path), endpointAnchor(endpoint), code(endpoint.Handler))
+	}
+	b.WriteString("\n")
+
+	for _, endpoint := range group.Endpoints {
+		m.writeEndpoint(b, endpoint)
+	}
+}
+
+func (m *markdownDocs) writeEndpoint(b *strings.Builder, endpoint *DocEndpoint) {
+	fmt.Fprintf(b, "<a id=\"%s\"></a>\n\n", endpointAnchor(endpoint))
+	m.heading(b, 3, endpoint.Method+" "+code(endpoint.Path))
+	if endpoint.Doc != "" {
+		b.WriteString(strings.TrimSpace(endpoint.Doc) + "\n\n")
+	}
+
+	b.WriteString("| | |\n|---|---|\n")
+	handler := code(endpoint.Handler)
+	if endpoint.HandlerSymbol != "" {
+		handler += " (" + code(endpoint.HandlerSymbol) + ")"
+	}
+	fmt.Fprintf(b, "| Handler | %s |\n", handler)
+	if len(endpoint.Middlewares) > 0 {
+		middlewares := make([]string, len(endpoint.Middlewares))
+		for i, middleware := range endpoint.Middlewares {
+			middlewares[i] = code(middleware)
+		}
+		fmt.Fprintf(b, "| Middlewares | %s |\n", strings.Join(middlewares, " → "))
+	}
+	fmt.Fprintf(b, "| Defined in | %s |\n\n", code(endpoint.File))
+
+	if len(endpoint.PathParams) > 0 || len(endpoint.QueryParams) > 0 {
+		b.WriteString("**Parameters**\n\n| Name | In | Required |\n|---|---|---|\n")
+		for _, name := range endpoint.PathParams {
+			fmt.Fprintf(b, "| %s | path | yes |\n", code(name))
+		}
+		for _, param := range endpoint.QueryParams {
+			fmt.Fprintf(b, "| %s | query | %s |\n", code(param.Name), yesNo(param.Required))
+		}
+		b.WriteString("\n")
+	}
+
+	if endpoint.Request != nil {
+		b.WriteString("**Request body**: " + code(endpoint.Request.Type) + "\n\n")
+		writeSchemaFields(b, endpoint.Request, true)
+	}
+	for _, response := range endpoint.Responses {
+		b.WriteString("**Response**: " + code(response.Type) + "\n\n")
+		writeSchemaFields(b, response, false)
+	}
+}
+
+func writeSchemaFields(b *strings.Builder, schema *DocSchema, request bool) {
+	if len(schema.Fields) == 0 {
+		b.WriteString("_Fields unknown: the type is not a struct declared in the project._\n\n")
+		return
+	}
+	if request {
+		b.WriteString("| Field | Type | Required |\n|---|---|---|\n")
+	} else {
+		b.WriteString("| Field | Type |\n|---|---|\n")
+	}
+	for _, field := range schema.Fields {
+		if request {
+			fmt.Fprintf(b, "| %s | %s | %s |\n", code(field.Name), code(field.Type), yesNo(field.Required))
+		} else {
+			fmt.Fprintf(b, "| %s | %s |\n", code(field.Name), code(field.Type))
+		}
+	}
+	b.WriteString("\n")
+}
+
+func groupTitle(group *DocGroup) string {
+	if group.Name == "" {
+		return "Other endpoints"
+	}
+	if group.Path == "/" {
+		// Routes of an engine share the root path, so their variable tells them apart
+		return code(group.Name) + " routes"
+	}
+	return code(group.Path)
+}
+
+func endpointAnchor(endpoint *DocEndpoint) string {
+	return strings.ToLower(endpoint.Method) + "-" + slug(endpoint.Path)
+}
+
+// code formats text as inline code that is safe inside a table cell
+func code(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	text = strings.ReplaceAll(text, "|", `\|`)
+	if strings.Contains(text, "`") {
+		return "`` " + text + " ``"
+	}
+	return "`" + text + "`"
+}
+
+// slug turns a path or name into lower case words joined by dashes
+func slug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+func location(file string, line int) string {
+	if line > 0 {
+		return file + ":" + strconv.Itoa(line)
+	}
+	return file
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(count) + " " + noun + "s"
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
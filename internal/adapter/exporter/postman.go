@@ -0,0 +1,190 @@
+package exporter
+
+import (
+	"encoding/json"
+	"strings"
+
+	"goapianalyzer/pkg/errors"
+)
+
+// PostmanSchema identifies the Postman collection format written by RenderPostmanCollection
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// baseURLVariable is the variable holding the base URL in collections and .http files
+const baseURLVariable = "baseUrl"
+
+type postmanCollection struct {
+	Info     postmanInfo        `json:"info"`
+	Item     []*postmanItem     `json:"item"`
+	Variable []*postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// postmanItem is a folder when it has items, otherwise a request
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []*postmanItem  `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string           `json:"method"`
+	Header []*postmanHeader `json:"header"`
+	URL    *postmanURL      `json:"url"`
+	Body   *postmanBody     `json:"body,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanURL struct {
+	Raw      string             `json:"raw"`
+	Host     []string           `json:"host"`
+	Path     []string           `json:"path"`
+	Query    []*postmanQuery    `json:"query,omitempty"`
+	Variable []*postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanQuery struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string              `json:"mode"`
+	Raw     string              `json:"raw"`
+	Options *postmanBodyOptions `json:"options,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// RenderPostmanCollection writes the endpoints of APIDocs as a Postman v2.1
+// collection with a folder per router group. Requests address the baseUrl
+// collection variable, set to baseURL.
+func RenderPostmanCollection(docs *APIDocs, baseURL string) ([]byte, error) {
+	collection := &postmanCollection{
+		Info: postmanInfo{Name: docs.Title + " API", Schema: PostmanSchema},
+		Item: make([]*postmanItem, 0, len(docs.Groups)),
+		Variable: []*postmanVariable{
+			{Key: baseURLVariable, Value: baseURL, Type: "string"},
+		},
+	}
+	for _, group := range docs.Groups {
+		collection.Item = append(collection.Item, postmanFolder(group))
+	}
+
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return nil, errors.NewSystemError("failed to marshal Postman collection: " + err.Error())
+	}
+	return data, nil
+}
+
+func postmanFolder(group *DocGroup) *postmanItem {
+	folder := &postmanItem{Name: groupName(group), Item: make([]*postmanItem, 0)}
+	if group.Name != "" {
+		folder.Description = "Routes registered on " + group.Name + " in " + location(group.File, group.Line)
+	}
+	for _, child := range group.Groups {
+		folder.Item = append(folder.Item, postmanFolder(child))
+	}
+	for _, endpoint := range group.Endpoints {
+		folder.Item = append(folder.Item, postmanRequestItem(endpoint))
+	}
+	return folder
+}
+
+func postmanRequestItem(endpoint *DocEndpoint) *postmanItem {
+	segments := requestPath(endpoint.Path, func(name string) string { return ":" + name })
+	url := &postmanURL{
+		Raw:  "{{" + baseURLVariable + "}}/" + strings.Join(segments, "/"),
+		Host: []string{"{{" + baseURLVariable + "}}"},
+		Path: segments,
+	}
+	for _, name := range endpoint.PathParams {
+		url.Variable = append(url.Variable, &postmanVariable{Key: name, Value: ""})
+	}
+
+	// Optional query parameters are listed but left out of the request until enabled
+	var query []string
+	for _, param := range endpoint.QueryParams {
+		url.Query = append(url.Query, &postmanQuery{Key: param.Name, Value: "", Disabled: !param.Required})
+		if param.Required {
+			query = append(query, param.Name+"=")
+		}
+	}
+	if len(query) > 0 {
+		url.Raw += "?" + strings.Join(query, "&")
+	}
+
+	request := &postmanRequest{
+		Method: endpoint.Method,
+		Header: make([]*postmanHeader, 0, 1),
+		URL:    url,
+	}
+	if endpoint.Request != nil {
+		request.Header = append(request.Header, &postmanHeader{Key: "Content-Type", Value: "application/json"})
+		request.Body = &postmanBody{Mode: "raw", Raw: exampleBody(endpoint.Request), Options: &postmanBodyOptions{}}
+		request.Body.Options.Raw.Language = "json"
+	}
+
+	return &postmanItem{
+		Name:        endpoint.Method + " " + endpoint.Path,
+		Description: requestDescription(endpoint),
+		Request:     request,
+	}
+}
+
+// requestPath splits an endpoint path into segments, writing path parameters of
+// gin (:id, *path) and mux ({id}, {path...}) styles with param
+func requestPath(endpointPath string, param func(name string) string) []string {
+	segments := strings.Split(strings.Trim(endpointPath, "/"), "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*"):
+			segments[i] = param(segment[1:])
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			segments[i] = param(strings.TrimSuffix(strings.Trim(segment, "{}"), "..."))
+		}
+	}
+	return segments
+}
+
+// requestDescription is the handler doc comment followed by the handler name
+func requestDescription(endpoint *DocEndpoint) string {
+	description := "Handler: " + endpoint.Handler
+	if doc := strings.TrimSpace(endpoint.Doc); doc != "" {
+		description = doc + "\n\n" + description
+	}
+	return description
+}
+
+// groupName names a router group in collections
+func groupName(group *DocGroup) string {
+	if group.Name == "" {
+		return "Other endpoints"
+	}
+	if group.Path == "/" {
+		return group.Name + " routes"
+	}
+	return group.Path
+}
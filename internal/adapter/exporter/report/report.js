@@ -0,0 +1,258 @@
+(function () {
+  "use strict";
+
+  var data = JSON.parse(document.getElementById("report-data").textContent);
+  var SVG = "http://www.w3.org/2000/svg";
+
+  // Sortable tables: click a header to sort by its column
+  document.querySelectorAll("table.sortable").forEach(function (table) {
+    table.querySelectorAll("th").forEach(function (th, column) {
+      th.addEventListener("click", function () {
+        var order = th.getAttribute("data-order") === "asc" ? "desc" : "asc";
+        table.querySelectorAll("th").forEach(function (other) { other.removeAttribute("data-order"); });
+        th.setAttribute("data-order", order);
+        var numeric = th.classList.contains("num");
+        var body = table.tBodies[0];
+        var rows = Array.prototype.slice.call(body.rows);
+        rows.sort(function (a, b) {
+          var x = a.cells[column].textContent.trim();
+          var y = b.cells[column].textContent.trim();
+          var result = numeric ? parseFloat(x) - parseFloat(y) : x.localeCompare(y);
+          return order === "asc" ? result : -result;
+        });
+        rows.forEach(function (row) { body.appendChild(row); });
+      });
+    });
+  });
+
+  // Symbol list with a text and type filter; only the first matches are drawn
+  var MAX_SYMBOL_ROWS = 500;
+  var search = document.getElementById("symbol-search");
+  var typeSelect = document.getElementById("symbol-type");
+  var rows = document.getElementById("symbol-rows");
+  var count = document.getElementById("symbol-count");
+
+  var types = {};
+  data.symbols.forEach(function (symbol) { types[symbol.type] = true; });
+  Object.keys(types).sort().forEach(function (type) {
+    var option = document.createElement("option");
+    option.value = type;
+    option.textContent = type;
+    typeSelect.appendChild(option);
+  });
+
+  function cell(row, text, className) {
+    var td = row.insertCell();
+    td.textContent = text;
+    if (className) {
+      td.className = className;
+    }
+    return td;
+  }
+
+  function renderSymbols() {
+    var terms = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var type = typeSelect.value;
+    var matches = data.symbols.filter(function (symbol) {
+      if (type && symbol.type !== type) {
+        return false;
+      }
+      var text = (symbol.name + " " + symbol.package + " " + symbol.file).toLowerCase();
+      return terms.every(function (term) { return text.indexOf(term) >= 0; });
+    });
+
+    rows.textContent = "";
+    matches.slice(0, MAX_SYMBOL_ROWS).forEach(function (symbol) {
+      var row = rows.insertRow();
+      var name = cell(row, "");
+      var code = document.createElement("code");
+      code.textContent = symbol.name;
+      name.appendChild(code);
+      cell(row, symbol.type + (symbol.complexity ? " · complexity " + symbol.complexity : ""));
+      cell(row, symbol.package);
+      cell(row, symbol.file + (symbol.line ? ":" + symbol.line : ""), "file");
+    });
+    count.textContent = matches.length > MAX_SYMBOL_ROWS
+      ? "showing " + MAX_SYMBOL_ROWS + " of " + matches.length
+      : matches.length + " symbols";
+  }
+
+  search.addEventListener("input", renderSymbols);
+  typeSelect.addEventListener("change", renderSymbols);
+  renderSymbols();
+
+  // Dependency graph: a small force-directed layout drawn in SVG
+  var graph = data.graph;
+  var svg = document.getElementById("graph-canvas");
+  if (!graph.nodes.length) {
+    document.getElementById("graph-note").textContent = "The project has no dependency graph.";
+    svg.style.display = "none";
+    return;
+  }
+  if (graph.collapsed) {
+    document.getElementById("graph-note").textContent +=
+      " The least connected nodes are grouped into “N more” nodes.";
+  }
+
+  var width = svg.clientWidth || 960;
+  var height = svg.clientHeight || 640;
+  var nodes = graph.nodes.map(function (node, i) {
+    var angle = (2 * Math.PI * i) / graph.nodes.length;
+    return {
+      data: node,
+      x: width / 2 + (width / 3) * Math.cos(angle),
+      y: height / 2 + (height / 3) * Math.sin(angle),
+      vx: 0,
+      vy: 0,
+      degree: 0
+    };
+  });
+  var edges = graph.edges.map(function (edge) {
+    nodes[edge[0]].degree++;
+    nodes[edge[1]].degree++;
+    return { source: nodes[edge[0]], target: nodes[edge[1]] };
+  });
+
+  function layout(iterations) {
+    var k = Math.sqrt((width * height) / nodes.length) * 0.6;
+    for (var step = 0; step < iterations; step++) {
+      var cooling = 1 - step / iterations;
+      for (var i = 0; i < nodes.length; i++) {
+        for (var j = i + 1; j < nodes.length; j++) {
+          var a = nodes[i], b = nodes[j];
+          var dx = a.x - b.x, dy = a.y - b.y;
+          var distance = Math.max(Math.sqrt(dx * dx + dy * dy), 1);
+          var force = (k * k) / distance / distance;
+          a.vx += dx * force; a.vy += dy * force;
+          b.vx -= dx * force; b.vy -= dy * force;
+        }
+      }
+      edges.forEach(function (edge) {
+        var dx = edge.target.x - edge.source.x, dy = edge.target.y - edge.source.y;
+        var distance = Math.max(Math.sqrt(dx * dx + dy * dy), 1);
+        var force = distance / k / 10;
+        edge.source.vx += dx * force; edge.source.vy += dy * force;
+        edge.target.vx -= dx * force; edge.target.vy -= dy * force;
+      });
+      nodes.forEach(function (node) {
+        node.vx += (width / 2 - node.x) * 0.01;
+        node.vy += (height / 2 - node.y) * 0.01;
+        var speed = Math.sqrt(node.vx * node.vx + node.vy * node.vy);
+        var limit = 20 * cooling + 1;
+        if (speed > limit) {
+          node.vx *= limit / speed;
+          node.vy *= limit / speed;
+        }
+        node.x += node.vx;
+        node.y += node.vy;
+        node.vx *= 0.5;
+        node.vy *= 0.5;
+      });
+    }
+  }
+  layout(300);
+
+  var viewport = document.createElementNS(SVG, "g");
+  svg.appendChild(viewport);
+  edges.forEach(function (edge) {
+    edge.element = document.createElementNS(SVG, "line");
+    edge.element.setAttribute("class", "edge");
+    viewport.appendChild(edge.element);
+  });
+  nodes.forEach(function (node) {
+    var group = document.createElementNS(SVG, "g");
+    group.setAttribute("class", "node");
+    var circle = document.createElementNS(SVG, "circle");
+    circle.setAttribute("r", Math.min(4 + Math.sqrt(node.degree) * 2, 18));
+    circle.setAttribute("fill", node.data.color);
+    var title = document.createElementNS(SVG, "title");
+    title.textContent = node.data.title + " (" + node.data.type + (node.data.package ? ", " + node.data.package : "") + ")";
+    var label = document.createElementNS(SVG, "text");
+    label.setAttribute("dx", 10);
+    label.setAttribute("dy", 3);
+    label.textContent = node.data.label;
+    circle.appendChild(title);
+    group.appendChild(circle);
+    group.appendChild(label);
+    viewport.appendChild(group);
+    node.element = group;
+  });
+
+  function draw() {
+    edges.forEach(function (edge) {
+      edge.element.setAttribute("x1", edge.source.x);
+      edge.element.setAttribute("y1", edge.source.y);
+      edge.element.setAttribute("x2", edge.target.x);
+      edge.element.setAttribute("y2", edge.target.y);
+    });
+    nodes.forEach(function (node) {
+      node.element.setAttribute("transform", "translate(" + node.x + "," + node.y + ")");
+    });
+  }
+  draw();
+
+  // Pan, zoom, drag and highlight
+  var view = { x: 0, y: 0, scale: 1 };
+  function applyView() {
+    viewport.setAttribute("transform", "translate(" + view.x + "," + view.y + ") scale(" + view.scale + ")");
+  }
+  function point(event) {
+    var box = svg.getBoundingClientRect();
+    return { x: (event.clientX - box.left - view.x) / view.scale, y: (event.clientY - box.top - view.y) / view.scale };
+  }
+
+  var dragged = null, panning = null;
+  nodes.forEach(function (node) {
+    node.element.addEventListener("mousedown", function (event) {
+      dragged = node;
+      event.stopPropagation();
+    });
+    node.element.addEventListener("click", function () {
+      nodes.forEach(function (other) { other.element.classList.toggle("active", other === node); });
+      edges.forEach(function (edge) {
+        edge.element.classList.toggle("active", edge.source === node || edge.target === node);
+      });
+    });
+  });
+  svg.addEventListener("mousedown", function (event) {
+    panning = { x: event.clientX - view.x, y: event.clientY - view.y };
+  });
+  window.addEventListener("mousemove", function (event) {
+    if (dragged) {
+      var p = point(event);
+      dragged.x = p.x;
+      dragged.y = p.y;
+      draw();
+    } else if (panning) {
+      view.x = event.clientX - panning.x;
+      view.y = event.clientY - panning.y;
+      applyView();
+    }
+  });
+  window.addEventListener("mouseup", function () {
+    dragged = null;
+    panning = null;
+  });
+  svg.addEventListener("wheel", function (event) {
+    event.preventDefault();
+    var box = svg.getBoundingClientRect();
+    var mx = event.clientX - box.left, my = event.clientY - box.top;
+    var factor = event.deltaY < 0 ? 1.1 : 1 / 1.1;
+    view.x = mx - (mx - view.x) * factor;
+    view.y = my - (my - view.y) * factor;
+    view.scale *= factor;
+    applyView();
+  }, { passive: false });
+
+  var legend = document.getElementById("graph-legend");
+  var colors = {};
+  graph.nodes.forEach(function (node) { colors[node.type] = node.color; });
+  Object.keys(colors).sort().forEach(function (type) {
+    var item = document.createElement("li");
+    var swatch = document.createElement("span");
+    swatch.style.background = colors[type];
+    item.appendChild(swatch);
+    item.appendChild(document.createTextNode(type));
+    legend.appendChild(item);
+  });
+})();
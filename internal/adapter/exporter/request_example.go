@@ -0,0 +1,124 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// exampleObject is a JSON object keeping its keys in declaration order
+type exampleObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *exampleObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// exampleField is a schema field with the fields nested in it
+type exampleField struct {
+	field    *DocField
+	children []*exampleField
+}
+
+// exampleBody builds an indented example JSON body of a schema, with a
+// placeholder value for each field. Nested fields become nested objects.
+func exampleBody(schema *DocSchema) string {
+	var roots []*exampleField
+	byName := make(map[string]*exampleField, len(schema.Fields))
+	for _, field := range schema.Fields {
+		node := &exampleField{field: field}
+		byName[field.Name] = node
+
+		parent := ""
+		if i := strings.LastIndex(field.Name, "."); i >= 0 {
+			parent = field.Name[:i]
+		}
+		if parentNode, exists := byName[parent]; exists {
+			parentNode.children = append(parentNode.children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	data, err := json.MarshalIndent(exampleFields(roots), "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+func exampleFields(fields []*exampleField) *exampleObject {
+	object := &exampleObject{values: make(map[string]interface{}, len(fields))}
+	for _, node := range fields {
+		key := node.field.Name[strings.LastIndex(node.field.Name, ".")+1:]
+		object.keys = append(object.keys, key)
+		if len(node.children) > 0 {
+			object.values[key] = wrapExample(node.field.Type, exampleFields(node.children))
+		} else {
+			object.values[key] = exampleValue(node.field.Type)
+		}
+	}
+	return object
+}
+
+// wrapExample puts an example object into the slices and maps of a Go type
+func wrapExample(goType string, object interface{}) interface{} {
+	goType = strings.TrimLeft(goType, "*")
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return []interface{}{wrapExample(goType[2:], object)}
+	case strings.HasPrefix(goType, "map["):
+		return map[string]interface{}{"key": wrapExample(goType[strings.Index(goType, "]")+1:], object)}
+	default:
+		return object
+	}
+}
+
+// exampleValue is a placeholder JSON value of a Go type
+func exampleValue(goType string) interface{} {
+	goType = strings.TrimLeft(goType, "*")
+	switch goType {
+	case "string":
+		return "string"
+	case "bool":
+		return false
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "time.Duration":
+		return 0
+	case "float32", "float64":
+		return 0.0
+	case "time.Time":
+		return "2006-01-02T15:04:05Z"
+	case "[]byte":
+		return ""
+	case "interface{}", "any":
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return []interface{}{exampleValue(goType[2:])}
+	case strings.HasPrefix(goType, "map["):
+		return map[string]interface{}{}
+	default:
+		// Structs declared outside the project and other named types
+		return map[string]interface{}{}
+	}
+}
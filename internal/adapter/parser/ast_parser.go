@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"strings"
 
 	"goapianalyzer/internal/core/domain/entity"
 	"goapianalyzer/pkg/errors"
@@ -44,6 +45,7 @@ func (p *ASTParser) parseFunctionDecl(funcDecl *ast.FuncDecl, fileInfo *entity.F
 		Name:       funcDecl.Name.Name,
 		Parameters: make([]*entity.Parameter, 0),
 		Returns:    make([]*entity.Return, 0),
+		Doc:        strings.TrimSpace(funcDecl.Doc.Text()),
 		Body:       p.getNodeText(funcDecl, fileInfo.Content),
 		CallsTo:    make([]*entity.FunctionCall, 0),
 		UsedTypes:  make([]string, 0),
@@ -102,10 +104,42 @@ func (p *ASTParser) parseFunctionDecl(funcDecl *ast.FuncDecl, fileInfo *entity.F
 	if funcDecl.Body != nil {
 		p.parseBlockStmt(funcDecl.Body, funcInfo, fileInfo)
 	}
+	funcInfo.Complexity = p.cyclomaticComplexity(funcDecl.Body)
 
 	return funcInfo
 }
 
+// cyclomaticComplexity counts the independent paths through a function body:
+// one plus every branch point and short-circuit operator
+func (p *ASTParser) cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	if body == nil {
+		return complexity
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+
+	return complexity
+}
+
 func (p *ASTParser) parseGenDecl(genDecl *ast.GenDecl, fileInfo *entity.FileInfo) {
 	switch genDecl.Tok {
 	case token.TYPE:
@@ -121,28 +155,34 @@ func (p *ASTParser) parseTypeDecl(genDecl *ast.GenDecl, fileInfo *entity.FileInf
 	for _, spec := range genDecl.Specs {
 		if typeSpec, ok := spec.(*ast.TypeSpec); ok {
 			typeName := typeSpec.Name.Name
+			doc := p.typeDoc(genDecl, typeSpec)
 
 			switch t := typeSpec.Type.(type) {
 			case *ast.StructType:
 				structInfo := p.parseStructType(typeName, t, fileInfo)
+				structInfo.Doc = doc
 				fileInfo.Structs = append(fileInfo.Structs, structInfo)
 				fileInfo.Types = append(fileInfo.Types, &entity.TypeInfo{
 					Name: typeName,
 					Type: "struct",
+					Doc:  doc,
 					Body: p.getNodeText(typeSpec, fileInfo.Content),
 				})
 			case *ast.InterfaceType:
 				interfaceInfo := p.parseInterfaceType(typeName, t, fileInfo)
+				interfaceInfo.Doc = doc
 				fileInfo.Interfaces = append(fileInfo.Interfaces, interfaceInfo)
 				fileInfo.Types = append(fileInfo.Types, &entity.TypeInfo{
 					Name: typeName,
 					Type: "interface",
+					Doc:  doc,
 					Body: p.getNodeText(typeSpec, fileInfo.Content),
 				})
 			default:
 				fileInfo.Types = append(fileInfo.Types, &entity.TypeInfo{
 					Name: typeName,
 					Type: p.exprToString(t),
+					Doc:  doc,
 					Body: p.getNodeText(typeSpec, fileInfo.Content),
 				})
 			}
@@ -150,6 +190,18 @@ func (p *ASTParser) parseTypeDecl(genDecl *ast.GenDecl, fileInfo *entity.FileInf
 	}
 }
 
+// typeDoc returns the doc comment of a type spec, falling back to the comment of
+// an ungrouped type declaration
+func (p *ASTParser) typeDoc(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) string {
+	if typeSpec.Doc != nil {
+		return strings.TrimSpace(typeSpec.Doc.Text())
+	}
+	if !genDecl.Lparen.IsValid() {
+		return strings.TrimSpace(genDecl.Doc.Text())
+	}
+	return ""
+}
+
 func (p *ASTParser) parseStructType(name string, structType *ast.StructType, fileInfo *entity.FileInfo) *entity.StructInfo {
 	structInfo := &entity.StructInfo{
 		Name:   name,
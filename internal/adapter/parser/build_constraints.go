@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"go/build"
+	"go/build/constraint"
+	"runtime"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// Known operating systems and architectures, mirroring go/build's syslist.go.
+// They are used to recognise implicit constraints in file names such as
+// foo_linux.go or foo_windows_amd64.go.
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+}
+
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "linux": true, "netbsd": true,
+	"openbsd": true, "solaris": true,
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+	"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+}
+
+// BuildMatcher evaluates build constraints of Go files against a build context
+type BuildMatcher struct {
+	context     *entity.BuildContext
+	tags        map[string]bool
+	releaseTags map[string]bool
+}
+
+// NewBuildMatcher creates a matcher for the given build context. A nil context
+// or empty GOOS/GOARCH falls back to the host platform, like the go tool does.
+func NewBuildMatcher(ctx *entity.BuildContext) *BuildMatcher {
+	resolved := &entity.BuildContext{}
+	if ctx != nil {
+		*resolved = *ctx
+	}
+	if resolved.GOOS == "" {
+		resolved.GOOS = runtime.GOOS
+	}
+	if resolved.GOARCH == "" {
+		resolved.GOARCH = runtime.GOARCH
+	}
+
+	m := &BuildMatcher{
+		context:     resolved,
+		tags:        make(map[string]bool),
+		releaseTags: make(map[string]bool),
+	}
+	for _, tag := range resolved.Tags {
+		m.tags[strings.TrimSpace(tag)] = true
+	}
+	for _, tag := range build.Default.ReleaseTags {
+		m.releaseTags[tag] = true
+	}
+
+	return m
+}
+
+// Context returns the resolved build context used for evaluation
+func (m *BuildMatcher) Context() *entity.BuildContext {
+	return m.context
+}
+
+// Match returns the combined constraint expression of a file (from its
+// //go:build or // +build lines and its name) and whether the file is part
+// of the build. Files are always reported as matching when the context asks
+// for all constraints to be analyzed.
+func (m *BuildMatcher) Match(fileName string, content []byte) (string, bool) {
+	expr := combineConstraints(parseHeaderConstraint(content), fileNameConstraint(fileName))
+	if expr == nil {
+		return "", true
+	}
+
+	if m.context.AllConstraints {
+		return expr.String(), true
+	}
+
+	return expr.String(), expr.Eval(m.hasTag)
+}
+
+func (m *BuildMatcher) hasTag(tag string) bool {
+	switch {
+	case tag == m.context.GOOS || tag == m.context.GOARCH:
+		return true
+	case tag == "unix":
+		return unixOS[m.context.GOOS]
+	case tag == "linux":
+		return m.context.GOOS == "android"
+	case tag == "solaris":
+		return m.context.GOOS == "illumos"
+	case tag == "darwin":
+		return m.context.GOOS == "ios"
+	case tag == "gc":
+		return true
+	case tag == "cgo":
+		return m.context.CgoEnabled
+	}
+
+	return m.tags[tag] || m.releaseTags[tag]
+}
+
+// parseHeaderConstraint reads the build constraint lines that precede the
+// package clause. A //go:build line takes precedence over // +build lines.
+func parseHeaderConstraint(content []byte) constraint.Expr {
+	var goBuild constraint.Expr
+	var plusBuild constraint.Expr
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	inBlockComment := false
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inBlockComment {
+			if strings.Contains(line, "*/") {
+				inBlockComment = false
+			}
+			continue
+		}
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/*"):
+			inBlockComment = !strings.Contains(line, "*/")
+			continue
+		case !strings.HasPrefix(line, "//"):
+			// Reached the package clause or other code
+			return combineConstraints(goBuild, plusBuild)
+		}
+
+		if constraint.IsGoBuild(line) && goBuild == nil {
+			if expr, err := constraint.Parse(line); err == nil {
+				goBuild = expr
+			}
+		} else if constraint.IsPlusBuild(line) {
+			if expr, err := constraint.Parse(line); err == nil {
+				plusBuild = combineConstraints(plusBuild, expr)
+			}
+		}
+	}
+
+	return combineConstraints(goBuild, plusBuild)
+}
+
+// fileNameConstraint derives the implicit constraint of names like
+// name_GOOS.go, name_GOARCH.go and name_GOOS_GOARCH.go
+func fileNameConstraint(fileName string) constraint.Expr {
+	name := strings.TrimSuffix(fileName, ".go")
+	name = strings.TrimSuffix(name, "_test")
+
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+
+	parts := strings.Split(name[i:], "_")
+	n := len(parts)
+
+	if n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]] {
+		return &constraint.AndExpr{
+			X: &constraint.TagExpr{Tag: parts[n-2]},
+			Y: &constraint.TagExpr{Tag: parts[n-1]},
+		}
+	}
+	if n >= 1 && (knownOS[parts[n-1]] || knownArch[parts[n-1]]) {
+		return &constraint.TagExpr{Tag: parts[n-1]}
+	}
+
+	return nil
+}
+
+func combineConstraints(x, y constraint.Expr) constraint.Expr {
+	switch {
+	case x == nil:
+		return y
+	case y == nil:
+		return x
+	default:
+		return &constraint.AndExpr{X: x, Y: y}
+	}
+}
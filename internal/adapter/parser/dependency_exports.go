@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// goListTimeout bounds compiling the dependencies of one module
+const goListTimeout = 5 * time.Minute
+
+// listedPackage is the part of the `go list -json` output locating export data
+type listedPackage struct {
+	ImportPath string
+	Export     string // Empty for packages that failed to build
+}
+
+// dependencyExports compiles the dependencies of a project with `go list -export`
+// and returns the export data file of every package by import path. Modules are
+// listed from their own directory and workspaces from the project root, so that
+// go.mod, go.sum, replacements and the module cache apply as in a build.
+func dependencyExports(analysis *entity.ProjectAnalysis) (map[string]string, []error) {
+	dirs := map[string]bool{}
+	for _, module := range analysis.Modules {
+		if module.Workspace {
+			dirs[analysis.ProjectPath] = true
+		} else {
+			dirs[filepath.Join(analysis.ProjectPath, module.Dir)] = true
+		}
+	}
+	if len(dirs) == 0 {
+		dirs[analysis.ProjectPath] = true
+	}
+
+	ordered := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		ordered = append(ordered, dir)
+	}
+	sort.Strings(ordered)
+
+	exports := make(map[string]string)
+	var errs []error
+	for _, dir := range ordered {
+		if err := listExports(dir, analysis.Config, exports); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return exports, errs
+}
+
+// listExports adds the export data of the packages of the module in dir and their dependencies
+func listExports(dir string, config *entity.AnalysisConfig, exports map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), goListTimeout)
+	defer cancel()
+
+	args := []string{"list", "-e", "-export", "-deps", "-json=ImportPath,Export"}
+	env := os.Environ()
+	if config != nil && config.BuildContext != nil && !config.BuildContext.AllConstraints {
+		buildContext := config.BuildContext
+		if len(buildContext.Tags) > 0 {
+			args = append(args, "-tags="+strings.Join(buildContext.Tags, ","))
+		}
+		if buildContext.GOOS != "" {
+			env = append(env, "GOOS="+buildContext.GOOS)
+		}
+		if buildContext.GOARCH != "" {
+			env = append(env, "GOARCH="+buildContext.GOARCH)
+		}
+		cgo := "0"
+		if buildContext.CgoEnabled {
+			cgo = "1"
+		}
+		env = append(env, "CGO_ENABLED="+cgo)
+	}
+	args = append(args, "./...")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("loading dependencies in %s: %v: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+
+	decoder := json.NewDecoder(&stdout)
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("loading dependencies in %s: %v", dir, err)
+		}
+		if _, exists := exports[pkg.ImportPath]; !exists && pkg.Export != "" {
+			exports[pkg.ImportPath] = pkg.Export
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"goapianalyzer/internal/core/domain/entity"
@@ -14,22 +21,30 @@ import (
 )
 
 type FileScanner struct {
-	fileSet         *token.FileSet
-	blacklistFiles  []string
-	blacklistDirs   []string
-	whitelistFiles  []string
-	whitelistDirs   []string
-	includeVendor   bool
-	includeTestFile bool
+	fileSet          *token.FileSet
+	blacklistFiles   []string
+	blacklistDirs    []string
+	whitelistFiles   []string
+	whitelistDirs    []string
+	includeVendor    bool
+	includeTestFile  bool
+	excludeGenerated bool
+	buildMatcher     *BuildMatcher
+	modules          *ModuleResolver
+	observer         ScanObserver
 }
 
 type ScanConfig struct {
-	BlacklistFiles  []string
-	BlacklistDirs   []string
-	WhitelistFiles  []string
-	WhitelistDirs   []string
-	IncludeVendor   bool
-	IncludeTestFile bool
+	BlacklistFiles   []string
+	BlacklistDirs    []string
+	WhitelistFiles   []string
+	WhitelistDirs    []string
+	IncludeVendor    bool
+	IncludeTestFile  bool
+	ExcludeGenerated bool
+	BuildContext     *entity.BuildContext
+	FileSet          *token.FileSet // Reused across rescans so earlier positions stay valid
+	Observer         ScanObserver   // Receives progress notifications, optional
 }
 
 func NewFileScanner(config *ScanConfig) *FileScanner {
@@ -37,27 +52,201 @@ func NewFileScanner(config *ScanConfig) *FileScanner {
 		config = &ScanConfig{}
 	}
 
+	fileSet := config.FileSet
+	if fileSet == nil {
+		fileSet = token.NewFileSet()
+	}
+
+	var observer ScanObserver = nopObserver{}
+	if config.Observer != nil {
+		observer = config.Observer
+	}
+
 	return &FileScanner{
-		fileSet:         token.NewFileSet(),
-		blacklistFiles:  config.BlacklistFiles,
-		blacklistDirs:   config.BlacklistDirs,
-		whitelistFiles:  config.WhitelistFiles,
-		whitelistDirs:   config.WhitelistDirs,
-		includeVendor:   config.IncludeVendor,
-		includeTestFile: config.IncludeTestFile,
+		fileSet:          fileSet,
+		blacklistFiles:   config.BlacklistFiles,
+		blacklistDirs:    config.BlacklistDirs,
+		whitelistFiles:   config.WhitelistFiles,
+		whitelistDirs:    config.WhitelistDirs,
+		includeVendor:    config.IncludeVendor,
+		includeTestFile:  config.IncludeTestFile,
+		excludeGenerated: config.ExcludeGenerated,
+		buildMatcher:     NewBuildMatcher(config.BuildContext),
+		observer:         observer,
 	}
 }
 
 func (fs *FileScanner) ScanProject(projectPath string) (*entity.ProjectAnalysis, error) {
+	return fs.ScanProjectContext(context.Background(), projectPath)
+}
+
+// ScanProjectContext scans a project and stops early when ctx is cancelled
+func (fs *FileScanner) ScanProjectContext(ctx context.Context, projectPath string) (*entity.ProjectAnalysis, error) {
 	if !utils.IsValidPath(projectPath) {
 		return nil, errors.NewValidationError(fmt.Sprintf("invalid project path: %s", projectPath))
 	}
 
+	modules, err := LoadModules(projectPath, fs.blacklistedDir, fs.observer.Diagnostic)
+	if err != nil {
+		return nil, err
+	}
+	fs.modules = modules
+
 	projectAnalysis := &entity.ProjectAnalysis{
-		ProjectPath: projectPath,
-		Files:       make(map[string]*entity.FileInfo),
-		Packages:    make(map[string]*entity.PackageInfo),
+		ProjectPath:        projectPath,
+		Files:              make(map[string]*entity.FileInfo),
+		Packages:           make(map[string]*entity.PackageInfo),
+		BuildContext:       fs.buildMatcher.Context(),
+		Modules:            modules.Modules(),
+		Workspace:          modules.Workspace(),
+		ModuleDependencies: modules.Dependencies(),
+	}
+
+	fs.observer.PhaseChanged(entity.ScanPhaseWalking)
+	filePaths, err := fs.collectGoFiles(projectPath)
+	if err != nil {
+		return nil, errors.NewSystemError(fmt.Sprintf("failed to scan project: %v", err))
+	}
+	fs.observer.FilesFound(len(filePaths))
+
+	fs.observer.PhaseChanged(entity.ScanPhaseParsing)
+	for _, filePath := range filePaths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		content, err := utils.ReadFile(filePath)
+		if err != nil {
+			fs.observer.FileScanned(filePath, err)
+			return nil, errors.NewSystemError(fmt.Sprintf("failed to scan project: failed to read file %s: %v", filePath, err))
+		}
+
+		fileInfo, err := fs.processGoFile(filePath, content, projectPath)
+		fs.observer.FileScanned(filePath, err)
+		if err != nil {
+			return nil, errors.NewSystemError(fmt.Sprintf("failed to scan project: %v", err))
+		}
+		if fileInfo != nil {
+			projectAnalysis.Files[fileInfo.Path] = fileInfo
+		}
+	}
+
+	fs.buildPackages(projectAnalysis)
+
+	return projectAnalysis, nil
+}
+
+// RescanResult lists the files that changed since the previous scan
+type RescanResult struct {
+	Added     []string
+	Modified  []string
+	Removed   []string
+	Unchanged int
+}
+
+// RescanProject walks the project again and reparses only the files whose
+// size, modification time or content hash changed. The analysis Files and
+// Packages maps are updated in place; unchanged files keep their parsed details.
+func (fs *FileScanner) RescanProject(projectAnalysis *entity.ProjectAnalysis) (*RescanResult, error) {
+	projectPath := projectAnalysis.ProjectPath
+	if !utils.IsValidPath(projectPath) {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid project path: %s", projectPath))
+	}
+
+	modules, err := LoadModules(projectPath, fs.blacklistedDir, fs.observer.Diagnostic)
+	if err != nil {
+		return nil, err
+	}
+	fs.modules = modules
+
+	// Import paths and import kinds of every file depend on the module files.
+	// Compare serialized forms so that analyses restored from storage compare equal.
+	modulesChanged := !sameJSON(modules.Modules(), projectAnalysis.Modules) ||
+		!sameJSON(modules.Workspace(), projectAnalysis.Workspace)
+	projectAnalysis.Modules = modules.Modules()
+	projectAnalysis.Workspace = modules.Workspace()
+	projectAnalysis.ModuleDependencies = modules.Dependencies()
+
+	filePaths, err := fs.collectGoFiles(projectPath)
+	if err != nil {
+		return nil, errors.NewSystemError(fmt.Sprintf("failed to rescan project: %v", err))
+	}
+
+	result := &RescanResult{
+		Added:    make([]string, 0),
+		Modified: make([]string, 0),
+		Removed:  make([]string, 0),
 	}
+	seen := make(map[string]bool)
+
+	for _, filePath := range filePaths {
+		relativePath, err := filepath.Rel(projectPath, filePath)
+		if err != nil {
+			relativePath = filePath
+		}
+		existing := projectAnalysis.Files[relativePath]
+
+		stat, err := os.Stat(filePath)
+		if err != nil {
+			return nil, errors.NewSystemError(fmt.Sprintf("failed to rescan project: %v", err))
+		}
+
+		if existing != nil && !modulesChanged && existing.Size == stat.Size() && existing.ModTime.Equal(stat.ModTime()) {
+			seen[relativePath] = true
+			result.Unchanged++
+			continue
+		}
+
+		content, err := utils.ReadFile(filePath)
+		if err != nil {
+			return nil, errors.NewSystemError(fmt.Sprintf("failed to rescan project: failed to read file %s: %v", filePath, err))
+		}
+
+		if existing != nil && !modulesChanged && existing.ContentHash == hashContent(content) {
+			// Touched but not modified. The file info may be shared with a stored analysis.
+			touched := *existing
+			touched.ModTime = stat.ModTime()
+			projectAnalysis.Files[relativePath] = &touched
+			seen[relativePath] = true
+			result.Unchanged++
+			continue
+		}
+
+		fileInfo, err := fs.processGoFile(filePath, content, projectPath)
+		if err != nil {
+			return nil, errors.NewSystemError(fmt.Sprintf("failed to rescan project: %v", err))
+		}
+		if fileInfo == nil {
+			// Now excluded by build constraints or the generated code setting
+			continue
+		}
+
+		seen[relativePath] = true
+		projectAnalysis.Files[relativePath] = fileInfo
+		if existing == nil {
+			result.Added = append(result.Added, relativePath)
+		} else {
+			result.Modified = append(result.Modified, relativePath)
+		}
+	}
+
+	for relativePath := range projectAnalysis.Files {
+		if !seen[relativePath] {
+			delete(projectAnalysis.Files, relativePath)
+			result.Removed = append(result.Removed, relativePath)
+		}
+	}
+	sort.Strings(result.Removed)
+
+	fs.buildPackages(projectAnalysis)
+
+	return result, nil
+}
+
+// collectGoFiles walks the project and returns the Go files that pass the
+// directory and file filters, in lexical order
+func (fs *FileScanner) collectGoFiles(projectPath string) ([]string, error) {
+	var filePaths []string
 
 	err := filepath.WalkDir(projectPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -76,14 +265,11 @@ func (fs *FileScanner) ScanProject(projectPath string) (*entity.ProjectAnalysis,
 			return nil
 		}
 
-		return fs.processGoFile(path, projectAnalysis)
+		filePaths = append(filePaths, path)
+		return nil
 	})
 
-	if err != nil {
-		return nil, errors.NewSystemError(fmt.Sprintf("failed to scan project: %v", err))
-	}
-
-	return projectAnalysis, nil
+	return filePaths, err
 }
 
 func (fs *FileScanner) handleDirectory(path string, d os.DirEntry) error {
@@ -99,11 +285,8 @@ func (fs *FileScanner) handleDirectory(path string, d os.DirEntry) error {
 		return filepath.SkipDir
 	}
 
-	// Check blacklist directories
-	for _, blacklistDir := range fs.blacklistDirs {
-		if strings.Contains(path, blacklistDir) {
-			return filepath.SkipDir
-		}
+	if fs.blacklistedDir(path) {
+		return filepath.SkipDir
 	}
 
 	// If whitelist is specified, check if directory matches
@@ -123,6 +306,16 @@ func (fs *FileScanner) handleDirectory(path string, d os.DirEntry) error {
 	return nil
 }
 
+// blacklistedDir reports whether a directory matches the directory blacklist
+func (fs *FileScanner) blacklistedDir(path string) bool {
+	for _, blacklistDir := range fs.blacklistDirs {
+		if strings.Contains(path, blacklistDir) {
+			return true
+		}
+	}
+	return false
+}
+
 func (fs *FileScanner) shouldProcessFile(path string, d os.DirEntry) bool {
 	fileName := d.Name()
 
@@ -155,61 +348,119 @@ func (fs *FileScanner) shouldProcessFile(path string, d os.DirEntry) bool {
 	return true
 }
 
-func (fs *FileScanner) processGoFile(filePath string, projectAnalysis *entity.ProjectAnalysis) error {
-	content, err := utils.ReadFile(filePath)
+// processGoFile parses a single file. It returns nil without error when the
+// file is excluded by the build context or the generated code setting.
+func (fs *FileScanner) processGoFile(filePath string, content []byte, projectPath string) (*entity.FileInfo, error) {
+	stat, err := os.Stat(filePath)
 	if err != nil {
-		return errors.NewSystemError(fmt.Sprintf("failed to read file %s: %v", filePath, err))
+		return nil, errors.NewSystemError(fmt.Sprintf("failed to stat file %s: %v", filePath, err))
+	}
+
+	// Skip files excluded by the build context (//go:build lines, _GOOS/_GOARCH suffixes)
+	buildConstraint, included := fs.buildMatcher.Match(filepath.Base(filePath), content)
+	if !included {
+		return nil, nil
 	}
 
 	// Parse the Go source code
 	astFile, err := parser.ParseFile(fs.fileSet, filePath, content, parser.ParseComments)
 	if err != nil {
-		return errors.NewValidationError(fmt.Sprintf("failed to parse file %s: %v", filePath, err))
+		return nil, errors.NewValidationError(fmt.Sprintf("failed to parse file %s: %v", filePath, err))
+	}
+
+	// Generated files carry the standard "// Code generated ... DO NOT EDIT." header
+	generated := ast.IsGenerated(astFile)
+	if generated && fs.excludeGenerated {
+		return nil, nil
 	}
 
 	// Get relative path from project root
-	relativePath, err := filepath.Rel(projectAnalysis.ProjectPath, filePath)
+	relativePath, err := filepath.Rel(projectPath, filePath)
 	if err != nil {
 		relativePath = filePath
 	}
 
 	// Create file info
 	fileInfo := &entity.FileInfo{
-		Path:         relativePath,
-		AbsolutePath: filePath,
-		PackageName:  astFile.Name.Name,
-		Content:      string(content),
-		AST:          astFile,
-		Imports:      make([]string, 0),
-		Functions:    make([]*entity.FunctionInfo, 0),
-		Types:        make([]*entity.TypeInfo, 0),
-		Variables:    make([]*entity.VariableInfo, 0),
-		Constants:    make([]*entity.ConstantInfo, 0),
-		Interfaces:   make([]*entity.InterfaceInfo, 0),
-		Structs:      make([]*entity.StructInfo, 0),
-	}
-
-	// Extract imports
+		Path:            relativePath,
+		AbsolutePath:    filePath,
+		PackageName:     astFile.Name.Name,
+		Content:         string(content),
+		ContentHash:     hashContent(content),
+		Size:            stat.Size(),
+		ModTime:         stat.ModTime(),
+		AST:             astFile,
+		Generated:       generated,
+		BuildConstraint: buildConstraint,
+		Imports:         make([]string, 0),
+		ImportDetails:   make([]*entity.ImportInfo, 0),
+		Functions:       make([]*entity.FunctionInfo, 0),
+		Types:           make([]*entity.TypeInfo, 0),
+		Variables:       make([]*entity.VariableInfo, 0),
+		Constants:       make([]*entity.ConstantInfo, 0),
+		Interfaces:      make([]*entity.InterfaceInfo, 0),
+		Structs:         make([]*entity.StructInfo, 0),
+	}
+
+	// Resolve the package import path and owning module
+	module := fs.modules.ModuleForDir(filepath.Dir(relativePath))
+	fileInfo.ImportPath = fs.modules.ImportPathForDir(filepath.Dir(relativePath))
+
+	// Extract and classify imports
 	for _, imp := range astFile.Imports {
 		importPath := strings.Trim(imp.Path.Value, `"`)
 		fileInfo.Imports = append(fileInfo.Imports, importPath)
+
+		importInfo := &entity.ImportInfo{Path: importPath}
+		if imp.Name != nil {
+			importInfo.Name = imp.Name.Name
+		}
+		importInfo.Kind, importInfo.Module = fs.modules.ClassifyImport(importPath, module)
+		fileInfo.ImportDetails = append(fileInfo.ImportDetails, importInfo)
 	}
 
-	// Store file info
-	projectAnalysis.Files[relativePath] = fileInfo
+	return fileInfo, nil
+}
 
-	// Update package info
-	packagePath := fs.extractPackagePath(relativePath)
-	if _, exists := projectAnalysis.Packages[packagePath]; !exists {
-		projectAnalysis.Packages[packagePath] = &entity.PackageInfo{
-			Name:  astFile.Name.Name,
-			Path:  packagePath,
-			Files: make([]string, 0),
+// buildPackages regroups the analysis files into packages by directory
+func (fs *FileScanner) buildPackages(projectAnalysis *entity.ProjectAnalysis) {
+	filePaths := make([]string, 0, len(projectAnalysis.Files))
+	for relativePath := range projectAnalysis.Files {
+		filePaths = append(filePaths, relativePath)
+	}
+	sort.Strings(filePaths)
+
+	packages := make(map[string]*entity.PackageInfo)
+	for _, relativePath := range filePaths {
+		fileInfo := projectAnalysis.Files[relativePath]
+		packagePath := fs.extractPackagePath(relativePath)
+
+		if _, exists := packages[packagePath]; !exists {
+			packages[packagePath] = &entity.PackageInfo{
+				Name:       fileInfo.PackageName,
+				Path:       packagePath,
+				ImportPath: fileInfo.ImportPath,
+				Files:      make([]string, 0),
+			}
+			if module := fs.modules.ModuleForDir(filepath.Dir(relativePath)); module != nil {
+				packages[packagePath].Module = module.Path
+			}
 		}
+		packages[packagePath].Files = append(packages[packagePath].Files, relativePath)
 	}
-	projectAnalysis.Packages[packagePath].Files = append(projectAnalysis.Packages[packagePath].Files, relativePath)
 
-	return nil
+	projectAnalysis.Packages = packages
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func sameJSON(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
 }
 
 func (fs *FileScanner) extractPackagePath(filePath string) string {
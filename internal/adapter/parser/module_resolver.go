@@ -0,0 +1,501 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+
+	"golang.org/x/mod/semver"
+)
+
+// ModuleResolver maps project files to Go modules and import paths using the
+// go.mod, go.work and go.sum files found in the project. It never touches the
+// network or the module cache.
+type ModuleResolver struct {
+	projectPath string
+	modules     []*entity.ModuleInfo
+	workspace   *entity.WorkspaceInfo
+	sums        map[string]string // "path@version" and "path@version/go.mod" -> hash
+}
+
+// LoadModules discovers every go.mod below projectPath and the go.work file at its root.
+// Directories skipDir returns true for are not searched. Module files that cannot be
+// read or parsed are left out and reported to diagnostic. Both functions may be nil.
+func LoadModules(projectPath string, skipDir func(path string) bool, diagnostic func(path string, err error)) (*ModuleResolver, error) {
+	if diagnostic == nil {
+		diagnostic = func(string, error) {}
+	}
+
+	r := &ModuleResolver{
+		projectPath: projectPath,
+		modules:     make([]*entity.ModuleInfo, 0),
+		sums:        make(map[string]string),
+	}
+
+	err := filepath.WalkDir(projectPath, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if p != projectPath && (name == "vendor" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				(skipDir != nil && skipDir(p))) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch d.Name() {
+		case "go.mod":
+			module, err := parseGoMod(p)
+			if err != nil {
+				diagnostic(p, err)
+				return nil
+			}
+			module.Dir = r.relativeDir(filepath.Dir(p))
+			r.modules = append(r.modules, module)
+			r.loadGoSum(filepath.Join(filepath.Dir(p), "go.sum"), diagnostic)
+		case "go.work":
+			if filepath.Dir(p) != filepath.Clean(projectPath) {
+				return nil
+			}
+			workspace, err := parseGoWork(p)
+			if err != nil {
+				diagnostic(p, err)
+				return nil
+			}
+			r.workspace = workspace
+			r.loadGoSum(filepath.Join(filepath.Dir(p), "go.work.sum"), diagnostic)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.NewSystemError(fmt.Sprintf("failed to load module files: %v", err))
+	}
+
+	if r.workspace != nil {
+		uses := make(map[string]bool)
+		for _, use := range r.workspace.Uses {
+			uses[r.relativeDir(filepath.Join(projectPath, use))] = true
+		}
+		for _, module := range r.modules {
+			module.Workspace = uses[module.Dir]
+		}
+	}
+
+	// Longest directory first so that nested modules win over their parents
+	sort.Slice(r.modules, func(i, j int) bool {
+		di, dj := moduleDirDepth(r.modules[i].Dir), moduleDirDepth(r.modules[j].Dir)
+		if di != dj {
+			return di > dj
+		}
+		return r.modules[i].Dir < r.modules[j].Dir
+	})
+
+	return r, nil
+}
+
+// Modules returns the discovered modules sorted by directory
+func (r *ModuleResolver) Modules() []*entity.ModuleInfo {
+	modules := make([]*entity.ModuleInfo, len(r.modules))
+	copy(modules, r.modules)
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Dir < modules[j].Dir
+	})
+	return modules
+}
+
+// Workspace returns the parsed go.work file, or nil if the project has none
+func (r *ModuleResolver) Workspace() *entity.WorkspaceInfo {
+	return r.workspace
+}
+
+// ModuleForDir returns the module owning a directory relative to the project root
+func (r *ModuleResolver) ModuleForDir(relDir string) *entity.ModuleInfo {
+	relDir = filepath.ToSlash(filepath.Clean(relDir))
+	for _, module := range r.modules {
+		if module.Dir == "." || relDir == module.Dir || strings.HasPrefix(relDir, module.Dir+"/") {
+			return module
+		}
+	}
+	return nil
+}
+
+// ImportPathForDir returns the full import path of the package in relDir.
+// Outside of any module the relative directory is returned unchanged.
+func (r *ModuleResolver) ImportPathForDir(relDir string) string {
+	relDir = filepath.ToSlash(filepath.Clean(relDir))
+	module := r.ModuleForDir(relDir)
+	if module == nil {
+		if relDir == "." {
+			return ""
+		}
+		return relDir
+	}
+
+	if relDir == module.Dir {
+		return module.Path
+	}
+	suffix := relDir
+	if module.Dir != "." {
+		suffix = strings.TrimPrefix(relDir, module.Dir+"/")
+	}
+	return path.Join(module.Path, suffix)
+}
+
+// ClassifyImport determines whether an import refers to the standard library,
+// the importing module, another workspace module or a third-party module
+func (r *ModuleResolver) ClassifyImport(importPath string, from *entity.ModuleInfo) (string, string) {
+	if owner := r.moduleForImport(importPath); owner != nil {
+		switch {
+		case from != nil && owner.Path == from.Path:
+			return entity.ImportKindSameModule, owner.Path
+		case owner.Workspace && (from == nil || from.Workspace):
+			return entity.ImportKindWorkspaceModule, owner.Path
+		}
+	}
+
+	if isStdlibImport(importPath) {
+		return entity.ImportKindStdlib, ""
+	}
+
+	return entity.ImportKindThirdParty, r.requiredModuleFor(importPath, from)
+}
+
+// Dependencies returns the required modules of every discovered module with
+// their go.sum checksums and effective replacements
+func (r *ModuleResolver) Dependencies() []*entity.ModuleDependency {
+	byPath := make(map[string]*entity.ModuleDependency)
+
+	for _, module := range r.modules {
+		for _, req := range module.Requires {
+			dep, exists := byPath[req.Path]
+			if !exists {
+				dep = &entity.ModuleDependency{
+					Path:       req.Path,
+					Version:    req.Version,
+					Indirect:   req.Indirect,
+					RequiredBy: make([]string, 0),
+				}
+				byPath[req.Path] = dep
+			} else if semver.Compare(req.Version, dep.Version) > 0 {
+				// Minimal version selection picks the highest required version
+				dep.Version = req.Version
+			}
+			dep.Indirect = dep.Indirect && req.Indirect
+			dep.RequiredBy = append(dep.RequiredBy, module.Path)
+			if replace := r.replacementFor(module, req.Path, req.Version); replace != nil {
+				dep.Replace = replace
+			}
+		}
+	}
+
+	dependencies := make([]*entity.ModuleDependency, 0, len(byPath))
+	for _, dep := range byPath {
+		dep.Sum = r.sums[dep.Path+"@"+dep.Version]
+		dep.GoModSum = r.sums[dep.Path+"@"+dep.Version+"/go.mod"]
+		dependencies = append(dependencies, dep)
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].Path < dependencies[j].Path
+	})
+
+	return dependencies
+}
+
+func (r *ModuleResolver) moduleForImport(importPath string) *entity.ModuleInfo {
+	var best *entity.ModuleInfo
+	for _, module := range r.modules {
+		if importPath == module.Path || strings.HasPrefix(importPath, module.Path+"/") {
+			if best == nil || len(module.Path) > len(best.Path) {
+				best = module
+			}
+		}
+	}
+	return best
+}
+
+func (r *ModuleResolver) requiredModuleFor(importPath string, from *entity.ModuleInfo) string {
+	if from == nil {
+		return ""
+	}
+
+	best := ""
+	for _, req := range from.Requires {
+		if (importPath == req.Path || strings.HasPrefix(importPath, req.Path+"/")) && len(req.Path) > len(best) {
+			best = req.Path
+		}
+	}
+	return best
+}
+
+func (r *ModuleResolver) replacementFor(module *entity.ModuleInfo, modPath, version string) *entity.ModuleReplace {
+	// go.work replacements override those of individual modules
+	var candidates []*entity.ModuleReplace
+	if r.workspace != nil {
+		candidates = append(candidates, r.workspace.Replaces...)
+	}
+	candidates = append(candidates, module.Replaces...)
+
+	for _, replace := range candidates {
+		if replace.OldPath == modPath && (replace.OldVersion == "" || replace.OldVersion == version) {
+			return replace
+		}
+	}
+	return nil
+}
+
+func (r *ModuleResolver) relativeDir(dir string) string {
+	rel, err := filepath.Rel(r.projectPath, dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// loadGoSum adds the checksums of a go.sum file; a missing file is not an error
+func (r *ModuleResolver) loadGoSum(sumPath string, diagnostic func(path string, err error)) {
+	file, err := os.Open(sumPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			diagnostic(sumPath, err)
+		}
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		r.sums[fields[0]+"@"+fields[1]] = fields[2]
+	}
+
+	if err := scanner.Err(); err != nil {
+		diagnostic(sumPath, err)
+	}
+}
+
+func moduleDirDepth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// isStdlibImport applies the go tool's rule: standard library import paths
+// have no dot in their first element
+func isStdlibImport(importPath string) bool {
+	first := importPath
+	if i := strings.Index(importPath, "/"); i >= 0 {
+		first = importPath[:i]
+	}
+	return !strings.Contains(first, ".")
+}
+
+// go.mod and go.work parsing
+
+type modDirective struct {
+	verb    string
+	args    []string
+	comment string
+}
+
+func parseGoMod(filePath string) (*entity.ModuleInfo, error) {
+	directives, err := readModDirectives(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &entity.ModuleInfo{
+		Requires: make([]*entity.ModuleRequirement, 0),
+		Replaces: make([]*entity.ModuleReplace, 0),
+	}
+
+	for _, d := range directives {
+		switch d.verb {
+		case "module":
+			if len(d.args) > 0 {
+				module.Path = d.args[0]
+			}
+		case "go":
+			if len(d.args) > 0 {
+				module.GoVersion = d.args[0]
+			}
+		case "toolchain":
+			if len(d.args) > 0 {
+				module.Toolchain = d.args[0]
+			}
+		case "require":
+			if len(d.args) >= 2 {
+				module.Requires = append(module.Requires, &entity.ModuleRequirement{
+					Path:     d.args[0],
+					Version:  d.args[1],
+					Indirect: strings.TrimSpace(d.comment) == "indirect",
+				})
+			}
+		case "replace":
+			if replace := parseReplace(d.args); replace != nil {
+				module.Replaces = append(module.Replaces, replace)
+			}
+		}
+	}
+
+	if module.Path == "" {
+		return nil, fmt.Errorf("%s: missing module directive", filePath)
+	}
+
+	return module, nil
+}
+
+func parseGoWork(filePath string) (*entity.WorkspaceInfo, error) {
+	directives, err := readModDirectives(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := &entity.WorkspaceInfo{
+		Uses:     make([]string, 0),
+		Replaces: make([]*entity.ModuleReplace, 0),
+	}
+
+	for _, d := range directives {
+		switch d.verb {
+		case "go":
+			if len(d.args) > 0 {
+				workspace.GoVersion = d.args[0]
+			}
+		case "use":
+			if len(d.args) > 0 {
+				workspace.Uses = append(workspace.Uses, d.args[0])
+			}
+		case "replace":
+			if replace := parseReplace(d.args); replace != nil {
+				workspace.Replaces = append(workspace.Replaces, replace)
+			}
+		}
+	}
+
+	return workspace, nil
+}
+
+func parseReplace(args []string) *entity.ModuleReplace {
+	arrow := -1
+	for i, arg := range args {
+		if arg == "=>" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 1 || arrow == len(args)-1 {
+		return nil
+	}
+
+	replace := &entity.ModuleReplace{
+		OldPath: args[0],
+		NewPath: args[arrow+1],
+	}
+	if arrow == 2 {
+		replace.OldVersion = args[1]
+	}
+	if len(args) > arrow+2 {
+		replace.NewVersion = args[arrow+2]
+	}
+
+	return replace
+}
+
+// readModDirectives tokenizes a go.mod/go.work file into directives, expanding
+// factored blocks such as require ( ... ) into one directive per line
+func readModDirectives(filePath string) ([]*modDirective, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var directives []*modDirective
+	blockVerb := ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields, comment := splitModFields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if blockVerb != "" {
+			if fields[0] == ")" {
+				blockVerb = ""
+				continue
+			}
+			directives = append(directives, &modDirective{verb: blockVerb, args: fields, comment: comment})
+			continue
+		}
+
+		if len(fields) == 2 && fields[1] == "(" {
+			blockVerb = fields[0]
+			continue
+		}
+
+		directives = append(directives, &modDirective{verb: fields[0], args: fields[1:], comment: comment})
+	}
+
+	return directives, scanner.Err()
+}
+
+// splitModFields splits a go.mod line into fields and its trailing // comment.
+// Interpreted ("...") and raw (`...`) strings are unquoted and may contain spaces
+// or //.
+func splitModFields(line string) ([]string, string) {
+	var fields []string
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(line[i:], "//"):
+			return fields, line[i+2:]
+		case c == '"' || c == '`':
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if c == '"' && line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				// Unterminated string; keep the rest as is
+				return append(fields, line[i:]), ""
+			}
+			field := line[i : end+1]
+			if unquoted, err := strconv.Unquote(field); err == nil {
+				field = unquoted
+			}
+			fields = append(fields, field)
+			i = end + 1
+		default:
+			end := i
+			for end < len(line) && line[end] != ' ' && line[end] != '\t' && line[end] != '\r' &&
+				line[end] != '"' && line[end] != '`' && !strings.HasPrefix(line[end:], "//") {
+				end++
+			}
+			fields = append(fields, line[i:end])
+			i = end
+		}
+	}
+	return fields, ""
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProject creates the files of a fixture project, keyed by slash-separated path
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestModuleResolverDependencyVersions(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"go.mod":     "module example.com/shop\n\nrequire (\n\texample.com/lib v1.2.3\n\texample.com/util v1.9.0\n\texample.com/legacy v2.0.0+incompatible\n)\n",
+		"rc/go.mod":  "module example.com/shop/rc\n\nrequire (\n\texample.com/lib v1.2.3-rc.1\n\texample.com/legacy v2.1.0+incompatible\n)\n",
+		"old/go.mod": "module example.com/shop/old\n\nrequire example.com/util v1.10.0\n",
+	})
+
+	resolver, err := LoadModules(root, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadModules: %v", err)
+	}
+
+	versions := map[string]string{}
+	for _, dep := range resolver.Dependencies() {
+		versions[dep.Path] = dep.Version
+	}
+	want := map[string]string{
+		"example.com/lib":    "v1.2.3",
+		"example.com/util":   "v1.10.0",
+		"example.com/legacy": "v2.1.0+incompatible",
+	}
+	for path, version := range want {
+		if versions[path] != version {
+			t.Errorf("dependency %s resolved to %q, want %q", path, versions[path], version)
+		}
+	}
+}
+
+func TestLoadModulesSkipsBadModules(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"go.mod":                  "module example.com/shop\n",
+		"broken/go.mod":           "require example.com/lib v1.0.0\n",
+		"third_party/lib/go.mod":  "not a go.mod\n",
+		"tools/go.mod":            "module example.com/shop/tools\n",
+		"third_party/ok/main.go":  "package ok\n",
+		"broken/broken.go":        "package broken\n",
+		"tools/cmd/generate/x.go": "package main\n",
+	})
+
+	var reported []string
+	resolver, err := LoadModules(root,
+		func(path string) bool { return strings.Contains(path, "third_party") },
+		func(path string, err error) { reported = append(reported, path) })
+	if err != nil {
+		t.Fatalf("LoadModules: %v", err)
+	}
+
+	var modules []string
+	for _, module := range resolver.Modules() {
+		modules = append(modules, module.Path)
+	}
+	if got := strings.Join(modules, ","); got != "example.com/shop,example.com/shop/tools" {
+		t.Errorf("loaded modules %s, want example.com/shop and example.com/shop/tools", got)
+	}
+	if len(reported) != 1 || reported[0] != filepath.Join(root, "broken", "go.mod") {
+		t.Errorf("reported %v, want only broken/go.mod", reported)
+	}
+}
@@ -0,0 +1,17 @@
+package parser
+
+// ScanObserver is notified as a scan progresses
+type ScanObserver interface {
+	PhaseChanged(phase string)
+	FilesFound(total int)
+	FileScanned(path string, err error)
+	Diagnostic(path string, err error) // A problem that does not stop the scan
+}
+
+// nopObserver ignores all progress notifications
+type nopObserver struct{}
+
+func (nopObserver) PhaseChanged(string)       {}
+func (nopObserver) FilesFound(int)            {}
+func (nopObserver) FileScanned(string, error) {}
+func (nopObserver) Diagnostic(string, error)  {}
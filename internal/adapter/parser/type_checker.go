@@ -0,0 +1,357 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/infrastructure/logger"
+)
+
+const (
+	defaultCheckedProjects    = 8  // Type-checked projects kept for reuse without a configured limit
+	maxTypeErrors             = 20 // Type errors kept per project
+	checkedBytesPerSourceByte = 12 // Syntax trees and type information relative to the source
+)
+
+// TypeCheckerConfig bounds the type-checked projects kept for reuse, in line
+// with the retention limits of stored projects. Zero values use the defaults.
+type TypeCheckerConfig struct {
+	MaxProjects     int   // Least recently used projects are dropped beyond this count
+	MaxProjectBytes int64 // Projects estimated larger than this are checked again on every use
+}
+
+// CheckedProject holds the syntax trees of a project together with the type
+// information go/types derived from them
+type CheckedProject struct {
+	FileSet *token.FileSet
+	Files   map[string]*ast.File // Relative path -> syntax tree
+	Info    *types.Info
+	Errors  []string // First type errors; packages outside the project may be unresolved
+
+	paths     map[string]string         // File name in FileSet -> relative path
+	packages  map[string]bool           // Import paths checked from the files of the project
+	imported  map[string]*types.Package // Import path -> packages loaded with type information
+	version   int
+	updatedAt time.Time
+}
+
+// Declaration locates the declaration of an object within the project. Objects of
+// packages outside the project, and predeclared ones, have no position in FileSet.
+func (c *CheckedProject) Declaration(obj types.Object) (string, token.Position, bool) {
+	if obj == nil || obj.Pkg() == nil || !c.packages[obj.Pkg().Path()] || !obj.Pos().IsValid() {
+		return "", token.Position{}, false
+	}
+	position := c.FileSet.Position(obj.Pos())
+	filePath, exists := c.paths[position.Filename]
+	return filePath, position, exists
+}
+
+// Resolves reports whether the type information of a package, named by import
+// path or package name, was loaded for the project
+func (c *CheckedProject) Resolves(pkg string) bool {
+	if _, exists := c.imported[pkg]; exists {
+		return true
+	}
+	for _, imported := range c.imported {
+		if imported.Name() == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeChecker type-checks analyzed projects. Packages of the project are checked
+// from their stored syntax trees; other imports are loaded in module mode from the
+// export data `go list -export` builds for the project.
+// Projects are checked in parallel; concurrent requests for one project share a check.
+type TypeChecker struct {
+	entries map[string]*checkEntry
+	config  TypeCheckerConfig
+	mutex   sync.Mutex // Guards entries and their lastUsed
+	logger  logger.Logger
+}
+
+// checkEntry caches the type-checked state of one project
+type checkEntry struct {
+	mutex    sync.Mutex // Held while the project is checked
+	project  *CheckedProject
+	lastUsed time.Time
+}
+
+// NewTypeChecker creates a type checker; a nil config uses the defaults
+func NewTypeChecker(config *TypeCheckerConfig) *TypeChecker {
+	tc := &TypeChecker{
+		entries: make(map[string]*checkEntry),
+		logger:  logger.GetLogger(),
+	}
+	if config != nil {
+		tc.config = *config
+	}
+	if tc.config.MaxProjects <= 0 {
+		tc.config.MaxProjects = defaultCheckedProjects
+	}
+	return tc
+}
+
+// Check type-checks a project, reusing the previous result while the analysis is unchanged
+func (tc *TypeChecker) Check(analysis *entity.ProjectAnalysis) *CheckedProject {
+	tc.mutex.Lock()
+	entry := tc.entries[analysis.ID]
+	if entry == nil {
+		entry = &checkEntry{}
+		tc.entries[analysis.ID] = entry
+	}
+	entry.lastUsed = time.Now()
+	tc.evict(analysis.ID)
+	tc.mutex.Unlock()
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if checked := entry.project; checked != nil &&
+		checked.version == analysis.Version && checked.updatedAt.Equal(analysis.UpdatedAt) {
+		return checked
+	}
+
+	start := time.Now()
+	checked := tc.check(analysis)
+
+	size := estimateCheckedBytes(analysis)
+	entry.project = nil
+	if tc.config.MaxProjectBytes <= 0 || size <= tc.config.MaxProjectBytes {
+		entry.project = checked
+	}
+
+	tc.logger.WithFields(map[string]interface{}{
+		"project_id":  analysis.ID,
+		"files":       len(checked.Files),
+		"type_errors": len(checked.Errors),
+		"bytes":       size,
+		"kept":        entry.project != nil,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}).Info("Project type-checked")
+
+	return checked
+}
+
+// Forget drops the type-checked state of a project, for example once it is deleted
+func (tc *TypeChecker) Forget(projectID string) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	delete(tc.entries, projectID)
+}
+
+// evict drops the least recently used projects beyond the limit, never keep.
+// Callers must hold the mutex.
+func (tc *TypeChecker) evict(keep string) {
+	for len(tc.entries) > tc.config.MaxProjects {
+		oldest := ""
+		for projectID, entry := range tc.entries {
+			if projectID != keep && (oldest == "" || entry.lastUsed.Before(tc.entries[oldest].lastUsed)) {
+				oldest = projectID
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		delete(tc.entries, oldest)
+	}
+}
+
+func estimateCheckedBytes(analysis *entity.ProjectAnalysis) int64 {
+	var size int64
+	for _, fileInfo := range analysis.Files {
+		size += int64(len(fileInfo.Content)) * checkedBytesPerSourceByte
+	}
+	return size
+}
+
+// LoadSyntax returns the syntax tree of every file of an analysis. Trees released
+// to save memory, or never kept as with snapshots loaded from disk, are parsed
+// again from the stored content.
+func LoadSyntax(analysis *entity.ProjectAnalysis) (*token.FileSet, map[string]*ast.File) {
+	fileSet := analysis.FileSet
+	reparse := fileSet == nil
+	if reparse {
+		fileSet = token.NewFileSet()
+	}
+
+	files := make(map[string]*ast.File, len(analysis.Files))
+	for filePath, fileInfo := range analysis.Files {
+		if fileInfo.AST != nil && !reparse {
+			files[filePath] = fileInfo.AST
+			continue
+		}
+
+		name := fileInfo.AbsolutePath
+		if name == "" {
+			name = filePath
+		}
+		astFile, err := parser.ParseFile(fileSet, name, fileInfo.Content, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		files[filePath] = astFile
+	}
+
+	return fileSet, files
+}
+
+// packageFiles groups the files of one package; external test packages are kept apart
+type packageFiles struct {
+	importPath string
+	name       string
+	files      []*ast.File
+}
+
+func (tc *TypeChecker) check(analysis *entity.ProjectAnalysis) *CheckedProject {
+	fileSet, files := LoadSyntax(analysis)
+	checked := &CheckedProject{
+		FileSet: fileSet,
+		Files:   files,
+		Info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			Implicits:  make(map[ast.Node]types.Object),
+		},
+		paths:     make(map[string]string, len(files)),
+		packages:  make(map[string]bool),
+		imported:  make(map[string]*types.Package),
+		version:   analysis.Version,
+		updatedAt: analysis.UpdatedAt,
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath, astFile := range files {
+		paths = append(paths, filePath)
+		checked.paths[fileSet.File(astFile.Pos()).Name()] = filePath
+	}
+	sort.Strings(paths)
+
+	packages := make(map[string]*packageFiles)
+	var order []string
+	for _, filePath := range paths {
+		fileInfo := analysis.Files[filePath]
+		importPath := fileInfo.ImportPath
+		if importPath == "" {
+			importPath = path.Dir(filePath)
+		}
+		key := importPath + "|" + fileInfo.PackageName
+		if packages[key] == nil {
+			packages[key] = &packageFiles{importPath: importPath, name: fileInfo.PackageName}
+			order = append(order, key)
+			checked.packages[importPath] = true
+		}
+		packages[key].files = append(packages[key].files, files[filePath])
+	}
+
+	// Importable packages of the project, excluding external test packages
+	local := make(map[string]*packageFiles)
+	for _, key := range order {
+		pkg := packages[key]
+		if !strings.HasSuffix(pkg.name, "_test") {
+			local[pkg.importPath] = pkg
+		}
+	}
+
+	exports, errs := dependencyExports(analysis)
+	for _, err := range errs {
+		tc.logger.WithError(err).WithField("project_id", analysis.ID).Warn("Failed to load dependencies for type checking")
+		checked.addError(err)
+	}
+	dependencies := importer.ForCompiler(token.NewFileSet(), "gc", func(importPath string) (io.ReadCloser, error) {
+		export, exists := exports[importPath]
+		if !exists {
+			return nil, fmt.Errorf("no export data for %s", importPath)
+		}
+		return os.Open(export)
+	})
+
+	loaded := make(map[string]*types.Package)
+	var imp importerFunc
+	imp = func(importPath string) (*types.Package, error) {
+		if pkg, exists := loaded[importPath]; exists {
+			return pkg, nil
+		}
+		if pkg := local[importPath]; pkg != nil {
+			// Registered before checking so that import cycles end with an incomplete package
+			loaded[importPath] = types.NewPackage(importPath, pkg.name)
+			loaded[importPath] = tc.checkPackage(checked, pkg, imp)
+			checked.addImported(loaded[importPath])
+			return loaded[importPath], nil
+		}
+		pkg, err := dependencies.Import(importPath)
+		if err != nil {
+			pkg = types.NewPackage(importPath, path.Base(importPath))
+			pkg.MarkComplete()
+		} else {
+			checked.addImported(pkg)
+		}
+		loaded[importPath] = pkg
+		return pkg, nil
+	}
+
+	for _, key := range order {
+		pkg := packages[key]
+		if local[pkg.importPath] == pkg {
+			imp(pkg.importPath)
+			continue
+		}
+		tc.checkPackage(checked, pkg, imp)
+	}
+
+	return checked
+}
+
+func (tc *TypeChecker) checkPackage(checked *CheckedProject, pkg *packageFiles, imp types.Importer) *types.Package {
+	config := &types.Config{
+		Importer:    imp,
+		FakeImportC: true,
+		Error:       checked.addError,
+	}
+
+	// Errors are collected above; the package is returned even when incomplete
+	typesPkg, _ := config.Check(pkg.importPath, checked.FileSet, pkg.files, checked.Info)
+	if typesPkg == nil {
+		typesPkg = types.NewPackage(pkg.importPath, pkg.name)
+	}
+	return typesPkg
+}
+
+func (c *CheckedProject) addError(err error) {
+	if len(c.Errors) < maxTypeErrors {
+		c.Errors = append(c.Errors, err.Error())
+	}
+}
+
+// addImported records a loaded package along with the packages it imports
+func (c *CheckedProject) addImported(pkg *types.Package) {
+	if _, exists := c.imported[pkg.Path()]; exists {
+		return
+	}
+	c.imported[pkg.Path()] = pkg
+	for _, imported := range pkg.Imports() {
+		c.addImported(imported)
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
@@ -2,10 +2,25 @@ package entity
 
 // APIEndpoint represents a discovered API endpoint in the project
 type APIEndpoint struct {
-	ID     string `json:"id"`
-	Method string `json:"method"` // GET, POST, PUT, DELETE, etc.
-	Path   string `json:"path"`   // /api/v1/users/:id
-	File   string `json:"file"`   // File where the endpoint is defined
+	ID          string   `json:"id"`
+	Method      string   `json:"method"`                // GET, POST, PUT, DELETE, etc.
+	Path        string   `json:"path"`                  // /api/v1/users/:id
+	File        string   `json:"file"`                  // File where the endpoint is defined
+	Handler     string   `json:"handler,omitempty"`     // Handler expression as registered
+	Middlewares []string `json:"middlewares,omitempty"` // Route-level middlewares in registration order
+
+	// Contract inferred from the handler body
+	HandlerSymbol string           `json:"handler_symbol,omitempty"` // Symbol key of the resolved handler function
+	PathParams    []string         `json:"path_params,omitempty"`
+	QueryParams   []*EndpointParam `json:"query_params,omitempty"`
+	RequestType   string           `json:"request_type,omitempty"`   // Type bound from the request body
+	ResponseTypes []string         `json:"response_types,omitempty"` // Types written as response bodies
+}
+
+// EndpointParam is a query parameter read by an endpoint handler
+type EndpointParam struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"` // Bound with binding:"required"
 }
 
 // APIStatistics contains statistics for a specific API endpoint
@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// CallTree is the tree of the callers or callees of a function, up to a depth
+type CallTree struct {
+	ProjectID   string        `json:"project_id"`
+	Direction   string        `json:"direction"` // callers or callees
+	Depth       int           `json:"depth"`
+	Root        *CallTreeNode `json:"root"`
+	Nodes       int           `json:"nodes"`
+	Truncated   bool          `json:"truncated"` // The tree reached its size limit before the requested depth
+	TypeErrors  []string      `json:"type_errors,omitempty"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// CallTreeNode is a function of a call tree, reached from its parent through the call sites listed
+type CallTreeNode struct {
+	Node      *CodeNodeRef    `json:"node"`
+	Function  string          `json:"function"`
+	Kind      string          `json:"kind,omitempty"` // Edge from the parent: static, interface, closure or goroutine
+	CallSites []*CallSite     `json:"call_sites,omitempty"`
+	Cycle     bool            `json:"cycle,omitempty"` // Already on the path from the root, so not expanded again
+	Children  []*CallTreeNode `json:"children,omitempty"`
+}
+
+// CallSite is the location of a call
+type CallSite struct {
+	File     string    `json:"file"`
+	Position *Position `json:"position"`
+}
+
+// CallPath is the shortest chain of calls leading from one function to another
+type CallPath struct {
+	ProjectID   string          `json:"project_id"`
+	From        *CodeNodeRef    `json:"from"`
+	To          *CodeNodeRef    `json:"to"`
+	Found       bool            `json:"found"`
+	Length      int             `json:"length"` // Number of calls
+	Steps       []*CallPathStep `json:"steps"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// CallPathStep is a function of a call path with the call leading to it; the first step has no call
+type CallPathStep struct {
+	Node     *CodeNodeRef `json:"node"`
+	Function string       `json:"function"`
+	Kind     string       `json:"kind,omitempty"`
+	CallSite *CallSite    `json:"call_site,omitempty"`
+}
@@ -9,14 +9,19 @@ type Position struct {
 
 // CodeNode represents a parsed code element (function, struct, interface, etc.)
 type CodeNode struct {
-	ID       string                 `json:"id"`
-	Name     string                 `json:"name"`
-	Type     string                 `json:"type"` // function, struct, interface, type, variable, constant
-	File     string                 `json:"file"`
-	Package  string                 `json:"package"`
-	Body     string                 `json:"body"`
-	Position *Position              `json:"position,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Type            string                 `json:"type"` // function, struct, interface, type, variable, constant
+	File            string                 `json:"file"`
+	Package         string                 `json:"package"`
+	PackagePath     string                 `json:"package_path,omitempty"` // Full import path of the package
+	SymbolKey       string                 `json:"symbol_key"`             // Canonical key: package path, receiver and name
+	Doc             string                 `json:"doc,omitempty"`          // Doc comment of the declaration
+	Body            string                 `json:"body"`
+	Position        *Position              `json:"position,omitempty"`
+	Generated       bool                   `json:"generated"`
+	BuildConstraint string                 `json:"build_constraint,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // Parameter represents a function parameter
@@ -45,10 +50,12 @@ type FunctionInfo struct {
 	IsMethod   bool            `json:"is_method"`
 	Parameters []*Parameter    `json:"parameters"`
 	Returns    []*Return       `json:"returns"`
+	Doc        string          `json:"doc,omitempty"`
 	Body       string          `json:"body"`
 	Position   *Position       `json:"position,omitempty"`
 	CallsTo    []*FunctionCall `json:"calls_to"`
 	UsedTypes  []string        `json:"used_types"`
+	Complexity int             `json:"complexity"` // Cyclomatic complexity
 }
 
 // StructField represents a field in a struct
@@ -63,6 +70,7 @@ type StructField struct {
 type StructInfo struct {
 	Name   string         `json:"name"`
 	Fields []*StructField `json:"fields"`
+	Doc    string         `json:"doc,omitempty"`
 	Body   string         `json:"body"`
 }
 
@@ -77,6 +85,7 @@ type InterfaceMethod struct {
 type InterfaceInfo struct {
 	Name    string             `json:"name"`
 	Methods []*InterfaceMethod `json:"methods"`
+	Doc     string             `json:"doc,omitempty"`
 	Body    string             `json:"body"`
 }
 
@@ -84,6 +93,7 @@ type InterfaceInfo struct {
 type TypeInfo struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+	Doc  string `json:"doc,omitempty"`
 	Body string `json:"body"`
 }
 
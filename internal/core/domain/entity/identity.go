@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SymbolKey builds the canonical key of a declaration from its package path,
+// receiver type and name, e.g. "example.com/app/usecase.Service.Run".
+// Pointer markers and type parameters are dropped from the receiver.
+func SymbolKey(packagePath, receiver, name string) string {
+	parts := make([]string, 0, 3)
+	if packagePath != "" {
+		parts = append(parts, packagePath)
+	}
+	if receiver = NormalizeReceiver(receiver); receiver != "" {
+		parts = append(parts, receiver)
+	}
+	parts = append(parts, name)
+
+	return strings.Join(parts, ".")
+}
+
+// NormalizeReceiver turns "*Service[T]" into "Service"
+func NormalizeReceiver(receiver string) string {
+	receiver = strings.TrimPrefix(strings.TrimSpace(receiver), "*")
+	if i := strings.Index(receiver, "["); i >= 0 {
+		receiver = receiver[:i]
+	}
+	return receiver
+}
+
+// NodeID derives the stable ID of a code node from its kind and symbol key
+func NodeID(kind, symbolKey string) string {
+	return stableID("node", kind, symbolKey)
+}
+
+// EndpointID derives the stable ID of an API endpoint from its method and
+// normalized path, so that renaming a path parameter keeps the same ID
+func EndpointID(method, path string) string {
+	return stableID("endpoint", strings.ToUpper(method), NormalizeRoutePath(path))
+}
+
+// NormalizeRoutePath replaces path parameter names with a placeholder:
+// /users/:id, /users/{userId} and /users/<uid> all become /users/{}
+// while catch-all segments such as *filepath become {*}
+func NormalizeRoutePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"),
+			strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && !strings.HasSuffix(segment, "...}"),
+			strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">"):
+			segments[i] = "{}"
+		case strings.HasPrefix(segment, "*"),
+			strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}"):
+			segments[i] = "{*}"
+		}
+	}
+
+	normalized := strings.Join(segments, "/")
+	if len(normalized) > 1 {
+		normalized = strings.TrimSuffix(normalized, "/")
+	}
+	return normalized
+}
+
+func stableID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
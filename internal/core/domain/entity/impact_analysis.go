@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// LineRange is a range of lines of a project file, both ends included
+type LineRange struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// ChangeSet describes code changed by a pull request: code nodes, line ranges
+// or a unified diff, any of which may be combined
+type ChangeSet struct {
+	NodeIDs []string     `json:"node_ids,omitempty"`
+	Ranges  []*LineRange `json:"ranges,omitempty"`
+	Diff    string       `json:"diff,omitempty"`
+}
+
+// ImpactAnalysis lists the API endpoints whose behavior a change may affect
+type ImpactAnalysis struct {
+	ProjectID     string            `json:"project_id"`
+	Changed       []*ChangedSymbol  `json:"changed"`
+	Endpoints     []*EndpointImpact `json:"endpoints"`
+	ImpactedNodes int               `json:"impacted_nodes"`       // Declarations reached from the change
+	Unresolved    []string          `json:"unresolved,omitempty"` // Parts of the change set matching no declaration
+	TypeErrors    []string          `json:"type_errors,omitempty"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+}
+
+// ChangedSymbol is a declaration touched by the change set
+type ChangedSymbol struct {
+	Node   *CodeNodeRef `json:"node,omitempty"`
+	Symbol string       `json:"symbol"`
+	Kind   string       `json:"kind"`
+}
+
+// EndpointImpact is an endpoint reached from a changed declaration, with the
+// shortest chain of calls and type usages leading to its handler
+type EndpointImpact struct {
+	Endpoint *APIEndpoint  `json:"endpoint"`
+	Changed  string        `json:"changed"` // Changed symbol the path starts from
+	Path     []*ImpactStep `json:"path"`
+}
+
+// ImpactStep is a declaration of an impact path with the use leading to it from the previous step
+type ImpactStep struct {
+	Node   *CodeNodeRef `json:"node,omitempty"`
+	Symbol string       `json:"symbol"`
+	Kind   string       `json:"kind,omitempty"` // static, interface, closure, goroutine or usage
+	Site   *CallSite    `json:"site,omitempty"`
+}
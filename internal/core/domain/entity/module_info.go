@@ -0,0 +1,61 @@
+package entity
+
+// Import classification kinds
+const (
+	ImportKindStdlib          = "stdlib"
+	ImportKindSameModule      = "same_module"
+	ImportKindWorkspaceModule = "workspace_module"
+	ImportKindThirdParty      = "third_party"
+)
+
+// ModuleInfo describes a Go module found in the project (a go.mod file)
+type ModuleInfo struct {
+	Path      string               `json:"path"`
+	Dir       string               `json:"dir"` // Directory relative to the project root
+	GoVersion string               `json:"go_version,omitempty"`
+	Toolchain string               `json:"toolchain,omitempty"`
+	Requires  []*ModuleRequirement `json:"requires"`
+	Replaces  []*ModuleReplace     `json:"replaces,omitempty"`
+	Workspace bool                 `json:"workspace"` // Listed in a go.work use directive
+}
+
+// ModuleRequirement represents a require directive of a go.mod file
+type ModuleRequirement struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect"`
+}
+
+// ModuleReplace represents a replace directive of a go.mod or go.work file
+type ModuleReplace struct {
+	OldPath    string `json:"old_path"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewPath    string `json:"new_path"`
+	NewVersion string `json:"new_version,omitempty"`
+}
+
+// WorkspaceInfo describes a go.work file at the project root
+type WorkspaceInfo struct {
+	GoVersion string           `json:"go_version,omitempty"`
+	Uses      []string         `json:"uses"`
+	Replaces  []*ModuleReplace `json:"replaces,omitempty"`
+}
+
+// ModuleDependency is a required module with the checksums recorded in go.sum
+type ModuleDependency struct {
+	Path       string         `json:"path"`
+	Version    string         `json:"version"`
+	Indirect   bool           `json:"indirect"`
+	Sum        string         `json:"sum,omitempty"`
+	GoModSum   string         `json:"go_mod_sum,omitempty"`
+	Replace    *ModuleReplace `json:"replace,omitempty"`
+	RequiredBy []string       `json:"required_by"`
+}
+
+// ImportInfo describes a single import of a file and where it resolves to
+type ImportInfo struct {
+	Path   string `json:"path"`
+	Name   string `json:"name,omitempty"` // Explicit import name, if any
+	Kind   string `json:"kind"`           // stdlib, same_module, workspace_module, third_party
+	Module string `json:"module,omitempty"`
+}
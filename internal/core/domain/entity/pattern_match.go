@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// PatternSearchResult lists the code matching a structural pattern
+type PatternSearchResult struct {
+	ProjectID     string          `json:"project_id"`
+	Pattern       string          `json:"pattern"`
+	Matches       []*PatternMatch `json:"matches"`
+	Total         int             `json:"total"`     // Matches found, including those beyond the limit
+	Truncated     bool            `json:"truncated"` // More matches exist than returned
+	FilesSearched int             `json:"files_searched"`
+	TypeErrors    []string        `json:"type_errors,omitempty"` // Type information may be incomplete where these occurred
+	DurationMs    int64           `json:"duration_ms"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+}
+
+// PatternMatch is a piece of code matching a pattern, with the code bound to each metavariable
+type PatternMatch struct {
+	File     string            `json:"file"`
+	Function string            `json:"function,omitempty"` // Enclosing function or method
+	Position *Position         `json:"position"`
+	End      *Position         `json:"end"`
+	Text     string            `json:"text"`
+	Bindings map[string]string `json:"bindings"`
+}
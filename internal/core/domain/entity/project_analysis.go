@@ -2,42 +2,61 @@ package entity
 
 import (
 	"go/ast"
+	"go/token"
 	"time"
 )
 
 // ProjectAnalysis represents the complete analysis of a Go project
 type ProjectAnalysis struct {
-	ID              string                  `json:"id"`
-	ProjectPath     string                  `json:"project_path"`
-	Files           map[string]*FileInfo    `json:"files"`
-	Packages        map[string]*PackageInfo `json:"packages"`
-	APIEndpoints    []*APIEndpoint          `json:"api_endpoints"`
-	DependencyGraph *DependencyGraph        `json:"dependency_graph"`
-	CreatedAt       time.Time               `json:"created_at"`
-	UpdatedAt       time.Time               `json:"updated_at"`
+	ID                 string                  `json:"id"`
+	Version            int                     `json:"version"` // Increases with every full scan of the same project
+	ProjectPath        string                  `json:"project_path"`
+	Files              map[string]*FileInfo    `json:"files"`
+	Packages           map[string]*PackageInfo `json:"packages"`
+	APIEndpoints       []*APIEndpoint          `json:"api_endpoints"`
+	BuildContext       *BuildContext           `json:"build_context,omitempty"`
+	Modules            []*ModuleInfo           `json:"modules,omitempty"`
+	Workspace          *WorkspaceInfo          `json:"workspace,omitempty"`
+	ModuleDependencies []*ModuleDependency     `json:"module_dependencies,omitempty"`
+	DependencyGraph    *DependencyGraph        `json:"dependency_graph"`
+	Config             *AnalysisConfig         `json:"config,omitempty"`
+	FileSet            *token.FileSet          `json:"-" yaml:"-"` // Positions of every parsed AST
+	Pinned             bool                    `json:"pinned"`     // Never evicted by retention policies
+	ScanDurationMs     int64                   `json:"scan_duration_ms"`
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
 }
 
 // FileInfo contains information about a Go source file
 type FileInfo struct {
-	Path         string           `json:"path"`
-	AbsolutePath string           `json:"absolute_path"`
-	PackageName  string           `json:"package_name"`
-	Content      string           `json:"content"`
-	AST          *ast.File        `json:"-"` // Excluded from JSON serialization
-	Imports      []string         `json:"imports"`
-	Functions    []*FunctionInfo  `json:"functions"`
-	Types        []*TypeInfo      `json:"types"`
-	Variables    []*VariableInfo  `json:"variables"`
-	Constants    []*ConstantInfo  `json:"constants"`
-	Interfaces   []*InterfaceInfo `json:"interfaces"`
-	Structs      []*StructInfo    `json:"structs"`
+	Path            string           `json:"path"`
+	AbsolutePath    string           `json:"absolute_path"`
+	PackageName     string           `json:"package_name"`
+	ImportPath      string           `json:"import_path"`
+	Content         string           `json:"content"`
+	ContentHash     string           `json:"content_hash"` // SHA-256 of Content
+	Size            int64            `json:"size"`
+	ModTime         time.Time        `json:"mod_time"`
+	AST             *ast.File        `json:"-" yaml:"-"`                 // Excluded from serialization
+	Generated       bool             `json:"generated"`                  // File carries a "Code generated ... DO NOT EDIT." header
+	BuildConstraint string           `json:"build_constraint,omitempty"` // Combined //go:build and file name constraint
+	Imports         []string         `json:"imports"`
+	ImportDetails   []*ImportInfo    `json:"import_details"`
+	Functions       []*FunctionInfo  `json:"functions"`
+	Types           []*TypeInfo      `json:"types"`
+	Variables       []*VariableInfo  `json:"variables"`
+	Constants       []*ConstantInfo  `json:"constants"`
+	Interfaces      []*InterfaceInfo `json:"interfaces"`
+	Structs         []*StructInfo    `json:"structs"`
 }
 
 // PackageInfo contains information about a Go package
 type PackageInfo struct {
-	Name  string   `json:"name"`
-	Path  string   `json:"path"`
-	Files []string `json:"files"`
+	Name       string   `json:"name"`
+	Path       string   `json:"path"`        // Directory relative to the project root
+	ImportPath string   `json:"import_path"` // Full import path within its module
+	Module     string   `json:"module,omitempty"`
+	Files      []string `json:"files"`
 }
 
 // DependencyGraph represents the dependency relationships between code elements
@@ -65,12 +84,24 @@ type Dependency struct {
 
 // AnalysisConfig contains configuration for project analysis
 type AnalysisConfig struct {
-	BlacklistFiles  []string `json:"blacklist_files,omitempty"`
-	BlacklistDirs   []string `json:"blacklist_dirs,omitempty"`
-	WhitelistFiles  []string `json:"whitelist_files,omitempty"`
-	WhitelistDirs   []string `json:"whitelist_dirs,omitempty"`
-	IncludeVendor   bool     `json:"include_vendor"`
-	IncludeTestFile bool     `json:"include_test_file"`
+	BlacklistFiles   []string      `json:"blacklist_files,omitempty"`
+	BlacklistDirs    []string      `json:"blacklist_dirs,omitempty"`
+	WhitelistFiles   []string      `json:"whitelist_files,omitempty"`
+	WhitelistDirs    []string      `json:"whitelist_dirs,omitempty"`
+	IncludeVendor    bool          `json:"include_vendor"`
+	IncludeTestFile  bool          `json:"include_test_file"`
+	ExcludeGenerated bool          `json:"exclude_generated"`
+	BuildContext     *BuildContext `json:"build_context,omitempty"`
+}
+
+// BuildContext selects the build configuration an analysis reflects.
+// Empty GOOS/GOARCH default to the platform the server runs on.
+type BuildContext struct {
+	GOOS           string   `json:"goos,omitempty"`
+	GOARCH         string   `json:"goarch,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	CgoEnabled     bool     `json:"cgo_enabled"`
+	AllConstraints bool     `json:"all_constraints"` // Analyze every file and only annotate constraints
 }
 
 // FilterConfig contains configuration for filtering nodes
@@ -83,6 +114,8 @@ type FilterConfig struct {
 	BlacklistDirs  []string `json:"blacklist_dirs,omitempty"`
 	MinComplexity  *int     `json:"min_complexity,omitempty"`
 	MaxComplexity  *int     `json:"max_complexity,omitempty"`
+	Generated      *bool    `json:"generated,omitempty"` // nil keeps both, true keeps only generated, false only handwritten
+	Query          string   `json:"query,omitempty"`     // Node query applied on top of the other filters
 }
 
 // FilterSuggestions contains available filter options for a project
@@ -100,7 +133,114 @@ type ProjectStatistics struct {
 	TotalPackages    int            `json:"total_packages"`
 	TotalNodes       int            `json:"total_nodes"`
 	TotalAPIs        int            `json:"total_apis"`
+	GeneratedFiles   int            `json:"generated_files"`
+	HandwrittenFiles int            `json:"handwritten_files"`
+	GeneratedNodes   int            `json:"generated_nodes"`
+	HandwrittenNodes int            `json:"handwritten_nodes"`
 	NodesByType      map[string]int `json:"nodes_by_type"`
 	FilesByExtension map[string]int `json:"files_by_extension"`
 	GeneratedAt      time.Time      `json:"generated_at"`
 }
+
+// ProjectSummary is a lightweight view of a stored analysis without file contents
+type ProjectSummary struct {
+	ID             string          `json:"id"`
+	Version        int             `json:"version"`
+	ProjectPath    string          `json:"project_path"`
+	FileCount      int             `json:"file_count"`
+	PackageCount   int             `json:"package_count"`
+	NodeCount      int             `json:"node_count"`
+	EndpointCount  int             `json:"endpoint_count"`
+	ScanDurationMs int64           `json:"scan_duration_ms"`
+	Config         *AnalysisConfig `json:"config,omitempty"`
+	Pinned         bool            `json:"pinned"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ProjectOverview is the default view of an analysis: its summary, packages and
+// endpoints, and per-file metadata without source contents or function bodies
+type ProjectOverview struct {
+	ProjectSummary
+	BuildContext *BuildContext           `json:"build_context,omitempty"`
+	Modules      []*ModuleInfo           `json:"modules,omitempty"`
+	Workspace    *WorkspaceInfo          `json:"workspace,omitempty"`
+	Packages     map[string]*PackageInfo `json:"packages"`
+	APIEndpoints []*APIEndpoint          `json:"api_endpoints"`
+	Files        map[string]*FileSummary `json:"files"`
+}
+
+// FileSummary describes a source file without its content
+type FileSummary struct {
+	Path            string `json:"path"`
+	PackageName     string `json:"package_name"`
+	ImportPath      string `json:"import_path"`
+	ContentHash     string `json:"content_hash"`
+	Size            int64  `json:"size"`
+	Generated       bool   `json:"generated"`
+	BuildConstraint string `json:"build_constraint,omitempty"`
+	Imports         int    `json:"imports"`
+	Functions       int    `json:"functions"`
+	Types           int    `json:"types"`
+	Structs         int    `json:"structs"`
+	Interfaces      int    `json:"interfaces"`
+}
+
+// StorageStats reports the memory held by stored analyses and the retention limits
+type StorageStats struct {
+	TotalProjects   int                   `json:"total_projects"`
+	TotalBytes      int64                 `json:"total_bytes"` // Estimated memory of the loaded projects
+	MaxProjects     int                   `json:"max_projects"`
+	MaxProjectBytes int64                 `json:"max_project_bytes"`
+	TTLSeconds      int64                 `json:"ttl_seconds"`
+	Projects        []*ProjectStorageInfo `json:"projects"`
+	GeneratedAt     time.Time             `json:"generated_at"`
+}
+
+// ProjectStorageInfo describes the storage footprint of a single project
+type ProjectStorageInfo struct {
+	ProjectID      string     `json:"project_id"`
+	ProjectPath    string     `json:"project_path"`
+	Bytes          int64      `json:"bytes"` // Estimated
+	Files          int        `json:"files"`
+	Nodes          int        `json:"nodes"`
+	Endpoints      int        `json:"endpoints"`
+	Pinned         bool       `json:"pinned"`
+	LastAccessedAt time.Time  `json:"last_accessed_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Versions       int        `json:"versions"` // Stored versions including the current one
+	Loaded         bool       `json:"loaded"`   // Held in memory rather than only on disk
+}
+
+// ProjectVersionInfo describes one stored version of a project
+type ProjectVersionInfo struct {
+	Version       int       `json:"version"`
+	ProjectPath   string    `json:"project_path"`
+	FileCount     int       `json:"file_count"`
+	EndpointCount int       `json:"endpoint_count"`
+	Current       bool      `json:"current"`
+	ScannedAt     time.Time `json:"scanned_at"`
+}
+
+// CodeNodeRef is a lightweight reference to a code node
+type CodeNodeRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	File string `json:"file"`
+}
+
+// RescanSummary describes what changed during an incremental re-analysis
+type RescanSummary struct {
+	ProjectID        string         `json:"project_id"`
+	AddedFiles       []string       `json:"added_files"`
+	ModifiedFiles    []string       `json:"modified_files"`
+	RemovedFiles     []string       `json:"removed_files"`
+	UnchangedFiles   int            `json:"unchanged_files"`
+	AddedNodes       []*CodeNodeRef `json:"added_nodes"`
+	RemovedNodes     []*CodeNodeRef `json:"removed_nodes"`
+	ModifiedNodes    []*CodeNodeRef `json:"modified_nodes"`
+	AddedEndpoints   int            `json:"added_endpoints"`
+	RemovedEndpoints int            `json:"removed_endpoints"`
+	DurationMs       int64          `json:"duration_ms"`
+}
@@ -0,0 +1,113 @@
+package entity
+
+import "time"
+
+// ProjectDiff lists what changed between two versions of a project
+type ProjectDiff struct {
+	ProjectID           string            `json:"project_id"`
+	FromVersion         int               `json:"from_version"`
+	ToVersion           int               `json:"to_version"`
+	AddedEndpoints      []*APIEndpoint    `json:"added_endpoints"`
+	RemovedEndpoints    []*APIEndpoint    `json:"removed_endpoints"`
+	ChangedEndpoints    []*EndpointChange `json:"changed_endpoints"`
+	ChangedFunctions    []*FunctionChange `json:"changed_functions"`
+	ChangedTypes        []*TypeChange     `json:"changed_types"`
+	AddedDependencies   []*Dependency     `json:"added_dependencies"`
+	RemovedDependencies []*Dependency     `json:"removed_dependencies"`
+	Summary             *DiffSummary      `json:"summary"`
+	GeneratedAt         time.Time         `json:"generated_at"`
+}
+
+// DiffSummary counts the entries of a ProjectDiff
+type DiffSummary struct {
+	AddedEndpoints      int `json:"added_endpoints"`
+	RemovedEndpoints    int `json:"removed_endpoints"`
+	ChangedEndpoints    int `json:"changed_endpoints"`
+	ChangedFunctions    int `json:"changed_functions"`
+	ChangedTypes        int `json:"changed_types"`
+	AddedDependencies   int `json:"added_dependencies"`
+	RemovedDependencies int `json:"removed_dependencies"`
+}
+
+// EndpointChange describes an endpoint present in both versions whose registration changed
+type EndpointChange struct {
+	ID      string       `json:"id"`
+	Method  string       `json:"method"`
+	Path    string       `json:"path"`
+	Changes []string     `json:"changes"` // Changed attributes: handler, middlewares, file
+	From    *APIEndpoint `json:"from"`
+	To      *APIEndpoint `json:"to"`
+}
+
+// FunctionChange describes a function or method whose signature changed
+type FunctionChange struct {
+	SymbolKey     string `json:"symbol_key"`
+	Name          string `json:"name"`
+	Receiver      string `json:"receiver,omitempty"`
+	File          string `json:"file"`
+	FromSignature string `json:"from_signature"`
+	ToSignature   string `json:"to_signature"`
+}
+
+// TypeChange describes a struct whose fields changed
+type TypeChange struct {
+	SymbolKey     string         `json:"symbol_key"`
+	Name          string         `json:"name"`
+	File          string         `json:"file"`
+	AddedFields   []*StructField `json:"added_fields"`
+	RemovedFields []*StructField `json:"removed_fields"`
+	ChangedFields []*FieldChange `json:"changed_fields"`
+}
+
+// FieldChange describes a struct field whose type, tag or embedding changed
+type FieldChange struct {
+	Name string       `json:"name"`
+	From *StructField `json:"from"`
+	To   *StructField `json:"to"`
+}
+
+// Severities of an API compatibility change
+const (
+	SeverityBreaking = "breaking" // Existing clients fail
+	SeverityWarning  = "warning"  // Existing clients may behave differently
+	SeverityInfo     = "info"     // Backward compatible
+)
+
+// Exit codes of a compatibility report, for failing CI pipelines
+const (
+	ExitCodeCompatible = 0 // No breaking or potentially breaking changes
+	ExitCodeBreaking   = 1 // At least one breaking change
+	ExitCodeWarning    = 2 // Potentially breaking changes only
+)
+
+// BreakingChangeReport classifies the API differences between two versions of a project
+type BreakingChangeReport struct {
+	ProjectID   string                 `json:"project_id"`
+	FromVersion int                    `json:"from_version"`
+	ToVersion   int                    `json:"to_version"`
+	Breaking    bool                   `json:"breaking"`
+	ExitCode    int                    `json:"exit_code"`
+	Summary     *BreakingChangeSummary `json:"summary"`
+	Changes     []*APIChange           `json:"changes"`
+	GeneratedAt time.Time              `json:"generated_at"`
+}
+
+// BreakingChangeSummary counts the changes of a report by severity
+type BreakingChangeSummary struct {
+	Breaking int `json:"breaking"`
+	Warning  int `json:"warning"`
+	Info     int `json:"info"`
+}
+
+// APIChange is a single classified difference of an endpoint contract
+type APIChange struct {
+	Severity string `json:"severity"`
+	Kind     string `json:"kind"` // endpoint_removed, method_changed, field_removed, ...
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Location string `json:"location,omitempty"` // path, query, request or response
+	Field    string `json:"field,omitempty"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Message  string `json:"message"`
+}
@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// Scan job states
+const (
+	ScanJobQueued    = "queued"
+	ScanJobRunning   = "running"
+	ScanJobCompleted = "completed"
+	ScanJobFailed    = "failed"
+	ScanJobCancelled = "cancelled"
+)
+
+// Phases of a running scan
+const (
+	ScanPhaseWalking   = "walking"
+	ScanPhaseParsing   = "parsing"
+	ScanPhaseDiscovery = "discovery"
+	ScanPhaseGraph     = "graph"
+)
+
+// ScanJob tracks an asynchronous project analysis
+type ScanJob struct {
+	ID             string          `json:"id"`
+	ProjectPath    string          `json:"project_path"`
+	ProjectID      string          `json:"project_id,omitempty"` // Project to version, or set once a new analysis is stored
+	Status         string          `json:"status"`
+	Phase          string          `json:"phase,omitempty"`
+	FilesProcessed int             `json:"files_processed"`
+	FilesTotal     int             `json:"files_total"`
+	Errors         []string        `json:"errors"`
+	Config         *AnalysisConfig `json:"config,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job reached a terminal state
+func (j *ScanJob) Finished() bool {
+	return j.Status == ScanJobCompleted || j.Status == ScanJobFailed || j.Status == ScanJobCancelled
+}
+
+// Scan event types
+const (
+	ScanEventFileParsed         = "file_parsed"
+	ScanEventEndpointDiscovered = "endpoint_discovered"
+	ScanEventDiagnostic         = "diagnostic"
+	ScanEventPhaseChanged       = "phase_changed"
+	ScanEventCompleted          = "completed"
+)
+
+// ScanEvent is a progress notification published while a scan job runs
+type ScanEvent struct {
+	ID    int64       `json:"id"` // Increasing per job, usable as Last-Event-ID
+	JobID string      `json:"job_id"`
+	Type  string      `json:"type"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data,omitempty"`
+}
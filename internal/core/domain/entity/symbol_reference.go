@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// SymbolDefinition describes the declaration an identifier resolves to
+type SymbolDefinition struct {
+	Name     string       `json:"name"`
+	Kind     string       `json:"kind"` // function, method, type, variable, field, constant, package, builtin, label
+	Type     string       `json:"type,omitempty"`
+	Package  string       `json:"package,omitempty"`
+	File     string       `json:"file,omitempty"`
+	Position *Position    `json:"position,omitempty"`
+	End      *Position    `json:"end,omitempty"`
+	Node     *CodeNodeRef `json:"node,omitempty"` // Declaration node, or the node enclosing a local or field declaration
+	External bool         `json:"external"`       // Declared outside the project, so no source location is known
+}
+
+// SymbolReference is a use of a declaration somewhere in the project
+type SymbolReference struct {
+	File     string    `json:"file"`
+	Kind     string    `json:"kind"`               // call, type, embedding, field, assignment, value
+	Function string    `json:"function,omitempty"` // Enclosing function or method
+	NodeID   string    `json:"node_id,omitempty"`  // Node whose declaration contains the use
+	Position *Position `json:"position"`
+	End      *Position `json:"end"`
+	Text     string    `json:"text"` // Source line of the use
+}
+
+// ReferenceResult lists every use of the declaration of a code node
+type ReferenceResult struct {
+	ProjectID   string             `json:"project_id"`
+	NodeID      string             `json:"node_id"`
+	Symbol      *SymbolDefinition  `json:"symbol"`
+	References  []*SymbolReference `json:"references"`
+	Total       int                `json:"total"`
+	Kinds       map[string]int     `json:"kinds"` // Number of references of each kind
+	TypeErrors  []string           `json:"type_errors,omitempty"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
@@ -9,16 +9,45 @@ type AnalysisRepository interface {
 	GetProjectAnalysis(projectID string) (*entity.ProjectAnalysis, error)
 	DeleteProjectAnalysis(projectID string) error
 	ListProjects() ([]*entity.ProjectAnalysis, error)
+	ListProjectSummaries() ([]*entity.ProjectSummary, error)
+	PinProject(projectID string, pinned bool) error
+	// ReplaceProject stores an analysis with its nodes and endpoints in place of the
+	// current ones of the project in one step, failing without changes if the result
+	// would exceed the storage limits. With archive the current analysis is kept as
+	// a past version and analysis stored as the next one.
+	ReplaceProject(analysis *entity.ProjectAnalysis, nodes []*entity.CodeNode, archive bool) error
+	GetStorageStats() (*entity.StorageStats, error)
+
+	// Project Version Methods
+	StoreProjectVersion(analysis *entity.ProjectAnalysis) error
+	GetProjectVersion(projectID string, version int) (*entity.ProjectAnalysis, error)
+	ListProjectVersions(projectID string) ([]*entity.ProjectVersionInfo, error)
 
 	// Code Node Methods
 	StoreCodeNode(projectID string, node *entity.CodeNode) error
 	GetCodeNode(projectID, nodeID string) (*entity.CodeNode, error)
+	GetCodeNodesBySymbol(projectID, symbolKey string) ([]*entity.CodeNode, error)
 	GetAllNodes(projectID string, page, limit int, nodeType string) ([]*entity.CodeNode, int64, error)
 	SearchNodes(projectID, query string, page, limit int) ([]*entity.CodeNode, int64, error)
+	DeleteCodeNode(projectID, nodeID string) error
 
 	// API Endpoint Methods
 	StoreAPIEndpoint(projectID string, endpoint *entity.APIEndpoint) error
 	GetAPIEndpoint(projectID, apiID string) (*entity.APIEndpoint, error)
 	GetAPIEndpoints(projectID string) ([]*entity.APIEndpoint, error)
 	GetAPINodes(projectID, apiID string) ([]*entity.CodeNode, error)
+	DeleteAPIEndpoint(projectID, apiID string) error
+
+	// Saved Query Methods
+	StoreSavedQuery(projectID string, query *entity.SavedQuery) error
+	GetSavedQuery(projectID, queryID string) (*entity.SavedQuery, error)
+	ListSavedQueries(projectID string) ([]*entity.SavedQuery, error)
+	DeleteSavedQuery(projectID, queryID string) error
+
+	// FlushProject makes the stored state of a project durable. Scans call it
+	// once they have stored everything.
+	FlushProject(projectID string) error
+
+	// Close releases the storage and writes any pending changes
+	Close() error
 }
@@ -7,8 +7,6 @@ import (
 
 	"goapianalyzer/internal/core/domain/entity"
 	"goapianalyzer/internal/infrastructure/logger"
-
-	"github.com/google/uuid"
 )
 
 type AnalyzerService struct {
@@ -31,6 +29,7 @@ type RouterGroup struct {
 	LineNumber int
 	File       string
 	Children   []*RouterGroup
+	Routes     []*RouteCall // Routes registered directly on the group
 }
 
 // RouteCall represents a route method call with context
@@ -57,11 +56,13 @@ type RouterContext struct {
 func (s *AnalyzerService) DiscoverAPIEndpoints(analysis *entity.ProjectAnalysis) error {
 	s.logger.Info("Starting enhanced API endpoint discovery")
 
-	// Use a map to track unique endpoints by method and path
+	// Use a map to track unique endpoints by their stable ID (method and normalized path)
 	endpointMap := make(map[string]*entity.APIEndpoint)
 
-	// Analyze each file for routing patterns
-	for filePath, fileInfo := range analysis.Files {
+	// Analyze each file for routing patterns, in path order so the first of
+	// colliding routes is the same on every scan
+	for _, filePath := range sortedFilePaths(analysis.Files) {
+		fileInfo := analysis.Files[filePath]
 		if !s.isRouterFile(fileInfo.Content) {
 			continue
 		}
@@ -69,7 +70,7 @@ func (s *AnalyzerService) DiscoverAPIEndpoints(analysis *entity.ProjectAnalysis)
 		context := s.analyzeRouterContext(fileInfo.Content, filePath)
 		fileEndpoints := s.extractEndpointsFromContext(context)
 		for _, endpoint := range fileEndpoints {
-			key := endpoint.Method + ":" + endpoint.Path
+			key := endpoint.ID
 			if _, exists := endpointMap[key]; !exists {
 				endpointMap[key] = endpoint
 			} else {
@@ -86,7 +87,7 @@ func (s *AnalyzerService) DiscoverAPIEndpoints(analysis *entity.ProjectAnalysis)
 	// Cross-file analysis for router setup patterns
 	crossFileEndpoints := s.analyzeCrossFileRouting(analysis)
 	for _, endpoint := range crossFileEndpoints {
-		key := endpoint.Method + ":" + endpoint.Path
+		key := endpoint.ID
 		if _, exists := endpointMap[key]; !exists {
 			endpointMap[key] = endpoint
 		} else {
@@ -104,17 +105,69 @@ func (s *AnalyzerService) DiscoverAPIEndpoints(analysis *entity.ProjectAnalysis)
 		endpoints = append(endpoints, endpoint)
 	}
 
-	// Sort endpoints by path
+	// Sort endpoints by path, then method
 	sort.Slice(endpoints, func(i, j int) bool {
-		return endpoints[i].Path < endpoints[j].Path
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
 	})
 
 	analysis.APIEndpoints = endpoints
+	s.inferEndpointContracts(analysis)
 
 	s.logger.WithField("endpoints_count", len(endpoints)).Info("Enhanced API endpoint discovery completed")
 	return nil
 }
 
+// RouterGroups recovers the router group hierarchy of the router files of an
+// analysis, with the routes registered on each group. Routes registered on an
+// engine or an unknown variable go to a root group named after the variable.
+// Root groups are sorted by file and line.
+func (s *AnalyzerService) RouterGroups(analysis *entity.ProjectAnalysis) []*RouterGroup {
+	var roots []*RouterGroup
+	for _, filePath := range sortedFilePaths(analysis.Files) {
+		content := analysis.Files[filePath].Content
+		if !s.isRouterFile(content) {
+			continue
+		}
+
+		ctx := s.analyzeRouterContext(content, filePath)
+		for _, route := range ctx.Routes {
+			group, exists := ctx.Groups[route.VarName]
+			if !exists {
+				group = &RouterGroup{
+					VarName:    route.VarName,
+					Path:       "/",
+					FullPath:   s.normalizePath(ctx.Variables[route.VarName]),
+					LineNumber: route.LineNumber,
+					File:       filePath,
+					Children:   make([]*RouterGroup, 0),
+				}
+				ctx.Groups[route.VarName] = group
+			}
+			group.Routes = append(group.Routes, route)
+		}
+
+		for _, group := range ctx.Groups {
+			if group.Parent == nil {
+				roots = append(roots, group)
+			}
+			sort.Slice(group.Children, func(i, j int) bool {
+				return group.Children[i].LineNumber < group.Children[j].LineNumber
+			})
+		}
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		if roots[i].File != roots[j].File {
+			return roots[i].File < roots[j].File
+		}
+		return roots[i].LineNumber < roots[j].LineNumber
+	})
+	return roots
+}
+
 // analyzeRouterContext performs comprehensive analysis of a router file
 func (s *AnalyzerService) analyzeRouterContext(content, filePath string) *RouterContext {
 	ctx := &RouterContext{
@@ -385,10 +438,12 @@ func (s *AnalyzerService) extractEndpointsFromContext(ctx *RouterContext) []*ent
 
 	for _, route := range ctx.Routes {
 		endpoint := &entity.APIEndpoint{
-			ID:     uuid.New().String(),
-			Method: route.Method,
-			Path:   route.FullPath,
-			File:   route.File,
+			ID:          entity.EndpointID(route.Method, route.FullPath),
+			Method:      route.Method,
+			Path:        route.FullPath,
+			File:        route.File,
+			Handler:     strings.TrimSpace(route.Handler),
+			Middlewares: route.Middlewares,
 		}
 
 		endpoints = append(endpoints, endpoint)
@@ -402,9 +457,9 @@ func (s *AnalyzerService) analyzeCrossFileRouting(analysis *entity.ProjectAnalys
 	var endpoints []*entity.APIEndpoint
 
 	// Look for main.go or router setup files
-	for filePath, fileInfo := range analysis.Files {
+	for _, filePath := range sortedFilePaths(analysis.Files) {
 		if s.isMainOrSetupFile(filePath) {
-			setupEndpoints := s.analyzeRouterSetupFile(fileInfo.Content, filePath, analysis)
+			setupEndpoints := s.analyzeRouterSetupFile(analysis.Files[filePath].Content, filePath, analysis)
 			endpoints = append(endpoints, setupEndpoints...)
 		}
 	}
@@ -412,6 +467,16 @@ func (s *AnalyzerService) analyzeCrossFileRouting(analysis *entity.ProjectAnalys
 	return endpoints
 }
 
+// sortedFilePaths returns the paths of the analyzed files in order
+func sortedFilePaths(files map[string]*entity.FileInfo) []string {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 // isMainOrSetupFile checks if the file is likely a main or router setup file
 func (s *AnalyzerService) isMainOrSetupFile(filePath string) bool {
 	fileName := strings.ToLower(filePath)
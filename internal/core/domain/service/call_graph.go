@@ -0,0 +1,217 @@
+package service
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// Kinds of call edges. A call through an interface links to every method of the
+// project implementing it; calls made inside function literals are closure calls,
+// and calls started by a go statement goroutine calls.
+const (
+	CallStatic    = "static"
+	CallInterface = "interface"
+	CallClosure   = "closure"
+	CallGoroutine = "goroutine"
+)
+
+// CallEdge is a call from one function to another at one call site
+type CallEdge struct {
+	Caller   *types.Func
+	Callee   *types.Func
+	Kind     string
+	File     string
+	Position *entity.Position
+}
+
+// CallGraph links the functions and methods declared in a set of files
+type CallGraph struct {
+	Callees map[*types.Func][]*CallEdge
+	Callers map[*types.Func][]*CallEdge
+
+	info            *types.Info
+	namedTypes      []*types.Named
+	implementations map[*types.Func][]*types.Func
+}
+
+// BuildCallGraph resolves the calls of every function declared in files
+func BuildCallGraph(fset *token.FileSet, files map[string]*ast.File, info *types.Info) *CallGraph {
+	graph := &CallGraph{
+		Callees:         make(map[*types.Func][]*CallEdge),
+		Callers:         make(map[*types.Func][]*CallEdge),
+		info:            info,
+		implementations: make(map[*types.Func][]*types.Func),
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	// Concrete types that may implement the interfaces called
+	for _, filePath := range paths {
+		for _, decl := range files[filePath].Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeName, ok := info.Defs[spec.(*ast.TypeSpec).Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				named, ok := typeName.Type().(*types.Named)
+				if ok && !types.IsInterface(named) && named.TypeParams().Len() == 0 {
+					graph.namedTypes = append(graph.namedTypes, named)
+				}
+			}
+		}
+	}
+
+	for _, filePath := range paths {
+		for _, decl := range files[filePath].Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			caller, ok := info.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			walker := &callWalker{graph: graph, fset: fset, file: filePath, caller: caller}
+			walker.walk(funcDecl.Body, CallStatic)
+		}
+	}
+
+	return graph
+}
+
+// callWalker records the calls of one function body, knowing whether the code
+// visited runs in a closure or a goroutine
+type callWalker struct {
+	graph  *CallGraph
+	fset   *token.FileSet
+	file   string
+	caller *types.Func
+}
+
+func (w *callWalker) walk(root ast.Node, kind string) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			if kind == CallStatic {
+				w.walk(node.Body, CallClosure)
+			} else {
+				w.walk(node.Body, kind)
+			}
+			return false
+		case *ast.GoStmt:
+			for _, arg := range node.Call.Args {
+				w.walk(arg, kind)
+			}
+			if funcLit, ok := ast.Unparen(node.Call.Fun).(*ast.FuncLit); ok {
+				w.walk(funcLit.Body, CallGoroutine)
+				return false
+			}
+			w.call(node.Call, CallGoroutine)
+			w.walk(node.Call.Fun, kind)
+			return false
+		case *ast.CallExpr:
+			w.call(node, kind)
+		}
+		return true
+	})
+}
+
+func (w *callWalker) call(call *ast.CallExpr, kind string) {
+	fun := ast.Unparen(call.Fun)
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+
+	var ident *ast.Ident
+	callees := make([]*types.Func, 0, 1)
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		ident = fun
+		if callee, ok := w.graph.info.Uses[fun].(*types.Func); ok {
+			callees = append(callees, callee.Origin())
+		}
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+		callee, ok := w.graph.info.Uses[fun.Sel].(*types.Func)
+		if !ok {
+			return
+		}
+		if selection := w.graph.info.Selections[fun]; selection != nil && selection.Kind() == types.MethodVal {
+			if iface, isInterface := selection.Recv().Underlying().(*types.Interface); isInterface {
+				callees = append(callees, w.graph.implementationsOf(callee, iface)...)
+				if kind == CallStatic {
+					kind = CallInterface
+				}
+				break
+			}
+		}
+		callees = append(callees, callee.Origin())
+	default:
+		return
+	}
+
+	position := w.fset.Position(ident.Pos())
+	for _, callee := range callees {
+		edge := &CallEdge{
+			Caller:   w.caller,
+			Callee:   callee,
+			Kind:     kind,
+			File:     w.file,
+			Position: &entity.Position{Line: position.Line, Column: position.Column, Offset: position.Offset},
+		}
+		w.graph.Callees[w.caller] = append(w.graph.Callees[w.caller], edge)
+		w.graph.Callers[callee] = append(w.graph.Callers[callee], edge)
+	}
+}
+
+// implementationsOf returns the methods of the project's concrete types that a
+// call of an interface method may dispatch to
+func (g *CallGraph) implementationsOf(method *types.Func, iface *types.Interface) []*types.Func {
+	if methods, exists := g.implementations[method]; exists {
+		return methods
+	}
+
+	methods := make([]*types.Func, 0)
+	seen := make(map[*types.Func]bool)
+	for _, named := range g.namedTypes {
+		var receiver types.Type = named
+		if !types.Implements(receiver, iface) {
+			receiver = types.NewPointer(named)
+			if !types.Implements(receiver, iface) {
+				continue
+			}
+		}
+		obj, _, _ := types.LookupFieldOrMethod(receiver, true, method.Pkg(), method.Name())
+		// Types embedding the same implementation share its method
+		if implementation, ok := obj.(*types.Func); ok && !seen[implementation.Origin()] {
+			seen[implementation.Origin()] = true
+			methods = append(methods, implementation.Origin())
+		}
+	}
+
+	g.implementations[method] = methods
+	return methods
+}
+
+// FunctionName writes a function as "Name" and a method as "(*Receiver).Name"
+func FunctionName(function *types.Func) string {
+	if receiver := ObjectReceiver(function); receiver != "" {
+		return "(" + receiver + ")." + function.Name()
+	}
+	return function.Name()
+}
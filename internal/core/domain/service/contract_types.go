@@ -0,0 +1,143 @@
+package service
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"sort"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// ContractType is a type declared in the project that a request or response type
+// is, or refers to through its fields
+type ContractType struct {
+	Name       string
+	Package    string // Package name; unqualified names in Struct and Underlying belong to it
+	Doc        string
+	Struct     *entity.StructInfo // Nil for types other than structs
+	Underlying string             // Type expression of other types; "interface" for interfaces
+}
+
+// ContractTypes resolves request and response types to their declarations along
+// with every project type reachable from their fields, the given types first.
+// Types declared outside the project, such as time.Time, are not included.
+func (s *AnalyzerService) ContractTypes(analysis *entity.ProjectAnalysis, typeNames []string) []*ContractType {
+	index := contractTypeIndex(analysis)
+
+	var result []*ContractType
+	seen := make(map[*ContractType]bool)
+	var queue []*ContractType
+	enqueue := func(contractType *ContractType) {
+		if contractType != nil && !seen[contractType] {
+			seen[contractType] = true
+			queue = append(queue, contractType)
+		}
+	}
+
+	for _, typeName := range typeNames {
+		for _, ref := range typeReferences(typeName) {
+			enqueue(lookupContractType(index, ref[0], ref[1]))
+		}
+	}
+	for len(queue) > 0 {
+		contractType := queue[0]
+		queue = queue[1:]
+		result = append(result, contractType)
+
+		var expressions []string
+		if contractType.Struct != nil {
+			for _, field := range contractType.Struct.Fields {
+				expressions = append(expressions, field.Type)
+			}
+		} else {
+			expressions = append(expressions, contractType.Underlying)
+		}
+		for _, expression := range expressions {
+			for _, ref := range typeReferences(expression) {
+				qualifier := ref[0]
+				if qualifier == "" {
+					qualifier = contractType.Package
+				}
+				enqueue(lookupContractType(index, qualifier, ref[1]))
+			}
+		}
+	}
+	return result
+}
+
+// contractTypeIndex maps type names to their declarations, first by file path
+func contractTypeIndex(analysis *entity.ProjectAnalysis) map[string][]*ContractType {
+	paths := make([]string, 0, len(analysis.Files))
+	for path := range analysis.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	index := make(map[string][]*ContractType)
+	for _, path := range paths {
+		fileInfo := analysis.Files[path]
+		structs := make(map[string]*entity.StructInfo, len(fileInfo.Structs))
+		for _, structInfo := range fileInfo.Structs {
+			structs[structInfo.Name] = structInfo
+		}
+		for _, typeInfo := range fileInfo.Types {
+			contractType := &ContractType{Name: typeInfo.Name, Package: fileInfo.PackageName, Doc: typeInfo.Doc}
+			if typeInfo.Type == "struct" {
+				contractType.Struct = structs[typeInfo.Name]
+				if contractType.Struct == nil {
+					continue
+				}
+			} else {
+				contractType.Underlying = typeInfo.Type
+			}
+			index[typeInfo.Name] = append(index[typeInfo.Name], contractType)
+		}
+	}
+	return index
+}
+
+// lookupContractType finds a type by name, within a package when qualified
+func lookupContractType(index map[string][]*ContractType, qualifier, name string) *ContractType {
+	for _, candidate := range index[name] {
+		if qualifier == "" || candidate.Package == qualifier {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// typeReferences lists the named types of a type expression as qualifier and
+// name pairs, leaving out predeclared types
+func typeReferences(expression string) [][2]string {
+	expr, err := parser.ParseExpr(strings.TrimSpace(expression))
+	if err != nil {
+		return nil
+	}
+
+	var refs [][2]string
+	var collect func(node ast.Node)
+	collect = func(node ast.Node) {
+		ast.Inspect(node, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.SelectorExpr:
+				if qualifier, ok := n.X.(*ast.Ident); ok {
+					refs = append(refs, [2]string{qualifier.Name, n.Sel.Name})
+				}
+				return false
+			case *ast.Ident:
+				if types.Universe.Lookup(n.Name) == nil {
+					refs = append(refs, [2]string{"", n.Name})
+				}
+			case *ast.Field:
+				// Names of fields and parameters are not types
+				collect(n.Type)
+				return false
+			}
+			return true
+		})
+	}
+	collect(expr)
+	return refs
+}
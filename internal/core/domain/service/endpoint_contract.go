@@ -0,0 +1,356 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+const (
+	maxContractDepth = 4 // How deep nested structs are expanded into contract fields
+	maxHelperDepth   = 3 // How deep query reads are followed into helpers called with the request context
+)
+
+var (
+	bindBodyPattern  = regexp.MustCompile(`\.(?:ShouldBind|Bind)(?:JSON|XML|YAML|TOML|BodyWithJSON)?\(\s*&?(\w+)`)
+	bindQueryPattern = regexp.MustCompile(`\.(?:ShouldBindQuery|BindQuery)\(\s*&?(\w+)`)
+	queryPattern     = regexp.MustCompile(`\.(?:Query|DefaultQuery|GetQuery|QueryArray|GetQueryArray|QueryMap)\(\s*"([^"]+)"`)
+	queryVarPattern  = regexp.MustCompile(`\.(?:Query|DefaultQuery|GetQuery|QueryArray|GetQueryArray|QueryMap)\(\s*(\w+)\s*[,)]`)
+	stringPattern    = regexp.MustCompile(`"([^"]+)"`)
+	responsePattern  = regexp.MustCompile(`\.(?:JSON|IndentedJSON|PureJSON|SecureJSON|AsciiJSON|XML|YAML)\(\s*[^,]+,\s*&?([\w.\[\]*]+)(\s*\{)?`)
+	identPattern     = regexp.MustCompile(`^\w+$`)
+
+	varDeclPattern    = `var\s+%s\s+\*?([\w.\[\]]+)`
+	varLiteralPattern = `%s\s*:?=\s*&?([\w.\[\]]+)\s*\{`
+	varNewPattern     = `%s\s*:?=\s*new\(\s*([\w.]+)\s*\)`
+	rangeNamesPattern = `for\s+(?:\w+\s*,\s*)?%s\s*:=\s*range\s+\[\]string\s*\{([^}]*)\}`
+)
+
+// ContractField is a field of a request, response or query type as it appears on the wire
+type ContractField struct {
+	Name     string // Wire name from the json or form tag; nested fields are dotted
+	GoName   string // Go field path, dotted like Name
+	Type     string
+	Required bool // binding:"required"
+}
+
+// structRef is a struct declaration together with its package
+type structRef struct {
+	info        *entity.StructInfo
+	packageName string
+}
+
+// inferEndpointContracts resolves the handler of every endpoint and records the
+// parameters it reads and the types it binds and writes
+func (s *AnalyzerService) inferEndpointContracts(analysis *entity.ProjectAnalysis) {
+	handlers := s.handlerIndex(analysis)
+	structs := structIndex(analysis)
+
+	for _, endpoint := range analysis.APIEndpoints {
+		endpoint.PathParams = pathParams(endpoint.Path)
+
+		handler := handlers[handlerName(endpoint.Handler)]
+		if handler == nil {
+			continue
+		}
+		endpoint.HandlerSymbol = handler.symbolKey
+		body := handler.info.Body
+
+		query := make(map[string]bool)
+		queryReads(handlers, structs, handler.info, query, 0, map[*entity.FunctionInfo]bool{})
+		endpoint.QueryParams = make([]*entity.EndpointParam, 0, len(query))
+		for name, required := range query {
+			endpoint.QueryParams = append(endpoint.QueryParams, &entity.EndpointParam{Name: name, Required: required})
+		}
+		sort.Slice(endpoint.QueryParams, func(i, j int) bool {
+			return endpoint.QueryParams[i].Name < endpoint.QueryParams[j].Name
+		})
+
+		if match := bindBodyPattern.FindStringSubmatch(body); match != nil {
+			endpoint.RequestType = variableType(body, match[1])
+		}
+
+		responses := make(map[string]bool)
+		for _, match := range responsePattern.FindAllStringSubmatch(body, -1) {
+			typeName := match[1]
+			if match[2] == "" {
+				typeName = variableType(body, match[1])
+			}
+			if typeName != "" && typeName != "nil" {
+				responses[typeName] = true
+			}
+		}
+		endpoint.ResponseTypes = make([]string, 0, len(responses))
+		for typeName := range responses {
+			endpoint.ResponseTypes = append(endpoint.ResponseTypes, typeName)
+		}
+		sort.Strings(endpoint.ResponseTypes)
+	}
+}
+
+// queryReads collects the query parameters a function reads, following calls that
+// pass its request context on to helpers such as a shared pagination parser
+func queryReads(
+	handlers map[string]*handlerRef,
+	structs map[string][]structRef,
+	funcInfo *entity.FunctionInfo,
+	query map[string]bool,
+	depth int,
+	visited map[*entity.FunctionInfo]bool,
+) {
+	if visited[funcInfo] {
+		return
+	}
+	visited[funcInfo] = true
+	body := funcInfo.Body
+
+	var names []string
+	for _, match := range queryPattern.FindAllStringSubmatch(body, -1) {
+		names = append(names, match[1])
+	}
+	for _, match := range queryVarPattern.FindAllStringSubmatch(body, -1) {
+		names = append(names, rangedNames(body, match[1])...)
+	}
+	for _, name := range names {
+		if _, exists := query[name]; !exists {
+			query[name] = false
+		}
+	}
+	if match := bindQueryPattern.FindStringSubmatch(body); match != nil {
+		if typeName := variableType(body, match[1]); typeName != "" {
+			for _, field := range contractFields(structs, typeName, "form", "", "", 0, map[string]bool{}) {
+				query[field.Name] = query[field.Name] || field.Required
+			}
+		}
+	}
+
+	if depth >= maxHelperDepth {
+		return
+	}
+	contexts := make(map[string]bool)
+	for _, param := range funcInfo.Parameters {
+		if param.Name != "" && isRequestType(param.Type) {
+			contexts[param.Name] = true
+		}
+	}
+	for _, call := range funcInfo.CallsTo {
+		passesContext := false
+		for _, argument := range call.Arguments {
+			passesContext = passesContext || contexts[argument]
+		}
+		if !passesContext {
+			continue
+		}
+		if helper := handlers[handlerName(call.Name)]; helper != nil && takesRequest(helper.info) {
+			queryReads(handlers, structs, helper.info, query, depth+1, visited)
+		}
+	}
+}
+
+// ContractFields expands a request or response type of an analysis into its wire
+// fields, following nested structs declared in the project. tagKey selects the
+// tag naming the fields, such as json or form.
+func (s *AnalyzerService) ContractFields(analysis *entity.ProjectAnalysis, typeName, tagKey string) []*ContractField {
+	return contractFields(structIndex(analysis), typeName, tagKey, "", "", 0, map[string]bool{})
+}
+
+type handlerRef struct {
+	info      *entity.FunctionInfo
+	symbolKey string
+}
+
+// handlerIndex maps function names to the declaration most likely to be an HTTP
+// handler: one taking a request context or response writer, first by file path
+func (s *AnalyzerService) handlerIndex(analysis *entity.ProjectAnalysis) map[string]*handlerRef {
+	paths := make([]string, 0, len(analysis.Files))
+	for path := range analysis.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	handlers := make(map[string]*handlerRef)
+	for _, path := range paths {
+		fileInfo := analysis.Files[path]
+		packagePath := fileInfo.ImportPath
+		if packagePath == "" {
+			packagePath = fileInfo.PackageName
+		}
+
+		for _, funcInfo := range fileInfo.Functions {
+			existing := handlers[funcInfo.Name]
+			if existing != nil && (isHandlerFunc(existing.info) || !isHandlerFunc(funcInfo)) {
+				continue
+			}
+			handlers[funcInfo.Name] = &handlerRef{
+				info:      funcInfo,
+				symbolKey: entity.SymbolKey(packagePath, funcInfo.Receiver, funcInfo.Name),
+			}
+		}
+	}
+
+	return handlers
+}
+
+func isHandlerFunc(funcInfo *entity.FunctionInfo) bool {
+	for _, param := range funcInfo.Parameters {
+		if strings.HasSuffix(param.Type, "Context") || strings.HasSuffix(param.Type, "ResponseWriter") {
+			return true
+		}
+	}
+	return false
+}
+
+func takesRequest(funcInfo *entity.FunctionInfo) bool {
+	for _, param := range funcInfo.Parameters {
+		if isRequestType(param.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequestType reports whether a parameter carries the request of a handler, as
+// a framework context does; a context.Context only carries its cancellation
+func isRequestType(typeName string) bool {
+	return strings.HasSuffix(typeName, "Context") && typeName != "context.Context"
+}
+
+// handlerName extracts the function name from a registered handler such as h.GetUser
+func handlerName(handler string) string {
+	handler = strings.TrimSpace(handler)
+	if i := strings.LastIndex(handler, "."); i >= 0 {
+		handler = handler[i+1:]
+	}
+	if !identPattern.MatchString(handler) {
+		return ""
+	}
+	return handler
+}
+
+// pathParams lists the named segments of a route path
+func pathParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case strings.HasPrefix(segment, ":"), strings.HasPrefix(segment, "*"):
+			params = append(params, segment[1:])
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			params = append(params, strings.TrimSuffix(strings.Trim(segment, "{}"), "..."))
+		}
+	}
+	return params
+}
+
+// variableType finds the declared type of a local variable in a function body
+func variableType(body, name string) string {
+	for _, pattern := range []string{varDeclPattern, varLiteralPattern, varNewPattern} {
+		re := regexp.MustCompile(fmt.Sprintf(pattern, `\b`+regexp.QuoteMeta(name)))
+		if match := re.FindStringSubmatch(body); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// rangedNames lists the names a loop variable takes when a function ranges over a
+// string slice literal, as in for _, name := range []string{"from", "to"}
+func rangedNames(body, name string) []string {
+	re := regexp.MustCompile(fmt.Sprintf(rangeNamesPattern, `\b`+regexp.QuoteMeta(name)))
+	match := re.FindStringSubmatch(body)
+	if match == nil {
+		return nil
+	}
+	var names []string
+	for _, literal := range stringPattern.FindAllStringSubmatch(match[1], -1) {
+		names = append(names, literal[1])
+	}
+	return names
+}
+
+func structIndex(analysis *entity.ProjectAnalysis) map[string][]structRef {
+	paths := make([]string, 0, len(analysis.Files))
+	for path := range analysis.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	index := make(map[string][]structRef)
+	for _, path := range paths {
+		fileInfo := analysis.Files[path]
+		for _, structInfo := range fileInfo.Structs {
+			index[structInfo.Name] = append(index[structInfo.Name], structRef{info: structInfo, packageName: fileInfo.PackageName})
+		}
+	}
+	return index
+}
+
+// lookupStruct resolves a type expression such as *dto.User or []User to a struct declaration
+func lookupStruct(structs map[string][]structRef, typeName string) *entity.StructInfo {
+	typeName = strings.TrimLeft(typeName, "*[]")
+	qualifier := ""
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		qualifier, typeName = typeName[:i], typeName[i+1:]
+	}
+
+	// Qualified names only match structs of that package, so time.Time never resolves to a local Time
+	for _, candidate := range structs[typeName] {
+		if qualifier == "" || candidate.packageName == qualifier {
+			return candidate.info
+		}
+	}
+	return nil
+}
+
+func contractFields(
+	structs map[string][]structRef,
+	typeName, tagKey, namePrefix, goPrefix string,
+	depth int,
+	visited map[string]bool,
+) []*ContractField {
+	structInfo := lookupStruct(structs, typeName)
+	if structInfo == nil || depth > maxContractDepth || visited[structInfo.Name] {
+		return nil
+	}
+	visited[structInfo.Name] = true
+	defer delete(visited, structInfo.Name)
+
+	var fields []*ContractField
+	for _, field := range structInfo.Fields {
+		tag := reflect.StructTag(strings.Trim(field.Tag, "`"))
+		wireName := strings.Split(tag.Get(tagKey), ",")[0]
+		if wireName == "-" {
+			continue
+		}
+
+		goName := field.Name
+		if goName == "" {
+			goName = strings.TrimLeft(field.Type, "*")
+			if i := strings.LastIndex(goName, "."); i >= 0 {
+				goName = goName[i+1:]
+			}
+		}
+		if field.Embedded && wireName == "" {
+			// Fields of untagged embedded structs are promoted to the outer object
+			fields = append(fields, contractFields(structs, field.Type, tagKey, namePrefix, goPrefix, depth+1, visited)...)
+			continue
+		}
+		if wireName == "" {
+			wireName = goName
+		}
+
+		contractField := &ContractField{
+			Name:     namePrefix + wireName,
+			GoName:   goPrefix + goName,
+			Type:     field.Type,
+			Required: strings.Contains(tag.Get("binding"), "required") || strings.Contains(tag.Get("validate"), "required"),
+		}
+		fields = append(fields, contractField)
+		fields = append(fields, contractFields(structs, field.Type, tagKey, contractField.Name+".", contractField.GoName+".", depth+1, visited)...)
+	}
+
+	return fields
+}
@@ -0,0 +1,441 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"goapianalyzer/pkg/errors"
+)
+
+// Operators of a parsed node query
+const (
+	QueryOpAnd  = "and"
+	QueryOpOr   = "or"
+	QueryOpNot  = "not"
+	QueryOpTerm = "term"
+)
+
+// Fields a query term can select on
+const (
+	FieldText       = ""           // Free text, matched through the search index
+	FieldType       = "type"       // Node type: function, struct, interface, ...
+	FieldName       = "name"       // Node name
+	FieldPackage    = "pkg"        // Package name or import path
+	FieldFile       = "file"       // File path; patterns without a slash match trailing path segments
+	FieldReceiver   = "receiver"   // Method receiver, with or without the pointer
+	FieldCalls      = "calls"      // A function called from the body, like repo.GetAllNodes
+	FieldComplexity = "complexity" // Cyclomatic complexity
+	FieldLines      = "lines"      // Lines of the declaration
+	FieldDoc        = "doc"        // Substring of the doc comment
+	FieldSymbol     = "symbol"     // Canonical symbol key
+	FieldGenerated  = "generated"  // true or false
+	FieldExported   = "exported"   // true or false
+)
+
+var (
+	queryFields = map[string]string{
+		"type": FieldType, "name": FieldName, "pkg": FieldPackage, "package": FieldPackage,
+		"file": FieldFile, "receiver": FieldReceiver, "recv": FieldReceiver, "calls": FieldCalls,
+		"complexity": FieldComplexity, "lines": FieldLines, "doc": FieldDoc, "symbol": FieldSymbol,
+		"generated": FieldGenerated, "exported": FieldExported,
+	}
+	numericFields = map[string]bool{FieldComplexity: true, FieldLines: true}
+	booleanFields = map[string]bool{FieldGenerated: true, FieldExported: true}
+
+	fieldTermPattern = regexp.MustCompile(`^([A-Za-z_]+)(:|>=|<=|>|<|=)`)
+	comparePattern   = regexp.MustCompile(`^(>=|<=|>|<|=)`)
+)
+
+// NodeQuery is a node of a parsed query: an AND, OR or NOT of child queries, or a term
+type NodeQuery struct {
+	Op       string       `json:"op"`
+	Children []*NodeQuery `json:"children,omitempty"`
+	Term     *QueryTerm   `json:"term,omitempty"`
+}
+
+// QueryTerm is a single condition such as type:function or complexity>10
+type QueryTerm struct {
+	Field    string `json:"field,omitempty"`
+	Operator string `json:"operator"` // :, =, >, >=, <, <=
+	Value    string `json:"value"`
+	Position int    `json:"position"` // 1-based column in the query
+
+	number  int
+	pattern *regexp.Regexp
+}
+
+// Terms returns the terms of the query in source order
+func (q *NodeQuery) Terms() []*QueryTerm {
+	if q.Term != nil {
+		return []*QueryTerm{q.Term}
+	}
+	var terms []*QueryTerm
+	for _, child := range q.Children {
+		terms = append(terms, child.Terms()...)
+	}
+	return terms
+}
+
+// IsFreeText reports whether the query is only words to look up, with no fields or operators
+func (q *NodeQuery) IsFreeText() bool {
+	switch q.Op {
+	case QueryOpTerm:
+		return q.Term.Field == FieldText
+	case QueryOpAnd:
+		for _, child := range q.Children {
+			if !child.IsFreeText() {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Matches reports whether a value equals the term, or matches it as a glob
+// when the term contains * or ?. Matching ignores case.
+func (t *QueryTerm) Matches(value string) bool {
+	if t.pattern != nil {
+		return t.pattern.MatchString(value)
+	}
+	return strings.EqualFold(t.Value, value)
+}
+
+// MatchesSuffix is like Matches but also accepts any part of the value following a separator,
+// so pkg:usecase matches goapianalyzer/internal/core/usecase
+func (t *QueryTerm) MatchesSuffix(value, separator string) bool {
+	for {
+		if t.Matches(value) {
+			return true
+		}
+		i := strings.Index(value, separator)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(separator):]
+	}
+}
+
+// Contains reports whether the value contains the term, ignoring case
+func (t *QueryTerm) Contains(value string) bool {
+	if t.pattern != nil {
+		return t.pattern.MatchString(value)
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(t.Value))
+}
+
+// Compare checks a number against a numeric term
+func (t *QueryTerm) Compare(value int) bool {
+	switch t.Operator {
+	case ">":
+		return value > t.number
+	case ">=":
+		return value >= t.number
+	case "<":
+		return value < t.number
+	case "<=":
+		return value <= t.number
+	default:
+		return value == t.number
+	}
+}
+
+// Bool returns the value of a boolean term
+func (t *QueryTerm) Bool() bool {
+	return t.number == 1
+}
+
+// ParseNodeQuery parses a node query. Terms are combined with AND, OR and NOT
+// (or a leading -), grouped with parentheses; adjacent terms are ANDed, and AND
+// binds tighter than OR. Syntax errors name the offending token and its position.
+func ParseNodeQuery(input string) (*NodeQuery, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.NewValidationError("query cannot be empty")
+	}
+
+	p := &queryParser{tokens: tokens}
+	query, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, queryError(tok, "unexpected %q", tok.text)
+	}
+	return query, nil
+}
+
+type queryTokenKind int
+
+const (
+	tokenTerm queryTokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLeftParen
+	tokenRightParen
+)
+
+type queryToken struct {
+	kind     queryTokenKind
+	text     string // Source text, for error messages
+	value    string // Unquoted term text
+	position int
+	offsets  []int // Position in the query of each byte of value
+}
+
+// positionAt maps a byte offset in the value of a term to its position in the
+// query; offsets before the value point at the token, past it just after the token
+func (t *queryToken) positionAt(offset int) int {
+	if offset < 0 {
+		return t.position
+	}
+	if offset < len(t.offsets) {
+		return t.offsets[offset]
+	}
+	return t.position + utf8.RuneCountInString(t.text)
+}
+
+// lexQuery splits a query into terms, keywords and parentheses. Quoted strings
+// keep spaces and parentheses, as in doc:"inverted index".
+func lexQuery(input string) ([]*queryToken, error) {
+	runes := []rune(input)
+	var tokens []*queryToken
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, &queryToken{kind: tokenLeftParen, text: "(", position: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, &queryToken{kind: tokenRightParen, text: ")", position: i + 1})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, &queryToken{kind: tokenNot, text: "-", position: i + 1})
+			i++
+		default:
+			start := i
+			var value strings.Builder
+			var offsets []int
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] != '"' {
+					value.WriteRune(runes[i])
+					offsets = appendOffsets(offsets, runes[i], i+1)
+					i++
+					continue
+				}
+				end := i + 1
+				for end < len(runes) && runes[end] != '"' {
+					end++
+				}
+				if end == len(runes) {
+					return nil, queryError(&queryToken{text: string(runes[i:]), position: i + 1}, "unterminated quoted string")
+				}
+				for j := i + 1; j < end; j++ {
+					value.WriteRune(runes[j])
+					offsets = appendOffsets(offsets, runes[j], j+1)
+				}
+				i = end + 1
+			}
+
+			tok := &queryToken{kind: tokenTerm, text: string(runes[start:i]), value: value.String(), position: start + 1, offsets: offsets}
+			switch tok.text {
+			case "AND", "&&":
+				tok.kind = tokenAnd
+			case "OR", "||":
+				tok.kind = tokenOr
+			case "NOT", "!":
+				tok.kind = tokenNot
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []*queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *queryParser) next() *queryToken {
+	tok := p.peek()
+	if tok != nil {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr() (*NodeQuery, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*NodeQuery{left}
+	for tok := p.peek(); tok != nil && tok.kind == tokenOr; tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &NodeQuery{Op: QueryOpOr, Children: children}, nil
+}
+
+func (p *queryParser) parseAnd() (*NodeQuery, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*NodeQuery{left}
+	for tok := p.peek(); tok != nil && tok.kind != tokenOr && tok.kind != tokenRightParen; tok = p.peek() {
+		if tok.kind == tokenAnd {
+			p.next()
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &NodeQuery{Op: QueryOpAnd, Children: children}, nil
+}
+
+func (p *queryParser) parseUnary() (*NodeQuery, error) {
+	tok := p.next()
+	if tok == nil {
+		last := p.tokens[len(p.tokens)-1]
+		return nil, queryError(last, "expected a term after %q", last.text)
+	}
+
+	switch tok.kind {
+	case tokenNot:
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NodeQuery{Op: QueryOpNot, Children: []*NodeQuery{child}}, nil
+	case tokenLeftParen:
+		query, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing := p.next()
+		if closing == nil {
+			return nil, queryError(tok, "unbalanced %q", tok.text)
+		}
+		if closing.kind != tokenRightParen {
+			return nil, queryError(closing, "expected \")\" but found %q", closing.text)
+		}
+		return query, nil
+	case tokenTerm:
+		term, err := parseQueryTerm(tok)
+		if err != nil {
+			return nil, err
+		}
+		return &NodeQuery{Op: QueryOpTerm, Term: term}, nil
+	default:
+		return nil, queryError(tok, "unexpected %q", tok.text)
+	}
+}
+
+func parseQueryTerm(tok *queryToken) (*QueryTerm, error) {
+	term := &QueryTerm{Field: FieldText, Operator: ":", Value: tok.value, Position: tok.position}
+
+	// Errors about the operator or the value point at them rather than at the term
+	operatorAt := tok.position
+	if match := fieldTermPattern.FindStringSubmatch(tok.value); match != nil {
+		field, known := queryFields[strings.ToLower(match[1])]
+		if !known {
+			return nil, queryError(tok, "unknown field %q", match[1])
+		}
+		term.Field = field
+		term.Operator = match[2]
+		term.Value = tok.value[len(match[0]):]
+
+		// complexity:>10 reads like complexity>10
+		if op := comparePattern.FindString(term.Value); term.Operator == ":" && op != "" {
+			term.Operator = op
+			term.Value = term.Value[len(op):]
+		}
+		operatorAt = tok.positionAt(len(tok.value) - len(term.Value) - len(term.Operator))
+	}
+	valueAt := tok.positionAt(len(tok.value) - len(term.Value))
+
+	if term.Value == "" {
+		return nil, queryErrorAt(valueAt, "missing value in %q", tok.text)
+	}
+
+	switch {
+	case numericFields[term.Field]:
+		number, err := strconv.Atoi(term.Value)
+		if err != nil {
+			return nil, queryErrorAt(valueAt, "%s expects a number but got %q", term.Field, term.Value)
+		}
+		term.number = number
+	case term.Operator != ":" && term.Operator != "=":
+		return nil, queryErrorAt(operatorAt, "operator %q only applies to numeric fields", term.Operator)
+	case booleanFields[term.Field]:
+		value, err := strconv.ParseBool(term.Value)
+		if err != nil {
+			return nil, queryErrorAt(valueAt, "%s expects true or false but got %q", term.Field, term.Value)
+		}
+		if value {
+			term.number = 1
+		}
+	case term.Field != FieldText && strings.ContainsAny(term.Value, "*?"):
+		pattern := regexp.QuoteMeta(term.Value)
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+		if term.Field == FieldDoc {
+			term.pattern = regexp.MustCompile(`(?i)` + pattern)
+		} else {
+			term.pattern = regexp.MustCompile(`(?i)^` + pattern + `$`)
+		}
+	}
+
+	return term, nil
+}
+
+func queryError(tok *queryToken, format string, args ...interface{}) error {
+	return queryErrorAt(tok.position, format, args...)
+}
+
+func queryErrorAt(position int, format string, args ...interface{}) error {
+	return errors.NewValidationError(fmt.Sprintf("invalid query: %s at position %d", fmt.Sprintf(format, args...), position))
+}
+
+// appendOffsets records position for each UTF-8 byte of r
+func appendOffsets(offsets []int, r rune, position int) []int {
+	for n := utf8.RuneLen(r); n > 0; n-- {
+		offsets = append(offsets, position)
+	}
+	return offsets
+}
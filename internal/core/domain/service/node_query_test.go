@@ -0,0 +1,107 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"goapianalyzer/pkg/errors"
+)
+
+// describeQuery writes a parsed query as a compact S-expression for comparisons
+func describeQuery(q *NodeQuery) string {
+	if q.Op == QueryOpTerm {
+		field := q.Term.Field
+		if field == FieldText {
+			field = "text"
+		}
+		return field + q.Term.Operator + q.Term.Value
+	}
+	parts := make([]string, 0, len(q.Children))
+	for _, child := range q.Children {
+		parts = append(parts, describeQuery(child))
+	}
+	return "(" + q.Op + " " + strings.Join(parts, " ") + ")"
+}
+
+func TestParseNodeQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		want     string
+		freeText bool
+	}{
+		{query: "GetAllNodes", want: "text:GetAllNodes", freeText: true},
+		{query: "store  analysis", want: "(and text:store text:analysis)", freeText: true},
+		{query: "-handler", want: "(not text:handler)"},
+		{query: "NOT handler", want: "(not text:handler)"},
+		{query: `"inverted index"`, want: "text:inverted index", freeText: true},
+		{query: `"a (b)" OR c`, want: "(or text:a (b) text:c)"},
+		{query: "type:function complexity>10", want: "(and type:function complexity>10)"},
+		{query: "complexity:>=3", want: "complexity>=3"},
+		{query: `doc:"inverted index"`, want: "doc:inverted index"},
+		{query: "type:function -(pkg:usecase OR name:Get*)", want: "(and type:function (not (or pkg:usecase name:Get*)))"},
+		{query: "exported=true && lines<40", want: "(and exported=true lines<40)"},
+	}
+
+	for _, test := range tests {
+		query, err := ParseNodeQuery(test.query)
+		if err != nil {
+			t.Errorf("ParseNodeQuery(%q): %v", test.query, err)
+			continue
+		}
+		if got := describeQuery(query); got != test.want {
+			t.Errorf("ParseNodeQuery(%q) = %s, want %s", test.query, got, test.want)
+		}
+		if got := query.IsFreeText(); got != test.freeText {
+			t.Errorf("ParseNodeQuery(%q).IsFreeText() = %v, want %v", test.query, got, test.freeText)
+		}
+	}
+}
+
+func TestParseNodeQueryErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		message  string
+		position int
+	}{
+		{query: "colour:red", message: `unknown field "colour"`, position: 1},
+		{query: "complexity>>3", message: "expects a number", position: 12},
+		{query: "type:function name>Get", message: `operator ">" only applies to numeric fields`, position: 19},
+		{query: "generated:maybe", message: "expects true or false", position: 11},
+		{query: "name:", message: "missing value", position: 6},
+		{query: `doc:"open`, message: "unterminated quoted string", position: 5},
+		{query: "(type:function", message: "unbalanced", position: 1},
+		{query: "type:function OR", message: "expected a term", position: 15},
+	}
+
+	for _, test := range tests {
+		_, err := ParseNodeQuery(test.query)
+		if !errors.IsValidationError(err) {
+			t.Errorf("ParseNodeQuery(%q): got %v, want a validation error", test.query, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.message) {
+			t.Errorf("ParseNodeQuery(%q) = %q, want it to mention %q", test.query, err, test.message)
+		}
+		if want := "at position " + strconv.Itoa(test.position); !strings.HasSuffix(err.Error(), want) {
+			t.Errorf("ParseNodeQuery(%q) = %q, want it to end with %q", test.query, err, want)
+		}
+	}
+}
+
+func TestQueryTermMatches(t *testing.T) {
+	query, err := ParseNodeQuery("name:Get* complexity>=3 doc:index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms := query.Terms()
+	if !terms[0].Matches("GetAllNodes") || terms[0].Matches("ListNodes") {
+		t.Error("name:Get* should match GetAllNodes and not ListNodes")
+	}
+	if !terms[1].Compare(3) || terms[1].Compare(2) {
+		t.Error("complexity>=3 should accept 3 and reject 2")
+	}
+	if !terms[2].Contains("Builds the inverted Index of a project") {
+		t.Error("doc:index should match case-insensitively anywhere in the doc comment")
+	}
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// Identifiers metavariables are rewritten to so that patterns parse as Go
+const (
+	metavarPrefix     = "__gapmv_"
+	listMetavarPrefix = "__gapml_"
+	statementWrapper  = "package p\nfunc _() {\n"
+)
+
+var metavarSyntax = regexp.MustCompile(`\$(\$?)([A-Za-z_]\w*)`)
+
+// qualifiedTypeSyntax finds package-qualified type names in a constraint, by
+// package name (gin.Context) or import path (github.com/gin-gonic/gin.Context)
+var qualifiedTypeSyntax = regexp.MustCompile(`([\w.\-/]*\w)\.[A-Za-z_]\w*`)
+
+var (
+	posType          = reflect.TypeOf(token.NoPos)
+	objectType       = reflect.TypeOf((*ast.Object)(nil))
+	scopeType        = reflect.TypeOf((*ast.Scope)(nil))
+	commentGroupType = reflect.TypeOf((*ast.CommentGroup)(nil))
+)
+
+// CodePattern is a compiled structural pattern: a Go expression or statement list
+// in which $name binds any expression or statement, $$name any run of list
+// elements (call arguments, statements, ...) and $_ matches without binding.
+// A metavariable used twice must bind equal code.
+type CodePattern struct {
+	Source        string
+	Metavariables []string
+
+	expr        ast.Expr
+	stmts       []ast.Stmt
+	constraints map[string]string // metavariable -> required type
+}
+
+// CompilePattern parses a pattern. constraints optionally restricts metavariables
+// to expressions of a type, written as in source: *gin.Context, error, []string.
+func CompilePattern(source string, constraints map[string]string) (*CodePattern, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errors.NewValidationError("pattern cannot be empty")
+	}
+
+	rewritten, offsets := rewriteMetavariables(source)
+	pattern := &CodePattern{Source: source, constraints: constraints}
+
+	if expr, err := parser.ParseExpr(rewritten); err == nil {
+		pattern.expr = expr
+	} else {
+		file, err := parser.ParseFile(token.NewFileSet(), "pattern.go", statementWrapper+rewritten+"\n}", 0)
+		if err != nil {
+			return nil, patternError(err, offsets, len(statementWrapper))
+		}
+		pattern.stmts = file.Decls[0].(*ast.FuncDecl).Body.List
+		if len(pattern.stmts) == 0 {
+			return nil, errors.NewValidationError("pattern contains no expression or statement")
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, match := range metavarSyntax.FindAllStringSubmatch(source, -1) {
+		if name := match[2]; name != "_" && !seen[name] {
+			seen[name] = true
+			pattern.Metavariables = append(pattern.Metavariables, name)
+		}
+	}
+	for name := range constraints {
+		if !seen[name] {
+			return nil, errors.NewValidationError(fmt.Sprintf("constraint on unknown metavariable $%s", name))
+		}
+	}
+
+	return pattern, nil
+}
+
+// ConstraintPackages returns the packages each constrained metavariable refers to
+// in its type, by name or import path
+func (p *CodePattern) ConstraintPackages() map[string][]string {
+	packages := make(map[string][]string)
+	for name, constraint := range p.constraints {
+		for _, match := range qualifiedTypeSyntax.FindAllStringSubmatch(constraint, -1) {
+			packages[name] = append(packages[name], match[1])
+		}
+	}
+	return packages
+}
+
+// rewriteMetavariables replaces metavariables with identifiers and maps every byte
+// of the rewritten source back to its offset in the original
+func rewriteMetavariables(source string) (string, []int) {
+	var builder strings.Builder
+	offsets := make([]int, 0, len(source))
+	last := 0
+
+	write := func(text string, original int) {
+		builder.WriteString(text)
+		for range text {
+			offsets = append(offsets, original)
+		}
+	}
+
+	for _, loc := range metavarSyntax.FindAllStringSubmatchIndex(source, -1) {
+		for i := last; i < loc[0]; i++ {
+			write(source[i:i+1], i)
+		}
+		prefix := metavarPrefix
+		if loc[3] > loc[2] {
+			prefix = listMetavarPrefix
+		}
+		write(prefix+source[loc[4]:loc[5]], loc[0])
+		last = loc[1]
+	}
+	for i := last; i < len(source); i++ {
+		write(source[i:i+1], i)
+	}
+	offsets = append(offsets, len(source))
+
+	return builder.String(), offsets
+}
+
+func patternError(err error, offsets []int, wrapperLength int) error {
+	message := err.Error()
+	position := 0
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+		message = list[0].Msg
+		offset := list[0].Pos.Offset - wrapperLength
+		if offset < 0 {
+			offset = 0
+		}
+		if offset >= len(offsets) {
+			offset = len(offsets) - 1
+		}
+		position = offsets[offset] + 1
+	}
+	return errors.NewValidationError(fmt.Sprintf("invalid pattern: %s at position %d", message, position))
+}
+
+func metavariable(node ast.Node) (name string, list bool, ok bool) {
+	if stmt, isStmt := node.(*ast.ExprStmt); isStmt {
+		node = stmt.X
+	}
+	ident, isIdent := node.(*ast.Ident)
+	if !isIdent {
+		return "", false, false
+	}
+	switch {
+	case strings.HasPrefix(ident.Name, metavarPrefix):
+		return strings.TrimPrefix(ident.Name, metavarPrefix), false, true
+	case strings.HasPrefix(ident.Name, listMetavarPrefix):
+		return strings.TrimPrefix(ident.Name, listMetavarPrefix), true, true
+	}
+	return "", false, false
+}
+
+// Match finds every occurrence of the pattern in a file. info may be nil, in
+// which case selectors compare by name and type constraints never hold.
+func (p *CodePattern) Match(fileSet *token.FileSet, file *ast.File, info *types.Info, filePath, content string) []*entity.PatternMatch {
+	var matches []*entity.PatternMatch
+	record := func(m *unifier, from, to ast.Node) {
+		matches = append(matches, m.result(p, fileSet, file, filePath, content, from, to))
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil {
+			return false
+		}
+		switch {
+		case p.expr != nil:
+			if expr, ok := node.(ast.Expr); ok {
+				m := newUnifier(info, p.constraints)
+				if m.node(p.expr, expr) {
+					record(m, expr, expr)
+				}
+			}
+		case len(p.stmts) == 1:
+			if stmt, ok := node.(ast.Stmt); ok {
+				m := newUnifier(info, p.constraints)
+				if m.node(p.stmts[0], stmt) {
+					record(m, stmt, stmt)
+				}
+			}
+		default:
+			var list []ast.Stmt
+			switch n := node.(type) {
+			case *ast.BlockStmt:
+				list = n.List
+			case *ast.CaseClause:
+				list = n.Body
+			case *ast.CommClause:
+				list = n.Body
+			}
+			for start := range list {
+				// The shortest run of statements from start that matches
+				for end := start + 1; end <= len(list); end++ {
+					m := newUnifier(info, p.constraints)
+					if m.list(reflect.ValueOf(p.stmts), reflect.ValueOf(list[start:end])) {
+						record(m, list[start], list[end-1])
+						break
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	return matches
+}
+
+// unifier matches a pattern against code, collecting metavariable bindings
+type unifier struct {
+	info        *types.Info
+	constraints map[string]string
+	bindings    map[string][]ast.Node
+	literal     bool // Comparing two pieces of code: identifiers are not metavariables
+}
+
+func newUnifier(info *types.Info, constraints map[string]string) *unifier {
+	return &unifier{info: info, constraints: constraints, bindings: make(map[string][]ast.Node)}
+}
+
+func (m *unifier) node(pattern, target ast.Node) bool {
+	if isNilNode(pattern) || isNilNode(target) {
+		return isNilNode(pattern) && isNilNode(target)
+	}
+
+	if !m.literal {
+		if name, _, ok := metavariable(pattern); ok {
+			if _, isStmt := pattern.(*ast.ExprStmt); isStmt {
+				if _, targetIsStmt := target.(ast.Stmt); !targetIsStmt {
+					return false
+				}
+			}
+			return m.bind(name, []ast.Node{target})
+		}
+	}
+
+	switch p := pattern.(type) {
+	case *ast.Ident:
+		t, ok := target.(*ast.Ident)
+		return ok && m.ident(p, t)
+	case *ast.SelectorExpr:
+		t, ok := target.(*ast.SelectorExpr)
+		return ok && m.selector(p, t)
+	}
+
+	if reflect.TypeOf(pattern) != reflect.TypeOf(target) {
+		return false
+	}
+	return m.fields(reflect.ValueOf(pattern).Elem(), reflect.ValueOf(target).Elem())
+}
+
+func (m *unifier) ident(pattern, target *ast.Ident) bool {
+	if m.literal && m.info != nil {
+		// Two pieces of code name the same thing when they resolve to the same object
+		patternObj, targetObj := m.info.ObjectOf(pattern), m.info.ObjectOf(target)
+		if patternObj != nil && targetObj != nil {
+			return patternObj == targetObj
+		}
+	}
+	return pattern.Name == target.Name
+}
+
+// selector matches pkg.Name against the package the code actually imports, so
+// http.StatusOK also matches code importing net/http under another name
+func (m *unifier) selector(pattern, target *ast.SelectorExpr) bool {
+	if !m.node(pattern.Sel, target.Sel) {
+		return false
+	}
+
+	patternIdent, isIdent := pattern.X.(*ast.Ident)
+	targetIdent, targetIsIdent := target.X.(*ast.Ident)
+	if isIdent && targetIsIdent && m.info != nil {
+		if _, _, isVar := metavariable(patternIdent); !isVar || m.literal {
+			if pkgName, ok := m.info.Uses[targetIdent].(*types.PkgName); ok {
+				imported := pkgName.Imported()
+				return patternIdent.Name == pkgName.Name() || patternIdent.Name == imported.Name() ||
+					patternIdent.Name == imported.Path() || patternIdent.Name == path.Base(imported.Path())
+			}
+		}
+	}
+
+	return m.node(pattern.X, target.X)
+}
+
+func (m *unifier) fields(pattern, target reflect.Value) bool {
+	for i := 0; i < pattern.NumField(); i++ {
+		switch pattern.Field(i).Type() {
+		case posType, objectType, scopeType, commentGroupType:
+			continue
+		}
+		if !m.value(pattern.Field(i), target.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *unifier) value(pattern, target reflect.Value) bool {
+	switch pattern.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if pattern.IsNil() || target.IsNil() {
+			return pattern.IsNil() && target.IsNil()
+		}
+		patternNode, isNode := pattern.Interface().(ast.Node)
+		targetNode, targetIsNode := target.Interface().(ast.Node)
+		if isNode && targetIsNode {
+			return m.node(patternNode, targetNode)
+		}
+		return m.value(pattern.Elem(), target.Elem())
+	case reflect.Struct:
+		return m.fields(pattern, target)
+	case reflect.Slice:
+		return m.list(pattern, target)
+	default:
+		return pattern.Interface() == target.Interface()
+	}
+}
+
+// list matches a list of nodes where $$name binds any run of elements
+func (m *unifier) list(pattern, target reflect.Value) bool {
+	return m.listFrom(pattern, 0, target, 0)
+}
+
+func (m *unifier) listFrom(pattern reflect.Value, i int, target reflect.Value, j int) bool {
+	if i == pattern.Len() {
+		return j == target.Len()
+	}
+
+	element := pattern.Index(i)
+	if node, ok := element.Interface().(ast.Node); ok && !m.literal {
+		if name, isList, isVar := metavariable(node); isVar && isList {
+			for end := j; end <= target.Len(); end++ {
+				saved := m.save()
+				run := make([]ast.Node, 0, end-j)
+				for k := j; k < end; k++ {
+					run = append(run, target.Index(k).Interface().(ast.Node))
+				}
+				if m.bind(name, run) && m.listFrom(pattern, i+1, target, end) {
+					return true
+				}
+				m.bindings = saved
+			}
+			return false
+		}
+	}
+
+	if j == target.Len() {
+		return false
+	}
+	saved := m.save()
+	if m.value(element, target.Index(j)) && m.listFrom(pattern, i+1, target, j+1) {
+		return true
+	}
+	m.bindings = saved
+	return false
+}
+
+func (m *unifier) save() map[string][]ast.Node {
+	saved := make(map[string][]ast.Node, len(m.bindings))
+	for name, nodes := range m.bindings {
+		saved[name] = nodes
+	}
+	return saved
+}
+
+// bind records what a metavariable matched; a metavariable seen before must match equal code
+func (m *unifier) bind(name string, nodes []ast.Node) bool {
+	if constraint, exists := m.constraints[name]; exists {
+		for _, node := range nodes {
+			if !m.hasType(node, constraint) {
+				return false
+			}
+		}
+	}
+	if name == "_" {
+		return true
+	}
+
+	previous, exists := m.bindings[name]
+	if !exists {
+		m.bindings[name] = nodes
+		return true
+	}
+	if len(previous) != len(nodes) {
+		return false
+	}
+	same := &unifier{info: m.info, literal: true}
+	for i := range nodes {
+		if !same.node(previous[i], nodes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *unifier) hasType(node ast.Node, constraint string) bool {
+	expr, ok := node.(ast.Expr)
+	if !ok || m.info == nil {
+		return false
+	}
+	typ := m.info.TypeOf(expr)
+	if typ == nil {
+		return false
+	}
+	short := types.TypeString(typ, func(pkg *types.Package) string { return pkg.Name() })
+	return constraint == short || constraint == types.TypeString(typ, nil)
+}
+
+func (m *unifier) result(
+	pattern *CodePattern,
+	fileSet *token.FileSet,
+	file *ast.File,
+	filePath, content string,
+	from, to ast.Node,
+) *entity.PatternMatch {
+	start, end := fileSet.Position(from.Pos()), fileSet.Position(to.End())
+	match := &entity.PatternMatch{
+		File:     filePath,
+		Function: enclosingFunction(file, from.Pos()),
+		Position: &entity.Position{Line: start.Line, Column: start.Column, Offset: start.Offset},
+		End:      &entity.Position{Line: end.Line, Column: end.Column, Offset: end.Offset},
+		Text:     sourceText(content, start.Offset, end.Offset),
+		Bindings: make(map[string]string, len(pattern.Metavariables)),
+	}
+
+	names := make([]string, 0, len(m.bindings))
+	for name := range m.bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		nodes := m.bindings[name]
+		if len(nodes) == 0 {
+			match.Bindings[name] = ""
+			continue
+		}
+		first, last := fileSet.Position(nodes[0].Pos()), fileSet.Position(nodes[len(nodes)-1].End())
+		match.Bindings[name] = sourceText(content, first.Offset, last.Offset)
+	}
+
+	return match
+}
+
+func enclosingFunction(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < funcDecl.Pos() || pos >= funcDecl.End() {
+			continue
+		}
+		if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+			return "(" + types.ExprString(funcDecl.Recv.List[0].Type) + ")." + funcDecl.Name.Name
+		}
+		return funcDecl.Name.Name
+	}
+	return ""
+}
+
+func sourceText(content string, start, end int) string {
+	if start < 0 || end > len(content) || start > end {
+		return ""
+	}
+	return content[start:end]
+}
+
+func isNilNode(node ast.Node) bool {
+	if node == nil {
+		return true
+	}
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// Kinds of symbol references
+const (
+	ReferenceCall       = "call"
+	ReferenceType       = "type"
+	ReferenceEmbedding  = "embedding"
+	ReferenceField      = "field"
+	ReferenceAssignment = "assignment"
+	ReferenceValue      = "value"
+)
+
+// FindReferences lists the identifiers of a file that denote an object, with the
+// way each of them uses it
+func FindReferences(fset *token.FileSet, file *ast.File, info *types.Info, filePath, content string, target types.Object) []*entity.SymbolReference {
+	target = OriginObject(target)
+
+	var references []*entity.SymbolReference
+	var stack []ast.Node
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+
+		ident, ok := node.(*ast.Ident)
+		if !ok || OriginObject(info.Uses[ident]) != target {
+			return true
+		}
+		start, end := fset.Position(ident.Pos()), fset.Position(ident.End())
+		references = append(references, &entity.SymbolReference{
+			File:     filePath,
+			Kind:     referenceKind(stack, target),
+			Function: enclosingFunction(file, ident.Pos()),
+			Position: &entity.Position{Line: start.Line, Column: start.Column, Offset: start.Offset},
+			End:      &entity.Position{Line: end.Line, Column: end.Column, Offset: end.Offset},
+			Text:     lineText(content, start.Offset),
+		})
+		return true
+	})
+
+	return references
+}
+
+// referenceKind classifies the identifier on top of a stack of enclosing nodes
+func referenceKind(stack []ast.Node, target types.Object) string {
+	ident := stack[len(stack)-1]
+	parent := func(i int) ast.Node {
+		if i < 0 || i >= len(stack) {
+			return nil
+		}
+		return stack[len(stack)-1-i]
+	}
+
+	// Climb from the identifier to the whole expression it belongs to: a
+	// qualified name, a pointer or an instantiation of a generic type
+	expr, depth := ast.Node(ident), 1
+climb:
+	for ; ; depth++ {
+		switch up := parent(depth).(type) {
+		case *ast.SelectorExpr:
+			if up.Sel == ident {
+				expr = up
+				continue
+			}
+		case *ast.StarExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			if _, isType := target.(*types.TypeName); isType {
+				expr = up
+				continue
+			}
+		}
+		break climb
+	}
+
+	if field, ok := parent(depth).(*ast.Field); ok && len(field.Names) == 0 && field.Type == expr {
+		switch parent(depth + 2).(type) {
+		case *ast.StructType, *ast.InterfaceType:
+			return ReferenceEmbedding
+		}
+	}
+	if _, ok := target.(*types.TypeName); ok {
+		return ReferenceType
+	}
+
+	// Only a selector makes the expression of calls and assignments
+	if selector, ok := parent(1).(*ast.SelectorExpr); ok && selector.Sel == ident {
+		expr, depth = selector, 2
+	} else {
+		expr, depth = ident, 1
+	}
+	switch up := parent(depth).(type) {
+	case *ast.CallExpr:
+		if up.Fun == expr {
+			return ReferenceCall
+		}
+	case *ast.AssignStmt:
+		for _, lhs := range up.Lhs {
+			if lhs == expr {
+				return ReferenceAssignment
+			}
+		}
+	case *ast.IncDecStmt:
+		if up.X == expr {
+			return ReferenceAssignment
+		}
+	}
+	if variable, ok := target.(*types.Var); ok && variable.IsField() {
+		return ReferenceField
+	}
+	return ReferenceValue
+}
+
+// DeclaredObject finds the object declared by a code node in the syntax tree of its file
+func DeclaredObject(file *ast.File, info *types.Info, node *entity.CodeNode) types.Object {
+	receiver, _ := node.Metadata["receiver"].(string)
+	receiver = entity.NormalizeReceiver(receiver)
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if node.Type != "function" || decl.Name.Name != node.Name {
+				continue
+			}
+			if entity.NormalizeReceiver(funcReceiver(decl)) == receiver {
+				if obj := info.Defs[decl.Name]; obj != nil {
+					return obj
+				}
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				var names []*ast.Ident
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if node.Type == "struct" || node.Type == "interface" || node.Type == "type" {
+						names = []*ast.Ident{spec.Name}
+					}
+				case *ast.ValueSpec:
+					if decl.Tok == token.VAR && node.Type == "variable" || decl.Tok == token.CONST && node.Type == "constant" {
+						names = spec.Names
+					}
+				}
+				for _, name := range names {
+					if name.Name != node.Name {
+						continue
+					}
+					if obj := info.Defs[name]; obj != nil {
+						return obj
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeclarationAt returns the receiver and name of the top-level declaration
+// containing a position, which is how code nodes are keyed
+func DeclarationAt(file *ast.File, pos token.Pos) (string, string, bool) {
+	for _, decl := range file.Decls {
+		if pos < decl.Pos() || pos >= decl.End() {
+			continue
+		}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			return funcReceiver(decl), decl.Name.Name, true
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if pos < spec.Pos() || pos >= spec.End() {
+					continue
+				}
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					return "", spec.Name.Name, true
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Pos() == pos {
+							return "", name.Name, true
+						}
+					}
+					return "", spec.Names[0].Name, true
+				}
+			}
+		}
+		return "", "", false
+	}
+	return "", "", false
+}
+
+// IdentAt finds the identifier at a 1-based line and column of a file. A
+// position right after an identifier, where an editor cursor usually rests, also selects it.
+func IdentAt(fset *token.FileSet, file *ast.File, line, column int) (*ast.Ident, error) {
+	tokenFile := fset.File(file.Pos())
+	if line < 1 || line > tokenFile.LineCount() || column < 1 {
+		return nil, errors.NewValidationError(fmt.Sprintf("position %d:%d is outside of the file", line, column))
+	}
+	lineStart := tokenFile.LineStart(line)
+	offset := tokenFile.Offset(lineStart) + column - 1
+	if offset > tokenFile.Size() {
+		return nil, errors.NewValidationError(fmt.Sprintf("position %d:%d is outside of the file", line, column))
+	}
+	pos := tokenFile.Pos(offset)
+
+	var found, before *ast.Ident
+	ast.Inspect(file, func(node ast.Node) bool {
+		if found != nil || node == nil || pos < node.Pos() || pos > node.End() {
+			return false
+		}
+		if ident, ok := node.(*ast.Ident); ok {
+			if pos < ident.End() {
+				found = ident
+			} else {
+				before = ident
+			}
+		}
+		return true
+	})
+
+	if found == nil {
+		found = before
+	}
+	if found == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("no identifier at %d:%d", line, column))
+	}
+	return found, nil
+}
+
+// ObjectKind names the kind of declaration an object comes from
+func ObjectKind(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if signature, ok := obj.Type().(*types.Signature); ok && signature.Recv() != nil {
+			return "method"
+		}
+		return "function"
+	case *types.TypeName:
+		return "type"
+	case *types.Var:
+		if obj.IsField() {
+			return "field"
+		}
+		return "variable"
+	case *types.Const:
+		return "constant"
+	case *types.PkgName:
+		return "package"
+	case *types.Label:
+		return "label"
+	default:
+		return "builtin"
+	}
+}
+
+// ObjectReceiver returns the receiver type of a method as the parser writes it, e.g. "*Service"
+func ObjectReceiver(obj types.Object) string {
+	function, ok := obj.(*types.Func)
+	if !ok {
+		return ""
+	}
+	signature, ok := function.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil {
+		return ""
+	}
+	return types.TypeString(signature.Recv().Type(), func(*types.Package) string { return "" })
+}
+
+// OriginObject maps the methods and fields of instantiated generic types to their declaration
+func OriginObject(obj types.Object) types.Object {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin()
+	case *types.Var:
+		return obj.Origin()
+	}
+	return obj
+}
+
+func funcReceiver(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	return types.ExprString(decl.Recv.List[0].Type)
+}
+
+// lineText returns the trimmed source line containing an offset
+func lineText(content string, offset int) string {
+	if offset < 0 || offset > len(content) {
+		return ""
+	}
+	start := strings.LastIndexByte(content[:offset], '\n') + 1
+	end := strings.IndexByte(content[offset:], '\n')
+	if end < 0 {
+		end = len(content)
+	} else {
+		end += offset
+	}
+	return strings.TrimSpace(content[start:end])
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff returns the line ranges of the new version of each file that a
+// unified diff adds or changes. Pure deletions mark the line following them.
+// Deleted files are skipped since nothing in the current tree declares their code.
+func ParseUnifiedDiff(diff string) ([]*entity.LineRange, error) {
+	var ranges []*entity.LineRange
+	var current *entity.LineRange
+	file := ""
+	line := 0
+	oldLeft, newLeft := 0, 0 // Lines of the current hunk still to read
+	hunks := 0
+
+	flush := func() {
+		if current != nil {
+			ranges = append(ranges, current)
+			current = nil
+		}
+	}
+	mark := func(lineNumber int) {
+		if current != nil && lineNumber <= current.EndLine+1 {
+			if lineNumber > current.EndLine {
+				current.EndLine = lineNumber
+			}
+			return
+		}
+		flush()
+		current = &entity.LineRange{File: file, StartLine: lineNumber, EndLine: lineNumber}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		text := scanner.Text()
+		switch {
+		case oldLeft <= 0 && newLeft <= 0 && strings.HasPrefix(text, "+++ "):
+			flush()
+			file = diffPath(strings.TrimPrefix(text, "+++ "))
+		case oldLeft <= 0 && newLeft <= 0 && strings.HasPrefix(text, "@@"):
+			flush()
+			match := hunkHeader.FindStringSubmatch(text)
+			if match == nil {
+				return nil, errors.NewValidationError(fmt.Sprintf("invalid diff: malformed hunk header at line %d", lineNumber))
+			}
+			if file == "" {
+				return nil, errors.NewValidationError(fmt.Sprintf("invalid diff: hunk without file header at line %d", lineNumber))
+			}
+			line, _ = strconv.Atoi(match[2])
+			oldLeft, newLeft = hunkCount(match[1]), hunkCount(match[3])
+			hunks++
+		case oldLeft <= 0 && newLeft <= 0:
+			// File headers and other lines between hunks
+		case strings.HasPrefix(text, `\`):
+			// "\ No newline at end of file"
+		case strings.HasPrefix(text, "+"):
+			if file != "/dev/null" {
+				mark(line)
+			}
+			line++
+			newLeft--
+		case strings.HasPrefix(text, "-"):
+			if file != "/dev/null" {
+				mark(line)
+			}
+			oldLeft--
+		default:
+			line++
+			oldLeft--
+			newLeft--
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewValidationError("invalid diff: " + err.Error())
+	}
+	flush()
+
+	if hunks == 0 {
+		return nil, errors.NewValidationError("invalid diff: no hunks found")
+	}
+	return ranges, nil
+}
+
+// hunkCount reads the line count of a hunk header, which is 1 when omitted
+func hunkCount(count string) int {
+	if count == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(count)
+	return n
+}
+
+// diffPath strips the timestamp and the a/ or b/ prefix git adds to file headers
+func diffPath(header string) string {
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	header = strings.Trim(strings.TrimSpace(header), `"`)
+	if header == "/dev/null" {
+		return header
+	}
+	if strings.HasPrefix(header, "a/") || strings.HasPrefix(header, "b/") {
+		header = header[2:]
+	}
+	return header
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// Usage is a use of a package-level declaration or struct field inside another
+// top-level declaration
+type Usage struct {
+	User     types.Object // Function, type, variable or constant whose declaration contains the use
+	Used     types.Object
+	File     string
+	Position *entity.Position
+}
+
+// BuildUsageIndex lists, for every package-level object and field of a set of
+// files, the declarations using it. Functions are left to the call graph.
+func BuildUsageIndex(fset *token.FileSet, files map[string]*ast.File, info *types.Info) map[types.Object][]*Usage {
+	index := make(map[types.Object][]*Usage)
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		collect := func(root ast.Node, user types.Object) {
+			ast.Inspect(root, func(node ast.Node) bool {
+				ident, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				used := OriginObject(info.Uses[ident])
+				if !isIndexedObject(used) || used == user {
+					return true
+				}
+				position := fset.Position(ident.Pos())
+				index[used] = append(index[used], &Usage{
+					User:     user,
+					Used:     used,
+					File:     filePath,
+					Position: &entity.Position{Line: position.Line, Column: position.Column, Offset: position.Offset},
+				})
+				return true
+			})
+		}
+
+		for _, decl := range files[filePath].Decls {
+			for _, declared := range DeclaredObjects(decl, info) {
+				switch node := declared.node.(type) {
+				case *ast.FuncDecl:
+					// A method does not depend on everything its receiver type
+					// holds, only on the fields it uses
+					collect(node.Type, declared.object)
+					if node.Body != nil {
+						collect(node.Body, declared.object)
+					}
+				case *ast.TypeSpec:
+					structType, ok := node.Type.(*ast.StructType)
+					if !ok {
+						collect(node, declared.object)
+						continue
+					}
+					// The types of struct fields are used by the fields themselves
+					if node.TypeParams != nil {
+						collect(node.TypeParams, declared.object)
+					}
+					for _, field := range structType.Fields.List {
+						for _, fieldVar := range fieldObjects(field, info) {
+							collect(field.Type, fieldVar)
+						}
+					}
+				default:
+					collect(node, declared.object)
+				}
+			}
+		}
+	}
+
+	return index
+}
+
+// fieldObjects returns the variables a struct field declares; an embedded field
+// is defined by the identifier of its type
+func fieldObjects(field *ast.Field, info *types.Info) []types.Object {
+	var objects []types.Object
+	for _, name := range field.Names {
+		if obj := info.Defs[name]; obj != nil {
+			objects = append(objects, obj)
+		}
+	}
+	if len(field.Names) > 0 {
+		return objects
+	}
+
+	typeExpr := field.Type
+	for {
+		switch expr := typeExpr.(type) {
+		case *ast.StarExpr:
+			typeExpr = expr.X
+			continue
+		case *ast.IndexExpr:
+			typeExpr = expr.X
+			continue
+		case *ast.IndexListExpr:
+			typeExpr = expr.X
+			continue
+		case *ast.SelectorExpr:
+			typeExpr = expr.Sel
+			continue
+		case *ast.Ident:
+			if obj := info.Defs[expr]; obj != nil {
+				objects = append(objects, obj)
+			}
+		}
+		return objects
+	}
+}
+
+// DeclaredNode pairs a top-level declaration with the object it declares
+type DeclaredNode struct {
+	node   ast.Node
+	object types.Object
+}
+
+// Object returns the declared object
+func (d *DeclaredNode) Object() types.Object {
+	return d.object
+}
+
+// Lines returns the first and last line of the declaration
+func (d *DeclaredNode) Lines(fset *token.FileSet) (int, int) {
+	return fset.Position(d.node.Pos()).Line, fset.Position(d.node.End()).Line
+}
+
+// DeclaredObjects splits a top-level declaration into the objects it declares:
+// a function, or each name of its type and value specs
+func DeclaredObjects(decl ast.Decl, info *types.Info) []*DeclaredNode {
+	var declared []*DeclaredNode
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if obj := info.Defs[decl.Name]; obj != nil {
+			declared = append(declared, &DeclaredNode{node: decl, object: obj})
+		}
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			var names []*ast.Ident
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				names = []*ast.Ident{spec.Name}
+			case *ast.ValueSpec:
+				names = spec.Names
+			}
+			for _, name := range names {
+				if obj := info.Defs[name]; obj != nil {
+					declared = append(declared, &DeclaredNode{node: spec, object: obj})
+				}
+			}
+		}
+	}
+	return declared
+}
+
+// isIndexedObject keeps the types, package-level variables and constants, and
+// struct fields usages are collected for
+func isIndexedObject(obj types.Object) bool {
+	switch obj := obj.(type) {
+	case *types.TypeName, *types.Const:
+		return obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+	case *types.Var:
+		return obj.IsField() || obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+	}
+	return false
+}
@@ -1,11 +1,17 @@
 package usecase
 
 import (
+	"context"
 	"encoding/json"
-	"encoding/xml"
+	"go/token"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"goapianalyzer/internal/adapter/exporter"
 	"goapianalyzer/internal/adapter/parser"
 	"goapianalyzer/internal/core/domain/entity"
 	"goapianalyzer/internal/core/domain/repository"
@@ -20,17 +26,33 @@ import (
 type AnalyzerUsecase struct {
 	repo            repository.AnalysisRepository
 	analyzerService *service.AnalyzerService
+	typeChecker     *parser.TypeChecker
 	logger          logger.Logger
 }
 
+// AnalyzerConfig bounds the derived state the use case keeps per project
+type AnalyzerConfig struct {
+	MaxCheckedProjects int   // Type-checked projects kept for navigation and search
+	MaxProjectBytes    int64 // Estimated bytes of a type-checked project kept for reuse, 0 disables the cap
+}
+
+// NewAnalyzerUsecase creates the analyzer use case; a nil config uses the defaults
 func NewAnalyzerUsecase(
 	repo repository.AnalysisRepository,
 	analyzerService *service.AnalyzerService,
+	config *AnalyzerConfig,
 ) *AnalyzerUsecase {
+	if config == nil {
+		config = &AnalyzerConfig{}
+	}
 	return &AnalyzerUsecase{
 		repo:            repo,
 		analyzerService: analyzerService,
-		logger:          logger.GetLogger(),
+		typeChecker: parser.NewTypeChecker(&parser.TypeCheckerConfig{
+			MaxProjects:     config.MaxCheckedProjects,
+			MaxProjectBytes: config.MaxProjectBytes,
+		}),
+		logger: logger.GetLogger(),
 	}
 }
 
@@ -75,26 +97,66 @@ func (u *AnalyzerUsecase) buildDependencyGraph(analysis *entity.ProjectAnalysis)
 	}
 }
 
-func (u *AnalyzerUsecase) AnalyzeProject(projectPath string, config *entity.AnalysisConfig) (*entity.ProjectAnalysis, error) {
+// AnalysisObserver receives progress notifications of a running analysis
+type AnalysisObserver interface {
+	parser.ScanObserver
+	EndpointDiscovered(endpoint *entity.APIEndpoint)
+}
+
+// nopAnalysisObserver ignores all progress notifications
+type nopAnalysisObserver struct{}
+
+func (nopAnalysisObserver) PhaseChanged(string)                    {}
+func (nopAnalysisObserver) FilesFound(int)                         {}
+func (nopAnalysisObserver) FileScanned(string, error)              {}
+func (nopAnalysisObserver) Diagnostic(string, error)               {}
+func (nopAnalysisObserver) EndpointDiscovered(*entity.APIEndpoint) {}
+
+// AnalyzeProjectContext analyzes a project, reporting progress to observer and
+// stopping between files and phases once ctx is cancelled. With a projectID the
+// result is stored as the next version of that project and the current one is archived.
+func (u *AnalyzerUsecase) AnalyzeProjectContext(
+	ctx context.Context,
+	projectPath string,
+	projectID string,
+	config *entity.AnalysisConfig,
+	observer AnalysisObserver,
+) (*entity.ProjectAnalysis, error) {
+	if observer == nil {
+		observer = nopAnalysisObserver{}
+	}
+
+	// Fail before scanning when the project to version does not exist
+	if projectID != "" {
+		if _, err := u.repo.GetProjectAnalysis(projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
 	u.logger.WithFields(map[string]interface{}{
 		"project_path": projectPath,
+		"project_id":   projectID,
 		"config":       config,
 	}).Info("Starting project analysis")
 
 	// Create file scanner with configuration
 	scanConfig := &parser.ScanConfig{
-		BlacklistFiles:  config.BlacklistFiles,
-		BlacklistDirs:   config.BlacklistDirs,
-		WhitelistFiles:  config.WhitelistFiles,
-		WhitelistDirs:   config.WhitelistDirs,
-		IncludeVendor:   config.IncludeVendor,
-		IncludeTestFile: config.IncludeTestFile,
+		BlacklistFiles:   config.BlacklistFiles,
+		BlacklistDirs:    config.BlacklistDirs,
+		WhitelistFiles:   config.WhitelistFiles,
+		WhitelistDirs:    config.WhitelistDirs,
+		IncludeVendor:    config.IncludeVendor,
+		IncludeTestFile:  config.IncludeTestFile,
+		ExcludeGenerated: config.ExcludeGenerated,
+		BuildContext:     config.BuildContext,
+		Observer:         observer,
 	}
 
 	fileScanner := parser.NewFileScanner(scanConfig)
 
 	// Scan project files
-	projectAnalysis, err := fileScanner.ScanProject(projectPath)
+	projectAnalysis, err := fileScanner.ScanProjectContext(ctx, projectPath)
 	if err != nil {
 		u.logger.WithError(err).Error("Failed to scan project files")
 		return nil, err
@@ -105,47 +167,63 @@ func (u *AnalyzerUsecase) AnalyzeProject(projectPath string, config *entity.Anal
 	for _, fileInfo := range projectAnalysis.Files {
 		if err := astParser.ParseFileDetails(fileInfo); err != nil {
 			u.logger.WithError(err).WithField("file", fileInfo.Path).Warn("Failed to parse file details")
+			observer.Diagnostic(fileInfo.Path, err)
 			continue
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Generate unique project ID
 	projectAnalysis.ID = uuid.New().String()
+	if projectID != "" {
+		projectAnalysis.ID = projectID
+	}
 	projectAnalysis.CreatedAt = time.Now().UTC()
+	projectAnalysis.Config = config
+	projectAnalysis.FileSet = fileScanner.GetFileSet()
 
 	// Use analyzer service to discover API endpoints
+	observer.PhaseChanged(entity.ScanPhaseDiscovery)
 	if err := u.analyzerService.DiscoverAPIEndpoints(projectAnalysis); err != nil {
 		u.logger.WithError(err).Warn("Failed to discover API endpoints")
+		observer.Diagnostic("", err)
+	}
+	for _, endpoint := range projectAnalysis.APIEndpoints {
+		observer.EndpointDiscovered(endpoint)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Build dependency graph manually since BuildDependencyGraph method doesn't exist
+	observer.PhaseChanged(entity.ScanPhaseGraph)
 	u.buildDependencyGraph(projectAnalysis)
 
 	// Generate code nodes from the analysis
 	codeNodes := u.generateCodeNodes(projectAnalysis)
-
-	// Store project analysis
-	if err := u.repo.StoreProjectAnalysis(projectAnalysis); err != nil {
-		u.logger.WithError(err).Error("Failed to store project analysis")
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	// Store all code nodes
-	for _, node := range codeNodes {
-		if err := u.repo.StoreCodeNode(projectAnalysis.ID, node); err != nil {
-			u.logger.WithError(err).WithField("node_id", node.ID).Warn("Failed to store code node")
-		}
+	projectAnalysis.ScanDurationMs = time.Since(start).Milliseconds()
+
+	// Store the analysis with its nodes and endpoints in one step rather than serve
+	// a truncated analysis; a versioned scan archives the current one in the same step
+	if err := u.repo.ReplaceProject(projectAnalysis, codeNodes, projectID != ""); err != nil {
+		u.logger.WithError(err).Error("Failed to store project analysis")
+		return nil, err
 	}
 
-	// Store API endpoints
-	for _, endpoint := range projectAnalysis.APIEndpoints {
-		if err := u.repo.StoreAPIEndpoint(projectAnalysis.ID, endpoint); err != nil {
-			u.logger.WithError(err).WithField("endpoint_id", endpoint.ID).Warn("Failed to store API endpoint")
-		}
+	if err := u.repo.FlushProject(projectAnalysis.ID); err != nil {
+		u.logger.WithError(err).Error("Failed to persist project analysis")
+		return nil, err
 	}
 
 	u.logger.WithFields(map[string]interface{}{
 		"project_id":  projectAnalysis.ID,
+		"version":     projectAnalysis.Version,
 		"files_count": len(projectAnalysis.Files),
 		"nodes_count": len(codeNodes),
 		"apis_count":  len(projectAnalysis.APIEndpoints),
@@ -154,20 +232,184 @@ func (u *AnalyzerUsecase) AnalyzeProject(projectPath string, config *entity.Anal
 	return projectAnalysis, nil
 }
 
+// RescanProject re-analyzes only the files that changed since the last scan
+// and updates the stored nodes and endpoints accordingly
+func (u *AnalyzerUsecase) RescanProject(projectID string) (*entity.RescanSummary, error) {
+	start := time.Now()
+
+	current, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work on a copy so that readers of the stored analysis never see a partial update.
+	// File infos stay shared until they change; whatever changes one replaces it with a copy.
+	analysis := *current
+	analysis.Files = make(map[string]*entity.FileInfo, len(current.Files))
+	for path, fileInfo := range current.Files {
+		analysis.Files[path] = fileInfo
+	}
+	analysis.DependencyGraph = nil
+
+	config := analysis.Config
+	if config == nil {
+		config = &entity.AnalysisConfig{}
+	}
+	if analysis.FileSet == nil {
+		analysis.FileSet = token.NewFileSet()
+	}
+
+	fileScanner := parser.NewFileScanner(&parser.ScanConfig{
+		BlacklistFiles:   config.BlacklistFiles,
+		BlacklistDirs:    config.BlacklistDirs,
+		WhitelistFiles:   config.WhitelistFiles,
+		WhitelistDirs:    config.WhitelistDirs,
+		IncludeVendor:    config.IncludeVendor,
+		IncludeTestFile:  config.IncludeTestFile,
+		ExcludeGenerated: config.ExcludeGenerated,
+		BuildContext:     config.BuildContext,
+		FileSet:          analysis.FileSet,
+	})
+
+	result, err := fileScanner.RescanProject(&analysis)
+	if err != nil {
+		u.logger.WithError(err).Error("Failed to rescan project files")
+		return nil, err
+	}
+
+	// Parse AST details only for files that changed
+	astParser := parser.NewASTParser(fileScanner.GetFileSet())
+	for _, path := range append(append([]string{}, result.Added...), result.Modified...) {
+		if err := astParser.ParseFileDetails(analysis.Files[path]); err != nil {
+			u.logger.WithError(err).WithField("file", path).Warn("Failed to parse file details")
+		}
+	}
+
+	// Endpoint discovery and the dependency graph are cheap compared to parsing and span files
+	if err := u.analyzerService.DiscoverAPIEndpoints(&analysis); err != nil {
+		u.logger.WithError(err).Warn("Failed to discover API endpoints")
+	}
+	u.buildDependencyGraph(&analysis)
+
+	previousNodes, _, err := u.repo.GetAllNodes(projectID, 1, math.MaxInt32, "")
+	if err != nil {
+		return nil, err
+	}
+	codeNodes := u.generateCodeNodes(&analysis)
+
+	summary := &entity.RescanSummary{
+		ProjectID:      projectID,
+		AddedFiles:     result.Added,
+		ModifiedFiles:  result.Modified,
+		RemovedFiles:   result.Removed,
+		UnchangedFiles: result.Unchanged,
+		AddedNodes:     make([]*entity.CodeNodeRef, 0),
+		RemovedNodes:   make([]*entity.CodeNodeRef, 0),
+		ModifiedNodes:  make([]*entity.CodeNodeRef, 0),
+	}
+
+	// Diff nodes by their stable IDs
+	previousByID := make(map[string]*entity.CodeNode, len(previousNodes))
+	for _, node := range previousNodes {
+		previousByID[node.ID] = node
+	}
+	for _, node := range codeNodes {
+		previous, exists := previousByID[node.ID]
+		delete(previousByID, node.ID)
+
+		switch {
+		case !exists:
+			summary.AddedNodes = append(summary.AddedNodes, u.nodeRef(node))
+		case previous.Body != node.Body || !reflect.DeepEqual(previous.Position, node.Position) ||
+			previous.BuildConstraint != node.BuildConstraint:
+			summary.ModifiedNodes = append(summary.ModifiedNodes, u.nodeRef(node))
+		}
+	}
+	for _, node := range previousByID {
+		summary.RemovedNodes = append(summary.RemovedNodes, u.nodeRef(node))
+	}
+
+	// Diff endpoints
+	currentEndpoints := make(map[string]bool)
+	for _, endpoint := range analysis.APIEndpoints {
+		currentEndpoints[endpoint.ID] = true
+	}
+	previousEndpoints := make(map[string]bool)
+	for _, endpoint := range current.APIEndpoints {
+		previousEndpoints[endpoint.ID] = true
+		if !currentEndpoints[endpoint.ID] {
+			summary.RemovedEndpoints++
+		}
+	}
+	for _, endpoint := range analysis.APIEndpoints {
+		if !previousEndpoints[endpoint.ID] {
+			summary.AddedEndpoints++
+		}
+	}
+
+	if err := u.repo.ReplaceProject(&analysis, codeNodes, false); err != nil {
+		u.logger.WithError(err).Error("Failed to store project analysis")
+		return nil, err
+	}
+
+	if err := u.repo.FlushProject(projectID); err != nil {
+		u.logger.WithError(err).Error("Failed to persist project analysis")
+		return nil, err
+	}
+
+	summary.DurationMs = time.Since(start).Milliseconds()
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id":     projectID,
+		"added_files":    len(result.Added),
+		"modified_files": len(result.Modified),
+		"removed_files":  len(result.Removed),
+		"added_nodes":    len(summary.AddedNodes),
+		"removed_nodes":  len(summary.RemovedNodes),
+		"modified_nodes": len(summary.ModifiedNodes),
+	}).Info("Project rescan completed successfully")
+
+	return summary, nil
+}
+
+func (u *AnalyzerUsecase) nodeRef(node *entity.CodeNode) *entity.CodeNodeRef {
+	return &entity.CodeNodeRef{
+		ID:   node.ID,
+		Name: node.Name,
+		Type: node.Type,
+		File: node.File,
+	}
+}
+
 func (u *AnalyzerUsecase) generateCodeNodes(analysis *entity.ProjectAnalysis) []*entity.CodeNode {
 	var nodes []*entity.CodeNode
 
-	for filePath, fileInfo := range analysis.Files {
+	// Walk files in a fixed order so that duplicate names get the same ordinal on every scan
+	filePaths := make([]string, 0, len(analysis.Files))
+	for filePath := range analysis.Files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+	ids := make(map[string]int)
+
+	for _, filePath := range filePaths {
+		fileInfo := analysis.Files[filePath]
+		packagePath := filePackagePath(fileInfo)
+
 		// Generate nodes for functions
 		for _, funcInfo := range fileInfo.Functions {
 			node := &entity.CodeNode{
-				ID:       uuid.New().String(),
-				Name:     funcInfo.Name,
-				Type:     "function",
-				File:     filePath,
-				Package:  fileInfo.PackageName,
-				Body:     funcInfo.Body,
-				Position: funcInfo.Position,
+				SymbolKey:       entity.SymbolKey(packagePath, funcInfo.Receiver, funcInfo.Name),
+				Name:            funcInfo.Name,
+				Type:            "function",
+				File:            filePath,
+				Package:         fileInfo.PackageName,
+				PackagePath:     packagePath,
+				Doc:             funcInfo.Doc,
+				Body:            funcInfo.Body,
+				Position:        funcInfo.Position,
+				Generated:       fileInfo.Generated,
+				BuildConstraint: fileInfo.BuildConstraint,
 				Metadata: map[string]interface{}{
 					"receiver":   funcInfo.Receiver,
 					"is_method":  funcInfo.IsMethod,
@@ -175,90 +417,115 @@ func (u *AnalyzerUsecase) generateCodeNodes(analysis *entity.ProjectAnalysis) []
 					"returns":    funcInfo.Returns,
 					"calls_to":   funcInfo.CallsTo,
 					"used_types": funcInfo.UsedTypes,
+					"complexity": funcInfo.Complexity,
 				},
 			}
+			u.assignNodeID(ids, node)
 			nodes = append(nodes, node)
 		}
 
 		// Generate nodes for structs
 		for _, structInfo := range fileInfo.Structs {
 			node := &entity.CodeNode{
-				ID:      uuid.New().String(),
-				Name:    structInfo.Name,
-				Type:    "struct",
-				File:    filePath,
-				Package: fileInfo.PackageName,
-				Body:    structInfo.Body,
+				SymbolKey:       entity.SymbolKey(packagePath, "", structInfo.Name),
+				Name:            structInfo.Name,
+				Type:            "struct",
+				File:            filePath,
+				Package:         fileInfo.PackageName,
+				PackagePath:     packagePath,
+				Doc:             structInfo.Doc,
+				Body:            structInfo.Body,
+				Generated:       fileInfo.Generated,
+				BuildConstraint: fileInfo.BuildConstraint,
 				Metadata: map[string]interface{}{
 					"fields": structInfo.Fields,
 				},
 			}
+			u.assignNodeID(ids, node)
 			nodes = append(nodes, node)
 		}
 
 		// Generate nodes for interfaces
 		for _, interfaceInfo := range fileInfo.Interfaces {
 			node := &entity.CodeNode{
-				ID:      uuid.New().String(),
-				Name:    interfaceInfo.Name,
-				Type:    "interface",
-				File:    filePath,
-				Package: fileInfo.PackageName,
-				Body:    interfaceInfo.Body,
+				SymbolKey:       entity.SymbolKey(packagePath, "", interfaceInfo.Name),
+				Name:            interfaceInfo.Name,
+				Type:            "interface",
+				File:            filePath,
+				Package:         fileInfo.PackageName,
+				PackagePath:     packagePath,
+				Doc:             interfaceInfo.Doc,
+				Body:            interfaceInfo.Body,
+				Generated:       fileInfo.Generated,
+				BuildConstraint: fileInfo.BuildConstraint,
 				Metadata: map[string]interface{}{
 					"methods": interfaceInfo.Methods,
 				},
 			}
+			u.assignNodeID(ids, node)
 			nodes = append(nodes, node)
 		}
 
 		// Generate nodes for types
 		for _, typeInfo := range fileInfo.Types {
 			node := &entity.CodeNode{
-				ID:      uuid.New().String(),
-				Name:    typeInfo.Name,
-				Type:    "type",
-				File:    filePath,
-				Package: fileInfo.PackageName,
-				Body:    typeInfo.Body,
+				SymbolKey:       entity.SymbolKey(packagePath, "", typeInfo.Name),
+				Name:            typeInfo.Name,
+				Type:            "type",
+				File:            filePath,
+				Package:         fileInfo.PackageName,
+				PackagePath:     packagePath,
+				Doc:             typeInfo.Doc,
+				Body:            typeInfo.Body,
+				Generated:       fileInfo.Generated,
+				BuildConstraint: fileInfo.BuildConstraint,
 				Metadata: map[string]interface{}{
 					"type_definition": typeInfo.Type,
 				},
 			}
+			u.assignNodeID(ids, node)
 			nodes = append(nodes, node)
 		}
 
 		// Generate nodes for variables
 		for _, varInfo := range fileInfo.Variables {
 			node := &entity.CodeNode{
-				ID:      uuid.New().String(),
-				Name:    varInfo.Name,
-				Type:    "variable",
-				File:    filePath,
-				Package: fileInfo.PackageName,
-				Body:    varInfo.Body,
+				SymbolKey:       entity.SymbolKey(packagePath, "", varInfo.Name),
+				Name:            varInfo.Name,
+				Type:            "variable",
+				File:            filePath,
+				Package:         fileInfo.PackageName,
+				PackagePath:     packagePath,
+				Body:            varInfo.Body,
+				Generated:       fileInfo.Generated,
+				BuildConstraint: fileInfo.BuildConstraint,
 				Metadata: map[string]interface{}{
 					"var_type": varInfo.Type,
 					"value":    varInfo.Value,
 				},
 			}
+			u.assignNodeID(ids, node)
 			nodes = append(nodes, node)
 		}
 
 		// Generate nodes for constants
 		for _, constInfo := range fileInfo.Constants {
 			node := &entity.CodeNode{
-				ID:      uuid.New().String(),
-				Name:    constInfo.Name,
-				Type:    "constant",
-				File:    filePath,
-				Package: fileInfo.PackageName,
-				Body:    constInfo.Body,
+				SymbolKey:       entity.SymbolKey(packagePath, "", constInfo.Name),
+				Name:            constInfo.Name,
+				Type:            "constant",
+				File:            filePath,
+				Package:         fileInfo.PackageName,
+				PackagePath:     packagePath,
+				Body:            constInfo.Body,
+				Generated:       fileInfo.Generated,
+				BuildConstraint: fileInfo.BuildConstraint,
 				Metadata: map[string]interface{}{
 					"const_type": constInfo.Type,
 					"value":      constInfo.Value,
 				},
 			}
+			u.assignNodeID(ids, node)
 			nodes = append(nodes, node)
 		}
 	}
@@ -266,12 +533,107 @@ func (u *AnalyzerUsecase) generateCodeNodes(analysis *entity.ProjectAnalysis) []
 	return nodes
 }
 
+// assignNodeID derives the node ID from its kind and symbol key so that IDs
+// survive re-scans. Keys declared more than once (several init functions,
+// blank identifiers, per-platform files analyzed together) are
+// disambiguated by file and ordinal.
+func (u *AnalyzerUsecase) assignNodeID(ids map[string]int, node *entity.CodeNode) {
+	key := node.Type + "|" + node.SymbolKey
+	ids[key]++
+	if ids[key] == 1 {
+		node.ID = entity.NodeID(node.Type, node.SymbolKey)
+		return
+	}
+
+	fileKey := key + "|" + node.File
+	ids[fileKey]++
+	node.ID = entity.NodeID(node.Type, node.SymbolKey+"@"+node.File+"#"+strconv.Itoa(ids[fileKey]))
+}
+
 func (u *AnalyzerUsecase) GetProjectAnalysis(projectID string) (*entity.ProjectAnalysis, error) {
 	return u.repo.GetProjectAnalysis(projectID)
 }
 
+// GetProjectOverview returns the lightweight view of a stored analysis
+func (u *AnalyzerUsecase) GetProjectOverview(projectID string) (*entity.ProjectOverview, error) {
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, nodeCount, err := u.repo.GetAllNodes(projectID, 1, 1, "")
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &entity.ProjectOverview{
+		ProjectSummary: entity.ProjectSummary{
+			ID:             analysis.ID,
+			Version:        analysis.Version,
+			ProjectPath:    analysis.ProjectPath,
+			FileCount:      len(analysis.Files),
+			PackageCount:   len(analysis.Packages),
+			NodeCount:      int(nodeCount),
+			EndpointCount:  len(analysis.APIEndpoints),
+			ScanDurationMs: analysis.ScanDurationMs,
+			Config:         analysis.Config,
+			Pinned:         analysis.Pinned,
+			CreatedAt:      analysis.CreatedAt,
+			UpdatedAt:      analysis.UpdatedAt,
+		},
+		BuildContext: analysis.BuildContext,
+		Modules:      analysis.Modules,
+		Workspace:    analysis.Workspace,
+		Packages:     analysis.Packages,
+		APIEndpoints: analysis.APIEndpoints,
+		Files:        make(map[string]*entity.FileSummary, len(analysis.Files)),
+	}
+
+	for path, fileInfo := range analysis.Files {
+		overview.Files[path] = &entity.FileSummary{
+			Path:            fileInfo.Path,
+			PackageName:     fileInfo.PackageName,
+			ImportPath:      fileInfo.ImportPath,
+			ContentHash:     fileInfo.ContentHash,
+			Size:            fileInfo.Size,
+			Generated:       fileInfo.Generated,
+			BuildConstraint: fileInfo.BuildConstraint,
+			Imports:         len(fileInfo.Imports),
+			Functions:       len(fileInfo.Functions),
+			Types:           len(fileInfo.Types),
+			Structs:         len(fileInfo.Structs),
+			Interfaces:      len(fileInfo.Interfaces),
+		}
+	}
+
+	return overview, nil
+}
+
+// GetFileContent returns a single analyzed file including its source
+func (u *AnalyzerUsecase) GetFileContent(projectID, filePath string) (*entity.FileInfo, error) {
+	if filePath == "" {
+		return nil, errors.NewValidationError("file path cannot be empty")
+	}
+
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, exists := analysis.Files[filePath]
+	if !exists {
+		return nil, errors.NewNotFoundError("file not found: " + filePath)
+	}
+
+	return fileInfo, nil
+}
+
 func (u *AnalyzerUsecase) DeleteProjectAnalysis(projectID string) error {
-	return u.repo.DeleteProjectAnalysis(projectID)
+	if err := u.repo.DeleteProjectAnalysis(projectID); err != nil {
+		return err
+	}
+	u.typeChecker.Forget(projectID)
+	return nil
 }
 
 func (u *AnalyzerUsecase) GetAPIEndpoints(projectID string) ([]*entity.APIEndpoint, error) {
@@ -294,6 +656,94 @@ func (u *AnalyzerUsecase) GetNode(projectID, nodeID string) (*entity.CodeNode, e
 	return u.repo.GetCodeNode(projectID, nodeID)
 }
 
+// ListProjects returns summaries of stored projects whose path contains pathFilter,
+// sorted by created_at or updated_at
+func (u *AnalyzerUsecase) ListProjects(page, limit int, sortBy, order, pathFilter string) ([]*entity.ProjectSummary, int64, error) {
+	switch sortBy {
+	case "", "created", "created_at":
+		sortBy = "created_at"
+	case "updated", "updated_at":
+		sortBy = "updated_at"
+	default:
+		return nil, 0, errors.NewValidationError("unsupported sort field: " + sortBy)
+	}
+
+	switch strings.ToLower(order) {
+	case "", "desc":
+		order = "desc"
+	case "asc":
+		order = "asc"
+	default:
+		return nil, 0, errors.NewValidationError("unsupported sort order: " + order)
+	}
+
+	summaries, err := u.repo.ListProjectSummaries()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filtered := make([]*entity.ProjectSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if pathFilter == "" || strings.Contains(summary.ProjectPath, pathFilter) {
+			filtered = append(filtered, summary)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		a, b := filtered[i].CreatedAt, filtered[j].CreatedAt
+		if sortBy == "updated_at" {
+			a, b = filtered[i].UpdatedAt, filtered[j].UpdatedAt
+		}
+		if a.Equal(b) {
+			return filtered[i].ID < filtered[j].ID
+		}
+		if order == "asc" {
+			return a.Before(b)
+		}
+		return a.After(b)
+	})
+
+	total := int64(len(filtered))
+
+	// Apply pagination
+	start := (page - 1) * limit
+	if start >= len(filtered) {
+		return []*entity.ProjectSummary{}, total, nil
+	}
+
+	end := start + limit
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+
+	return filtered[start:end], total, nil
+}
+
+// PinProject protects a project from eviction, or releases it when pinned is false
+func (u *AnalyzerUsecase) PinProject(projectID string, pinned bool) error {
+	if err := u.repo.PinProject(projectID, pinned); err != nil {
+		return err
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"pinned":     pinned,
+	}).Info("Project pin state changed")
+
+	return nil
+}
+
+// GetStorageStats reports the estimated memory held per project and the retention limits
+func (u *AnalyzerUsecase) GetStorageStats() (*entity.StorageStats, error) {
+	return u.repo.GetStorageStats()
+}
+
+// GetNodesBySymbol resolves a symbol key such as "usecase.AnalyzerUsecase.AnalyzeProject"
+// or its fully qualified form to the matching code nodes
+func (u *AnalyzerUsecase) GetNodesBySymbol(projectID, symbolKey string) ([]*entity.CodeNode, error) {
+	return u.repo.GetCodeNodesBySymbol(projectID, symbolKey)
+}
+
 func (u *AnalyzerUsecase) SearchNodes(projectID, query string, page, limit int) ([]*entity.CodeNode, int64, error) {
 	return u.repo.SearchNodes(projectID, query, page, limit)
 }
@@ -320,6 +770,11 @@ func (u *AnalyzerUsecase) GetProjectStatistics(projectID string) (*entity.Projec
 
 		for _, node := range nodes {
 			stats.NodesByType[node.Type]++
+			if node.Generated {
+				stats.GeneratedNodes++
+			} else {
+				stats.HandwrittenNodes++
+			}
 		}
 	}
 
@@ -328,6 +783,11 @@ func (u *AnalyzerUsecase) GetProjectStatistics(projectID string) (*entity.Projec
 	for _, fileInfo := range analysis.Files {
 		ext := u.getFileExtension(fileInfo.Path)
 		stats.FilesByExtension[ext]++
+		if fileInfo.Generated {
+			stats.GeneratedFiles++
+		} else {
+			stats.HandwrittenFiles++
+		}
 	}
 
 	return stats, nil
@@ -366,7 +826,10 @@ func (u *AnalyzerUsecase) ExportAnalysis(projectID, format string) (string, erro
 		data, err := yaml.Marshal(analysis)
 		return string(data), err
 	case "xml":
-		data, err := xml.MarshalIndent(analysis, "", "  ")
+		data, err := exporter.MarshalAnalysisXML(analysis)
+		return string(data), err
+	case "html":
+		data, err := u.renderHTMLReport(analysis)
 		return string(data), err
 	default:
 		return "", errors.NewValidationError("unsupported format: " + format)
@@ -397,7 +860,7 @@ func (u *AnalyzerUsecase) ExportAPIAnalysis(projectID, apiID, format string) (st
 		data, err := yaml.Marshal(exportData)
 		return string(data), err
 	case "xml":
-		data, err := xml.MarshalIndent(exportData, "", "  ")
+		data, err := exporter.MarshalAPIAnalysisXML(endpoint, nodes)
 		return string(data), err
 	default:
 		return "", errors.NewValidationError("unsupported format: " + format)
@@ -413,6 +876,21 @@ func (u *AnalyzerUsecase) GetDependencyGraph(projectID string) (*entity.Dependen
 	return analysis.DependencyGraph, nil
 }
 
+// GetModuleDependencies returns the modules, workspace and required module
+// versions recorded for a project
+func (u *AnalyzerUsecase) GetModuleDependencies(projectID string) (map[string]interface{}, error) {
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"modules":      analysis.Modules,
+		"workspace":    analysis.Workspace,
+		"dependencies": analysis.ModuleDependencies,
+	}, nil
+}
+
 func (u *AnalyzerUsecase) GetAPIDependencies(projectID, apiID string) ([]*entity.Dependency, error) {
 	graph, err := u.GetDependencyGraph(projectID)
 	if err != nil {
@@ -421,6 +899,9 @@ func (u *AnalyzerUsecase) GetAPIDependencies(projectID, apiID string) ([]*entity
 
 	// Find dependencies for the specific API
 	var dependencies []*entity.Dependency
+	if graph == nil {
+		return dependencies, nil
+	}
 	for _, dep := range graph.Dependencies {
 		if dep.From == apiID || dep.To == apiID {
 			dependencies = append(dependencies, dep)
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"path"
+	"strings"
+
+	"goapianalyzer/internal/adapter/exporter"
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+	"goapianalyzer/pkg/errors"
+)
+
+// API documentation formats
+const (
+	DocsFormatMarkdown = "markdown" // A single Markdown document
+	DocsFormatZip      = "zip"      // A zip archive of Markdown pages, one per router group
+)
+
+// ExportAPIDocs generates a Markdown API reference of a project, with a page per
+// router group and an index page, either as one document or as a zip archive
+func (u *AnalyzerUsecase) ExportAPIDocs(projectID, format string) ([]byte, error) {
+	format = strings.ToLower(format)
+	if format != DocsFormatMarkdown && format != DocsFormatZip {
+		return nil, errors.NewValidationError("unsupported docs format: " + format)
+	}
+
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+	docs := u.buildAPIDocs(analysis)
+
+	var data []byte
+	if format == DocsFormatMarkdown {
+		data = exporter.RenderMarkdownDocument(docs)
+	} else if data, err = exporter.ZipArchive(exporter.RenderMarkdownDocs(docs)); err != nil {
+		return nil, err
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"format":     format,
+		"endpoints":  len(analysis.APIEndpoints),
+		"bytes":      len(data),
+	}).Info("API docs generated")
+
+	return data, nil
+}
+
+// buildAPIDocs places the endpoints of an analysis in the router groups they are
+// registered on. Groups without endpoints in their subtree are left out.
+func (u *AnalyzerUsecase) buildAPIDocs(analysis *entity.ProjectAnalysis) *exporter.APIDocs {
+	endpoints := make(map[string]*entity.APIEndpoint, len(analysis.APIEndpoints))
+	for _, endpoint := range analysis.APIEndpoints {
+		endpoints[endpoint.ID] = endpoint
+	}
+	placed := make(map[string]bool)
+
+	var convert func(group *service.RouterGroup) *exporter.DocGroup
+	convert = func(group *service.RouterGroup) *exporter.DocGroup {
+		docGroup := &exporter.DocGroup{
+			Name: group.VarName,
+			Path: group.FullPath,
+			File: group.File,
+			Line: group.LineNumber,
+		}
+		for _, route := range group.Routes {
+			id := entity.EndpointID(route.Method, route.FullPath)
+			if endpoint, exists := endpoints[id]; exists && !placed[id] {
+				placed[id] = true
+				docGroup.Endpoints = append(docGroup.Endpoints, u.docEndpoint(analysis, endpoint))
+			}
+		}
+		for _, child := range group.Children {
+			if docChild := convert(child); docChild != nil {
+				docGroup.Groups = append(docGroup.Groups, docChild)
+			}
+		}
+		if len(docGroup.Endpoints) == 0 && len(docGroup.Groups) == 0 {
+			return nil
+		}
+		return docGroup
+	}
+
+	docs := &exporter.APIDocs{Title: path.Base(analysis.ProjectPath)}
+	for _, group := range u.analyzerService.RouterGroups(analysis) {
+		if docGroup := convert(group); docGroup != nil {
+			docs.Groups = append(docs.Groups, docGroup)
+		}
+	}
+
+	// Endpoints found by other means than the router file of a group
+	other := &exporter.DocGroup{Path: "/"}
+	for _, endpoint := range analysis.APIEndpoints {
+		if !placed[endpoint.ID] {
+			other.Endpoints = append(other.Endpoints, u.docEndpoint(analysis, endpoint))
+		}
+	}
+	if len(other.Endpoints) > 0 {
+		docs.Groups = append(docs.Groups, other)
+	}
+
+	return docs
+}
+
+// docEndpoint adds the handler doc comment and the body schemas to an endpoint
+func (u *AnalyzerUsecase) docEndpoint(analysis *entity.ProjectAnalysis, endpoint *entity.APIEndpoint) *exporter.DocEndpoint {
+	docEndpoint := &exporter.DocEndpoint{APIEndpoint: endpoint}
+	if endpoint.HandlerSymbol != "" {
+		if nodes, err := u.repo.GetCodeNodesBySymbol(analysis.ID, endpoint.HandlerSymbol); err == nil {
+			for _, node := range nodes {
+				if node.Type == "function" {
+					docEndpoint.Doc = node.Doc
+					break
+				}
+			}
+		}
+	}
+
+	if endpoint.RequestType != "" {
+		docEndpoint.Request = u.docSchema(analysis, endpoint.RequestType)
+	}
+	for _, typeName := range endpoint.ResponseTypes {
+		docEndpoint.Responses = append(docEndpoint.Responses, u.docSchema(analysis, typeName))
+	}
+	return docEndpoint
+}
+
+func (u *AnalyzerUsecase) docSchema(analysis *entity.ProjectAnalysis, typeName string) *exporter.DocSchema {
+	schema := &exporter.DocSchema{Type: typeName}
+	for _, field := range u.analyzerService.ContractFields(analysis, typeName, "json") {
+		schema.Fields = append(schema.Fields, &exporter.DocField{Name: field.Name, Type: field.Type, Required: field.Required})
+	}
+	return schema
+}
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+)
+
+// DetectBreakingChanges classifies the API differences between two versions of a
+// project as breaking, potentially breaking or compatible. Versions default as in
+// DiffProjectVersions.
+func (u *AnalyzerUsecase) DetectBreakingChanges(projectID string, from, to int) (*entity.BreakingChangeReport, error) {
+	fromAnalysis, toAnalysis, err := u.resolveVersions(projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.BreakingChangeReport{
+		ProjectID:   projectID,
+		FromVersion: fromAnalysis.Version,
+		ToVersion:   toAnalysis.Version,
+		Summary:     &entity.BreakingChangeSummary{},
+		Changes:     make([]*entity.APIChange, 0),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	previous := make(map[string]*entity.APIEndpoint, len(fromAnalysis.APIEndpoints))
+	for _, endpoint := range fromAnalysis.APIEndpoints {
+		previous[endpoint.ID] = endpoint
+	}
+
+	var added []*entity.APIEndpoint
+	for _, endpoint := range toAnalysis.APIEndpoints {
+		old, exists := previous[endpoint.ID]
+		if !exists {
+			added = append(added, endpoint)
+			continue
+		}
+		delete(previous, endpoint.ID)
+		u.compareEndpointContracts(report, fromAnalysis, toAnalysis, old, endpoint)
+	}
+
+	removed := make([]*entity.APIEndpoint, 0, len(previous))
+	for _, endpoint := range previous {
+		removed = append(removed, endpoint)
+	}
+	sortEndpoints(removed)
+	sortEndpoints(added)
+	u.classifyMovedEndpoints(report, removed, added)
+
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		a, b := report.Changes[i], report.Changes[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) < severityRank(b.Severity)
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	for _, change := range report.Changes {
+		switch change.Severity {
+		case entity.SeverityBreaking:
+			report.Summary.Breaking++
+		case entity.SeverityWarning:
+			report.Summary.Warning++
+		default:
+			report.Summary.Info++
+		}
+	}
+
+	report.Breaking = report.Summary.Breaking > 0
+	switch {
+	case report.Summary.Breaking > 0:
+		report.ExitCode = entity.ExitCodeBreaking
+	case report.Summary.Warning > 0:
+		report.ExitCode = entity.ExitCodeWarning
+	default:
+		report.ExitCode = entity.ExitCodeCompatible
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id":   projectID,
+		"from_version": report.FromVersion,
+		"to_version":   report.ToVersion,
+		"breaking":     report.Summary.Breaking,
+		"warnings":     report.Summary.Warning,
+	}).Info("Breaking change detection completed")
+
+	return report, nil
+}
+
+// classifyMovedEndpoints pairs removed and added endpoints served by the same
+// handler, which are method or path changes rather than unrelated endpoints
+func (u *AnalyzerUsecase) classifyMovedEndpoints(report *entity.BreakingChangeReport, removed, added []*entity.APIEndpoint) {
+	matched := make(map[*entity.APIEndpoint]bool)
+
+	for _, old := range removed {
+		var match *entity.APIEndpoint
+		for _, endpoint := range added {
+			if matched[endpoint] {
+				continue
+			}
+			samePath := entity.NormalizeRoutePath(old.Path) == entity.NormalizeRoutePath(endpoint.Path)
+			sameHandler := old.HandlerSymbol != "" && old.HandlerSymbol == endpoint.HandlerSymbol
+			if (samePath && old.Method != endpoint.Method) || sameHandler {
+				match = endpoint
+				break
+			}
+		}
+
+		if match == nil {
+			report.Changes = append(report.Changes, &entity.APIChange{
+				Severity: entity.SeverityBreaking,
+				Kind:     "endpoint_removed",
+				Method:   old.Method,
+				Path:     old.Path,
+				Message:  fmt.Sprintf("%s %s was removed", old.Method, old.Path),
+			})
+			continue
+		}
+		matched[match] = true
+
+		switch {
+		case old.Method != match.Method:
+			report.Changes = append(report.Changes, &entity.APIChange{
+				Severity: entity.SeverityBreaking,
+				Kind:     "method_changed",
+				Method:   old.Method,
+				Path:     old.Path,
+				From:     old.Method + " " + old.Path,
+				To:       match.Method + " " + match.Path,
+				Message:  fmt.Sprintf("%s %s is now served as %s %s", old.Method, old.Path, match.Method, match.Path),
+			})
+		case len(match.PathParams) > len(old.PathParams):
+			report.Changes = append(report.Changes, &entity.APIChange{
+				Severity: entity.SeverityBreaking,
+				Kind:     "path_param_added",
+				Method:   old.Method,
+				Path:     old.Path,
+				Location: "path",
+				From:     old.Path,
+				To:       match.Path,
+				Message:  fmt.Sprintf("%s %s now requires path parameters %s", old.Method, old.Path, strings.Join(match.PathParams, ", ")),
+			})
+		default:
+			report.Changes = append(report.Changes, &entity.APIChange{
+				Severity: entity.SeverityBreaking,
+				Kind:     "path_changed",
+				Method:   old.Method,
+				Path:     old.Path,
+				From:     old.Path,
+				To:       match.Path,
+				Message:  fmt.Sprintf("%s %s moved to %s", old.Method, old.Path, match.Path),
+			})
+		}
+	}
+
+	for _, endpoint := range added {
+		if matched[endpoint] {
+			continue
+		}
+		report.Changes = append(report.Changes, &entity.APIChange{
+			Severity: entity.SeverityInfo,
+			Kind:     "endpoint_added",
+			Method:   endpoint.Method,
+			Path:     endpoint.Path,
+			Message:  fmt.Sprintf("%s %s was added", endpoint.Method, endpoint.Path),
+		})
+	}
+}
+
+// compareEndpointContracts classifies parameter and body changes of an endpoint present in both versions
+func (u *AnalyzerUsecase) compareEndpointContracts(
+	report *entity.BreakingChangeReport,
+	fromAnalysis, toAnalysis *entity.ProjectAnalysis,
+	old, endpoint *entity.APIEndpoint,
+) {
+	add := func(severity, kind, location, field, from, to, message string) {
+		report.Changes = append(report.Changes, &entity.APIChange{
+			Severity: severity,
+			Kind:     kind,
+			Method:   endpoint.Method,
+			Path:     endpoint.Path,
+			Location: location,
+			Field:    field,
+			From:     from,
+			To:       to,
+			Message:  message,
+		})
+	}
+
+	// Path parameters keep their position when renamed, so clients are unaffected
+	if strings.Join(old.PathParams, "/") != strings.Join(endpoint.PathParams, "/") {
+		add(entity.SeverityInfo, "path_param_renamed", "path", "", old.Path, endpoint.Path,
+			fmt.Sprintf("path parameters renamed from %s to %s", strings.Join(old.PathParams, ", "), strings.Join(endpoint.PathParams, ", ")))
+	}
+
+	oldQuery := make(map[string]*entity.EndpointParam, len(old.QueryParams))
+	for _, param := range old.QueryParams {
+		oldQuery[param.Name] = param
+	}
+	for _, param := range endpoint.QueryParams {
+		previous, exists := oldQuery[param.Name]
+		delete(oldQuery, param.Name)
+		switch {
+		case !exists && param.Required:
+			add(entity.SeverityBreaking, "query_param_added", "query", param.Name, "", "required",
+				fmt.Sprintf("new required query parameter %q", param.Name))
+		case !exists:
+			add(entity.SeverityInfo, "query_param_added", "query", param.Name, "", "optional",
+				fmt.Sprintf("new optional query parameter %q", param.Name))
+		case param.Required && !previous.Required:
+			add(entity.SeverityBreaking, "query_param_required", "query", param.Name, "optional", "required",
+				fmt.Sprintf("query parameter %q became required", param.Name))
+		case !param.Required && previous.Required:
+			add(entity.SeverityInfo, "query_param_optional", "query", param.Name, "required", "optional",
+				fmt.Sprintf("query parameter %q became optional", param.Name))
+		}
+	}
+	for name := range oldQuery {
+		add(entity.SeverityWarning, "query_param_removed", "query", name, name, "",
+			fmt.Sprintf("query parameter %q is no longer read", name))
+	}
+
+	u.compareFields(add, "request",
+		u.bodyFields(fromAnalysis, []string{old.RequestType}),
+		u.bodyFields(toAnalysis, []string{endpoint.RequestType}))
+	u.compareFields(add, "response",
+		u.bodyFields(fromAnalysis, old.ResponseTypes),
+		u.bodyFields(toAnalysis, endpoint.ResponseTypes))
+}
+
+// bodyFields merges the JSON fields of the given types, keyed by wire name
+func (u *AnalyzerUsecase) bodyFields(analysis *entity.ProjectAnalysis, typeNames []string) map[string]*service.ContractField {
+	fields := make(map[string]*service.ContractField)
+	for _, typeName := range typeNames {
+		if typeName == "" {
+			continue
+		}
+		for _, field := range u.analyzerService.ContractFields(analysis, typeName, "json") {
+			fields[field.Name] = field
+		}
+	}
+	return fields
+}
+
+// compareFields classifies field changes of a request or response body. Clients
+// send requests and read responses, so the same change can differ in severity.
+func (u *AnalyzerUsecase) compareFields(
+	add func(severity, kind, location, field, from, to, message string),
+	location string,
+	oldFields, newFields map[string]*service.ContractField,
+) {
+	request := location == "request"
+
+	// A field whose Go name stayed while its wire name changed was renamed
+	newByGoName := make(map[string]*service.ContractField, len(newFields))
+	for _, field := range newFields {
+		newByGoName[field.GoName] = field
+	}
+	renamedTo := make(map[string]bool)
+
+	for _, name := range sortedFieldNames(oldFields) {
+		old := oldFields[name]
+		field, exists := newFields[name]
+		if !exists {
+			if renamed, ok := newByGoName[old.GoName]; ok && oldFields[renamed.Name] == nil {
+				renamedTo[renamed.Name] = true
+				add(entity.SeverityBreaking, "field_renamed", location, name, name, renamed.Name,
+					fmt.Sprintf("%s field %q was renamed to %q", location, name, renamed.Name))
+				continue
+			}
+
+			severity := entity.SeverityBreaking
+			if request {
+				// Servers ignore unknown request fields, but the client's data is no longer used
+				severity = entity.SeverityWarning
+			}
+			add(severity, "field_removed", location, name, old.Type, "",
+				fmt.Sprintf("%s field %q was removed", location, name))
+			continue
+		}
+
+		if old.Type != field.Type {
+			add(entity.SeverityBreaking, "field_type_changed", location, name, old.Type, field.Type,
+				fmt.Sprintf("%s field %q changed type from %s to %s", location, name, old.Type, field.Type))
+		}
+		if request && field.Required && !old.Required {
+			add(entity.SeverityBreaking, "field_required", location, name, "optional", "required",
+				fmt.Sprintf("request field %q became required", name))
+		}
+		if request && !field.Required && old.Required {
+			add(entity.SeverityInfo, "field_optional", location, name, "required", "optional",
+				fmt.Sprintf("request field %q became optional", name))
+		}
+	}
+
+	for _, name := range sortedFieldNames(newFields) {
+		field := newFields[name]
+		if oldFields[name] != nil || renamedTo[name] {
+			continue
+		}
+		if request && field.Required {
+			add(entity.SeverityBreaking, "field_added", location, name, "", field.Type,
+				fmt.Sprintf("new required request field %q", name))
+			continue
+		}
+		add(entity.SeverityInfo, "field_added", location, name, "", field.Type,
+			fmt.Sprintf("new %s field %q", location, name))
+	}
+}
+
+func sortedFieldNames(fields map[string]*service.ContractField) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case entity.SeverityBreaking:
+		return 0
+	case entity.SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
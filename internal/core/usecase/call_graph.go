@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"time"
+
+	"goapianalyzer/internal/adapter/parser"
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+	"goapianalyzer/pkg/errors"
+)
+
+const (
+	CallTreeCallers = "callers"
+	CallTreeCallees = "callees"
+
+	defaultCallDepth = 3
+	maxCallDepth     = 10
+	maxCallTreeNodes = 2000
+)
+
+// GetCallTree returns the functions calling a function node, or called by it, as a
+// tree of the given depth. Calls through interfaces reach every implementation in
+// the project; a function already on the path from the root is marked as a cycle.
+func (u *AnalyzerUsecase) GetCallTree(projectID, nodeID, direction string, depth int) (*entity.CallTree, error) {
+	if direction != CallTreeCallers && direction != CallTreeCallees {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid call tree direction: %s", direction))
+	}
+	if depth <= 0 {
+		depth = defaultCallDepth
+	}
+	if depth > maxCallDepth {
+		depth = maxCallDepth
+	}
+
+	graph, err := u.loadCallGraph(projectID)
+	if err != nil {
+		return nil, err
+	}
+	root, err := graph.function(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.callTree(root, direction, depth), nil
+}
+
+// FindCallPath finds the shortest chain of calls from one function node to another
+func (u *AnalyzerUsecase) FindCallPath(projectID, fromNodeID, toNodeID string) (*entity.CallPath, error) {
+	graph, err := u.loadCallGraph(projectID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := graph.function(fromNodeID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := graph.function(toNodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := &entity.CallPath{
+		ProjectID:   projectID,
+		From:        graph.nodes.at(graph.declaration(from)),
+		To:          graph.nodes.at(graph.declaration(to)),
+		Steps:       make([]*entity.CallPathStep, 0),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	// Breadth-first search, remembering the call each function was first reached by
+	reachedBy := map[*types.Func]*service.CallEdge{from: nil}
+	queue := []*types.Func{from}
+	for len(queue) > 0 && !path.Found {
+		function := queue[0]
+		queue = queue[1:]
+		if function == to {
+			path.Found = true
+			break
+		}
+		for _, edge := range graph.Callees[function] {
+			if _, seen := reachedBy[edge.Callee]; seen || !graph.isLocal(edge.Callee) {
+				continue
+			}
+			reachedBy[edge.Callee] = edge
+			queue = append(queue, edge.Callee)
+		}
+	}
+	if !path.Found {
+		return path, nil
+	}
+
+	for function := to; ; {
+		step := &entity.CallPathStep{Function: service.FunctionName(function)}
+		step.Node = graph.nodes.at(graph.declaration(function))
+		edge := reachedBy[function]
+		if edge != nil {
+			step.Kind = edge.Kind
+			step.CallSite = &entity.CallSite{File: edge.File, Position: edge.Position}
+		}
+		path.Steps = append([]*entity.CallPathStep{step}, path.Steps...)
+		if edge == nil {
+			break
+		}
+		function = edge.Caller
+	}
+	path.Length = len(path.Steps) - 1
+
+	return path, nil
+}
+
+// projectCallGraph is the call graph of a project with what is needed to map its
+// functions back to code nodes
+type projectCallGraph struct {
+	*service.CallGraph
+	projectID string
+	usecase   *AnalyzerUsecase
+	checked   *parser.CheckedProject
+	nodes     *declarationNodes
+}
+
+func (u *AnalyzerUsecase) loadCallGraph(projectID string) (*projectCallGraph, error) {
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := u.typeChecker.Check(analysis)
+	return &projectCallGraph{
+		CallGraph: service.BuildCallGraph(checked.FileSet, checked.Files, checked.Info),
+		projectID: projectID,
+		usecase:   u,
+		checked:   checked,
+		nodes:     newDeclarationNodes(u, projectID, analysis),
+	}, nil
+}
+
+// function resolves a function node to the function it declares
+func (g *projectCallGraph) function(nodeID string) (*types.Func, error) {
+	node, err := g.usecase.repo.GetCodeNode(g.projectID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node.Type != "function" {
+		return nil, errors.NewValidationError(fmt.Sprintf("node %s is a %s, not a function", nodeID, node.Type))
+	}
+
+	if file := g.checked.Files[node.File]; file != nil {
+		if function, ok := service.DeclaredObject(file, g.checked.Info, node).(*types.Func); ok {
+			return function, nil
+		}
+	}
+	return nil, errors.NewNotFoundError(fmt.Sprintf("declaration of node %s not found in type information", nodeID))
+}
+
+func (g *projectCallGraph) isLocal(obj types.Object) bool {
+	_, _, ok := g.checked.Declaration(obj)
+	return ok
+}
+
+func (g *projectCallGraph) declaration(obj types.Object) (string, *ast.File, token.Pos) {
+	filePath, _, _ := g.checked.Declaration(obj)
+	return filePath, g.checked.Files[filePath], obj.Pos()
+}
+
+// callTree builds the callers or callees tree of a function
+func (g *projectCallGraph) callTree(root *types.Func, direction string, depth int) *entity.CallTree {
+	builder := &callTreeBuilder{
+		graph:     g,
+		direction: direction,
+		depth:     depth,
+		onPath:    map[*types.Func]bool{root: true},
+	}
+	tree := &entity.CallTree{
+		ProjectID:   g.projectID,
+		Direction:   direction,
+		Depth:       depth,
+		Root:        g.treeNode(root),
+		TypeErrors:  g.checked.Errors,
+		GeneratedAt: time.Now().UTC(),
+	}
+	builder.expand(tree.Root, root, 0)
+	tree.Nodes = builder.count + 1
+	tree.Truncated = builder.truncated
+	return tree
+}
+
+func (g *projectCallGraph) treeNode(function *types.Func) *entity.CallTreeNode {
+	return &entity.CallTreeNode{
+		Node:     g.nodes.at(g.declaration(function)),
+		Function: service.FunctionName(function),
+	}
+}
+
+// callTreeBuilder expands a call tree level by level until its depth or size limit
+type callTreeBuilder struct {
+	graph     *projectCallGraph
+	direction string
+	depth     int
+	onPath    map[*types.Func]bool
+	count     int
+	truncated bool
+}
+
+func (b *callTreeBuilder) expand(parent *entity.CallTreeNode, function *types.Func, level int) {
+	if level >= b.depth {
+		return
+	}
+
+	edges := b.graph.Callees[function]
+	if b.direction == CallTreeCallers {
+		edges = b.graph.Callers[function]
+	}
+
+	// One child per function and edge kind, in call order, listing all of its call sites
+	type edgeKey struct {
+		function *types.Func
+		kind     string
+	}
+	children := make(map[edgeKey]*entity.CallTreeNode)
+	var order []edgeKey
+	for _, edge := range edges {
+		other := edge.Callee
+		if b.direction == CallTreeCallers {
+			other = edge.Caller
+		}
+		if !b.graph.isLocal(other) {
+			continue
+		}
+
+		key := edgeKey{function: other, kind: edge.Kind}
+		child, exists := children[key]
+		if !exists {
+			child = b.graph.treeNode(other)
+			child.Kind = edge.Kind
+			children[key] = child
+			order = append(order, key)
+		}
+		child.CallSites = append(child.CallSites, &entity.CallSite{File: edge.File, Position: edge.Position})
+	}
+
+	for _, key := range order {
+		if b.count >= maxCallTreeNodes {
+			b.truncated = true
+			return
+		}
+		b.count++
+
+		child := children[key]
+		parent.Children = append(parent.Children, child)
+		if b.onPath[key.function] {
+			child.Cycle = true
+			continue
+		}
+		b.onPath[key.function] = true
+		b.expand(child, key.function, level+1)
+		delete(b.onPath, key.function)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"go/token"
+
+	"goapianalyzer/internal/adapter/exporter"
+	"goapianalyzer/pkg/errors"
+)
+
+// DefaultClientPackage is the Go package name of generated clients when none is given
+const DefaultClientPackage = "client"
+
+// ExportClientSDK generates typed Go and TypeScript clients of the project API as
+// a zip archive. Request and response types are reproduced along with the project
+// types their fields refer to.
+func (u *AnalyzerUsecase) ExportClientSDK(projectID, packageName string) ([]byte, error) {
+	if packageName == "" {
+		packageName = DefaultClientPackage
+	}
+	if !token.IsIdentifier(packageName) || packageName == "_" || packageName == "main" {
+		return nil, errors.NewValidationError("invalid Go package name: " + packageName)
+	}
+
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+	docs := u.buildAPIDocs(analysis)
+
+	var typeNames []string
+	for _, endpoint := range analysis.APIEndpoints {
+		if endpoint.RequestType != "" {
+			typeNames = append(typeNames, endpoint.RequestType)
+		}
+		typeNames = append(typeNames, endpoint.ResponseTypes...)
+	}
+
+	sdk := &exporter.ClientSDK{Docs: docs, PackageName: packageName}
+	for _, contractType := range u.analyzerService.ContractTypes(analysis, typeNames) {
+		clientType := &exporter.ClientType{
+			Name:       contractType.Name,
+			Package:    contractType.Package,
+			Doc:        contractType.Doc,
+			Struct:     contractType.Struct != nil,
+			Underlying: contractType.Underlying,
+		}
+		if contractType.Struct != nil {
+			clientType.Fields = contractType.Struct.Fields
+		}
+		sdk.Types = append(sdk.Types, clientType)
+	}
+
+	files, err := exporter.RenderClientSDK(sdk)
+	if err != nil {
+		return nil, err
+	}
+	data, err := exporter.ZipArchive(files)
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"package":    packageName,
+		"endpoints":  len(analysis.APIEndpoints),
+		"types":      len(sdk.Types),
+		"bytes":      len(data),
+	}).Info("Client SDK exported")
+
+	return data, nil
+}
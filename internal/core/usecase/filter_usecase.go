@@ -5,6 +5,7 @@ import (
 
 	"goapianalyzer/internal/core/domain/entity"
 	"goapianalyzer/internal/core/domain/repository"
+	"goapianalyzer/internal/core/domain/service"
 	"goapianalyzer/internal/infrastructure/logger"
 	"goapianalyzer/pkg/utils"
 )
@@ -36,6 +37,24 @@ func (u *FilterUsecase) ApplyFilters(projectID string, filters *entity.FilterCon
 	// Apply filters
 	filteredNodes := u.filterNodes(allNodes, filters)
 
+	if filters != nil && filters.Query != "" {
+		query, err := service.ParseNodeQuery(filters.Query)
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := u.newNodeMatcher(projectID, query)
+		if err != nil {
+			return nil, err
+		}
+		queried := make([]*entity.CodeNode, 0, len(filteredNodes))
+		for _, node := range filteredNodes {
+			if matcher.match(query, node) {
+				queried = append(queried, node)
+			}
+		}
+		filteredNodes = queried
+	}
+
 	u.logger.WithFields(map[string]interface{}{
 		"project_id":      projectID,
 		"total_nodes":     len(allNodes),
@@ -110,6 +129,11 @@ func (u *FilterUsecase) nodePassesFilters(node *entity.CodeNode, filters *entity
 		}
 	}
 
+	// Filter by generated code marker
+	if filters.Generated != nil && node.Generated != *filters.Generated {
+		return false
+	}
+
 	// Filter by complexity (if complexity metadata exists)
 	if filters.MinComplexity != nil || filters.MaxComplexity != nil {
 		complexity := u.getNodeComplexity(node)
@@ -144,10 +168,12 @@ func (u *FilterUsecase) containsAny(slice []string, target string) bool {
 func (u *FilterUsecase) getNodeComplexity(node *entity.CodeNode) int {
 	// Try to extract complexity from metadata
 	if node.Metadata != nil {
-		if complexity, exists := node.Metadata["complexity"]; exists {
-			if complexityInt, ok := complexity.(int); ok {
-				return complexityInt
-			}
+		// Snapshots loaded from disk decode numbers as float64
+		switch complexity := node.Metadata["complexity"].(type) {
+		case int:
+			return complexity
+		case float64:
+			return int(complexity)
 		}
 	}
 
@@ -210,6 +236,12 @@ func (u *FilterUsecase) countAppliedFilters(filters *entity.FilterConfig) int {
 	if filters.MaxComplexity != nil {
 		count++
 	}
+	if filters.Generated != nil {
+		count++
+	}
+	if filters.Query != "" {
+		count++
+	}
 
 	return count
 }
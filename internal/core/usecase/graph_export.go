@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"go/types"
+
+	"goapianalyzer/internal/adapter/exporter"
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+)
+
+// Edges of an API dependency graph besides call edges
+const (
+	apiEdgeHandles  = "handles"     // An API to its handler function
+	apiEdgeRoutes   = "routes"      // An API to the file registering it
+	apiEdgeDeclared = "declared_in" // A function to the file declaring it
+)
+
+// ShapeDependencyGraph returns the dependency graph of a project, or the part of
+// it around one API when apiID is set, filtered and collapsed as the options ask
+func (u *AnalyzerUsecase) ShapeDependencyGraph(projectID, apiID string, options *exporter.GraphOptions) (*entity.DependencyGraph, error) {
+	graph, err := u.dependencyGraph(projectID, apiID)
+	if err != nil {
+		return nil, err
+	}
+	return exporter.PrepareGraph(graph, options), nil
+}
+
+// ExportDependencyGraph renders the graph ShapeDependencyGraph selects as DOT,
+// GraphML, Mermaid or Cytoscape JSON
+func (u *AnalyzerUsecase) ExportDependencyGraph(projectID, apiID, format string, options *exporter.GraphOptions) ([]byte, error) {
+	graph, err := u.dependencyGraph(projectID, apiID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := exporter.ExportGraph(graph, format, options)
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"api_id":     apiID,
+		"format":     format,
+		"bytes":      len(data),
+	}).Info("Dependency graph exported")
+
+	return data, nil
+}
+
+// dependencyGraph returns the file and import graph of a project, or the API
+// dependency graph of apiID when it is set
+func (u *AnalyzerUsecase) dependencyGraph(projectID, apiID string) (*entity.DependencyGraph, error) {
+	if apiID == "" {
+		return u.GetDependencyGraph(projectID)
+	}
+
+	endpoint, err := u.repo.GetAPIEndpoint(projectID, apiID)
+	if err != nil {
+		return nil, err
+	}
+	callGraph, err := u.loadCallGraph(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return apiDependencyGraph(callGraph, endpoint), nil
+}
+
+// apiDependencyGraph links an API to its handler, the project functions the
+// handler reaches through calls and the files declaring them. Only the route file
+// is linked when the handler cannot be resolved.
+func apiDependencyGraph(graph *projectCallGraph, endpoint *entity.APIEndpoint) *entity.DependencyGraph {
+	builder := &apiGraphBuilder{
+		graph:     graph,
+		subgraph:  &entity.DependencyGraph{Nodes: make([]*entity.DependencyNode, 0), Dependencies: make([]*entity.Dependency, 0)},
+		functions: make(map[*types.Func]string),
+		added:     make(map[string]bool),
+		edges:     make(map[[3]string]*entity.Dependency),
+	}
+
+	builder.addNode(&entity.DependencyNode{
+		ID:   endpoint.ID,
+		Name: endpoint.Method + " " + endpoint.Path,
+		Type: "api",
+		File: endpoint.File,
+	})
+	if endpoint.File != "" {
+		builder.addEdge(endpoint.ID, builder.fileNode(endpoint.File), apiEdgeRoutes)
+	}
+
+	handler := graph.handler(endpoint)
+	if handler == nil {
+		return builder.subgraph
+	}
+	builder.addEdge(endpoint.ID, builder.functionNode(handler), apiEdgeHandles)
+
+	// Breadth first so that the closest callees are kept when the limit is reached
+	queue := []*types.Func{handler}
+	for len(queue) > 0 && len(builder.functions) < maxCallTreeNodes {
+		function := queue[0]
+		queue = queue[1:]
+		for _, edge := range graph.Callees[function] {
+			if !graph.isLocal(edge.Callee) {
+				continue
+			}
+			_, seen := builder.functions[edge.Callee]
+			if !seen && len(builder.functions) >= maxCallTreeNodes {
+				break
+			}
+			builder.addEdge(builder.functions[function], builder.functionNode(edge.Callee), edge.Kind)
+			if !seen {
+				queue = append(queue, edge.Callee)
+			}
+		}
+	}
+	return builder.subgraph
+}
+
+// apiGraphBuilder adds nodes and edges to an API dependency graph once each;
+// repeated edges strengthen the first one
+type apiGraphBuilder struct {
+	graph     *projectCallGraph
+	subgraph  *entity.DependencyGraph
+	functions map[*types.Func]string
+	added     map[string]bool
+	edges     map[[3]string]*entity.Dependency
+}
+
+func (b *apiGraphBuilder) addNode(node *entity.DependencyNode) {
+	if b.added[node.ID] {
+		return
+	}
+	b.added[node.ID] = true
+	b.subgraph.Nodes = append(b.subgraph.Nodes, node)
+}
+
+func (b *apiGraphBuilder) addEdge(from, to, kind string) {
+	key := [3]string{from, to, kind}
+	if edge, exists := b.edges[key]; exists {
+		if edge.Strength < 10 {
+			edge.Strength++
+		}
+		return
+	}
+	edge := &entity.Dependency{From: from, To: to, Type: kind, Strength: 5}
+	b.edges[key] = edge
+	b.subgraph.Dependencies = append(b.subgraph.Dependencies, edge)
+}
+
+// fileNode adds a file under the ID it has in the project dependency graph
+func (b *apiGraphBuilder) fileNode(filePath string) string {
+	node := &entity.DependencyNode{ID: "file_" + filePath, Name: filePath, Type: "file", File: filePath}
+	if fileInfo := b.graph.nodes.analysis.Files[filePath]; fileInfo != nil {
+		node.Name = fileInfo.Path
+		node.Package = fileInfo.PackageName
+	}
+	b.addNode(node)
+	return node.ID
+}
+
+// functionNode adds a function under the ID of its code node, linked to its file
+func (b *apiGraphBuilder) functionNode(function *types.Func) string {
+	if id, exists := b.functions[function]; exists {
+		return id
+	}
+
+	filePath, file, pos := b.graph.declaration(function)
+	node := &entity.DependencyNode{
+		ID:   "func_" + service.FunctionName(function),
+		Name: service.FunctionName(function),
+		Type: "function",
+		File: filePath,
+	}
+	if function.Pkg() != nil {
+		node.Package = function.Pkg().Name()
+	}
+	if ref := b.graph.nodes.at(filePath, file, pos); ref != nil {
+		node.ID = ref.ID
+	}
+	b.functions[function] = node.ID
+	b.addNode(node)
+	if filePath != "" {
+		b.addEdge(node.ID, b.fileNode(filePath), apiEdgeDeclared)
+	}
+	return node.ID
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"goapianalyzer/internal/adapter/exporter"
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// reportCallDepth is the depth of the handler call trees of HTML reports
+const reportCallDepth = 3
+
+// renderHTMLReport gathers the nodes and handler call trees of an analysis into a
+// self-contained HTML report
+func (u *AnalyzerUsecase) renderHTMLReport(analysis *entity.ProjectAnalysis) ([]byte, error) {
+	nodes, _, err := u.repo.GetAllNodes(analysis.ID, 1, math.MaxInt32, "")
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := u.loadCallGraph(analysis.ID)
+	if err != nil {
+		return nil, err
+	}
+	handlers, err := u.endpointHandlers(analysis.ID, graph)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &exporter.Report{
+		Analysis:    analysis,
+		Nodes:       nodes,
+		CallTrees:   make(map[string]*entity.CallTree),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for function, endpoints := range handlers {
+		tree := graph.callTree(function, CallTreeCallees, reportCallDepth)
+		for _, endpoint := range endpoints {
+			report.CallTrees[endpoint.ID] = tree
+		}
+	}
+
+	data, err := exporter.RenderHTMLReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": analysis.ID,
+		"endpoints":  len(analysis.APIEndpoints),
+		"call_trees": len(report.CallTrees),
+		"bytes":      len(data),
+	}).Info("HTML report rendered")
+
+	return data, nil
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+	"goapianalyzer/pkg/errors"
+)
+
+// Edges of an impact path besides call edges
+const (
+	impactUsage = "usage" // A declaration uses a changed type, variable, constant or field
+	impactField = "field" // A field of a changed struct
+)
+
+// impactEdge is how an impact path reaches a declaration
+type impactEdge struct {
+	from types.Object
+	kind string
+	site *entity.CallSite
+}
+
+// AnalyzeImpact finds the API endpoints a change may affect by walking the reverse
+// call graph and type usages from the changed declarations up to route handlers.
+// Each endpoint comes with the shortest path explaining its impact.
+func (u *AnalyzerUsecase) AnalyzeImpact(projectID string, changes *entity.ChangeSet) (*entity.ImpactAnalysis, error) {
+	if len(changes.NodeIDs) == 0 && len(changes.Ranges) == 0 && strings.TrimSpace(changes.Diff) == "" {
+		return nil, errors.NewValidationError("change set is empty: give node_ids, ranges or a diff")
+	}
+
+	ranges := make([]*entity.LineRange, 0, len(changes.Ranges))
+	for _, lineRange := range changes.Ranges {
+		if lineRange.EndLine == 0 {
+			lineRange.EndLine = lineRange.StartLine
+		}
+		if lineRange.File == "" || lineRange.StartLine < 1 || lineRange.EndLine < lineRange.StartLine {
+			return nil, errors.NewValidationError(fmt.Sprintf("invalid line range %s:%d-%d", lineRange.File, lineRange.StartLine, lineRange.EndLine))
+		}
+		ranges = append(ranges, lineRange)
+	}
+	if strings.TrimSpace(changes.Diff) != "" {
+		diffRanges, err := service.ParseUnifiedDiff(changes.Diff)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, diffRanges...)
+	}
+
+	graph, err := u.loadCallGraph(projectID)
+	if err != nil {
+		return nil, err
+	}
+	checked := graph.checked
+
+	result := &entity.ImpactAnalysis{
+		ProjectID:   projectID,
+		Changed:     make([]*entity.ChangedSymbol, 0),
+		Endpoints:   make([]*entity.EndpointImpact, 0),
+		TypeErrors:  checked.Errors,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	// Changed declarations start the search
+	reachedBy := make(map[types.Object]*impactEdge)
+	var queue []types.Object
+	addChanged := func(obj types.Object) {
+		obj = service.OriginObject(obj)
+		if _, exists := reachedBy[obj]; exists {
+			return
+		}
+		reachedBy[obj] = nil
+		queue = append(queue, obj)
+		result.Changed = append(result.Changed, &entity.ChangedSymbol{
+			Node:   graph.nodes.at(graph.declaration(obj)),
+			Symbol: graph.symbolName(obj),
+			Kind:   service.ObjectKind(obj),
+		})
+	}
+
+	for _, nodeID := range changes.NodeIDs {
+		node, err := u.repo.GetCodeNode(projectID, nodeID)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				result.Unresolved = append(result.Unresolved, "node "+nodeID)
+				continue
+			}
+			return nil, err
+		}
+		var obj types.Object
+		if file := checked.Files[node.File]; file != nil {
+			obj = service.DeclaredObject(file, checked.Info, node)
+		}
+		if obj == nil {
+			result.Unresolved = append(result.Unresolved, "node "+nodeID)
+			continue
+		}
+		addChanged(obj)
+	}
+
+	for _, lineRange := range ranges {
+		description := fmt.Sprintf("%s:%d-%d", lineRange.File, lineRange.StartLine, lineRange.EndLine)
+		filePath := resolveProjectFile(checked.Files, lineRange.File)
+		if filePath == "" {
+			result.Unresolved = append(result.Unresolved, description)
+			continue
+		}
+
+		matched := false
+		for _, decl := range checked.Files[filePath].Decls {
+			for _, declared := range service.DeclaredObjects(decl, checked.Info) {
+				start, end := declared.Lines(checked.FileSet)
+				if start <= lineRange.EndLine && lineRange.StartLine <= end {
+					addChanged(declared.Object())
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			result.Unresolved = append(result.Unresolved, description)
+		}
+	}
+
+	handlers, err := u.endpointHandlers(projectID, graph)
+	if err != nil {
+		return nil, err
+	}
+	usages := service.BuildUsageIndex(checked.FileSet, checked.Files, checked.Info)
+
+	// Fields of changed structs are reached through the struct itself
+	for _, obj := range queue {
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if structType, ok := typeName.Type().Underlying().(*types.Struct); ok {
+			for i := 0; i < structType.NumFields(); i++ {
+				field := structType.Field(i)
+				if _, exists := reachedBy[field]; !exists {
+					reachedBy[field] = &impactEdge{from: obj, kind: impactField}
+					queue = append(queue, field)
+				}
+			}
+		}
+	}
+
+	visit := func(obj types.Object, edge *impactEdge) {
+		obj = service.OriginObject(obj)
+		if _, exists := reachedBy[obj]; exists || !graph.isLocal(obj) {
+			return
+		}
+		reachedBy[obj] = edge
+		queue = append(queue, obj)
+	}
+
+	for len(queue) > 0 {
+		obj := queue[0]
+		queue = queue[1:]
+
+		function, isFunction := obj.(*types.Func)
+		if !isFunction {
+			for _, usage := range usages[obj] {
+				visit(usage.User, &impactEdge{
+					from: obj,
+					kind: impactUsage,
+					site: &entity.CallSite{File: usage.File, Position: usage.Position},
+				})
+			}
+			continue
+		}
+
+		for _, endpoint := range handlers[function] {
+			path := impactPath(graph, reachedBy, function)
+			result.Endpoints = append(result.Endpoints, &entity.EndpointImpact{
+				Endpoint: endpoint,
+				Changed:  path[0].Symbol,
+				Path:     path,
+			})
+		}
+		for _, edge := range graph.Callers[function] {
+			visit(edge.Caller, &impactEdge{
+				from: function,
+				kind: edge.Kind,
+				site: &entity.CallSite{File: edge.File, Position: edge.Position},
+			})
+		}
+	}
+
+	for obj := range reachedBy {
+		if field, ok := obj.(*types.Var); !ok || !field.IsField() {
+			result.ImpactedNodes++
+		}
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"changed":    len(result.Changed),
+		"endpoints":  len(result.Endpoints),
+		"unresolved": len(result.Unresolved),
+	}).Info("Change impact analyzed")
+
+	return result, nil
+}
+
+// endpointHandlers maps the handler functions of a project to the endpoints they serve
+func (u *AnalyzerUsecase) endpointHandlers(projectID string, graph *projectCallGraph) (map[*types.Func][]*entity.APIEndpoint, error) {
+	endpoints, err := u.repo.GetAPIEndpoints(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+
+	handlers := make(map[*types.Func][]*entity.APIEndpoint)
+	for _, endpoint := range endpoints {
+		if function := graph.handler(endpoint); function != nil {
+			handlers[function] = append(handlers[function], endpoint)
+		}
+	}
+	return handlers, nil
+}
+
+// handler resolves the handler function of an endpoint, or returns nil when the
+// endpoint has none or it is not declared in the project
+func (g *projectCallGraph) handler(endpoint *entity.APIEndpoint) *types.Func {
+	if endpoint.HandlerSymbol == "" {
+		return nil
+	}
+	nodes, err := g.usecase.repo.GetCodeNodesBySymbol(g.projectID, endpoint.HandlerSymbol)
+	if err != nil {
+		return nil
+	}
+	for _, node := range nodes {
+		if node.Type != "function" || g.checked.Files[node.File] == nil {
+			continue
+		}
+		if function, ok := service.DeclaredObject(g.checked.Files[node.File], g.checked.Info, node).(*types.Func); ok {
+			return function
+		}
+	}
+	return nil
+}
+
+// impactPath follows the edges an object was reached by back to a changed declaration
+func impactPath(graph *projectCallGraph, reachedBy map[types.Object]*impactEdge, obj types.Object) []*entity.ImpactStep {
+	var path []*entity.ImpactStep
+	for {
+		step := &entity.ImpactStep{
+			Node:   graph.nodes.at(graph.declaration(obj)),
+			Symbol: graph.symbolName(obj),
+		}
+		edge := reachedBy[obj]
+		if edge != nil {
+			step.Kind = edge.kind
+			step.Site = edge.site
+		}
+		path = append([]*entity.ImpactStep{step}, path...)
+		if edge == nil {
+			return path
+		}
+		obj = edge.from
+	}
+}
+
+// resolveProjectFile matches a path given by a client, possibly relative to a
+// parent directory of the project, to a file of the project
+func resolveProjectFile(files map[string]*ast.File, filePath string) string {
+	filePath = strings.TrimPrefix(filePath, "./")
+	if _, exists := files[filePath]; exists {
+		return filePath
+	}
+
+	match := ""
+	for candidate := range files {
+		if strings.HasSuffix(filePath, "/"+candidate) || strings.HasSuffix(candidate, "/"+filePath) {
+			if match != "" {
+				return "" // Ambiguous
+			}
+			match = candidate
+		}
+	}
+	return match
+}
+
+// symbolName names a declaration as in impact paths: fields are qualified by their struct
+func (g *projectCallGraph) symbolName(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return service.FunctionName(obj)
+	case *types.Var:
+		if _, file, pos := g.declaration(obj); obj.IsField() && file != nil {
+			if _, structName, ok := service.DeclarationAt(file, pos); ok {
+				return structName + "." + obj.Name()
+			}
+		}
+	}
+	return obj.Name()
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"time"
+
+	"goapianalyzer/internal/adapter/parser"
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+	"goapianalyzer/pkg/errors"
+)
+
+// FindReferences lists every use of the declaration of a code node across the
+// project: calls, type usages, embeddings, field accesses and assignments
+func (u *AnalyzerUsecase) FindReferences(projectID, nodeID string) (*entity.ReferenceResult, error) {
+	node, err := u.repo.GetCodeNode(projectID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := u.typeChecker.Check(analysis)
+	file := checked.Files[node.File]
+	if file == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("source file %s of node %s not found", node.File, nodeID))
+	}
+	target := service.DeclaredObject(file, checked.Info, node)
+	if target == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("declaration of node %s not found in type information", nodeID))
+	}
+
+	nodes := newDeclarationNodes(u, projectID, analysis)
+	result := &entity.ReferenceResult{
+		ProjectID:   projectID,
+		NodeID:      nodeID,
+		Symbol:      u.describeObject(checked, nodes, target),
+		References:  make([]*entity.SymbolReference, 0),
+		Kinds:       make(map[string]int),
+		TypeErrors:  checked.Errors,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	paths := make([]string, 0, len(checked.Files))
+	for filePath := range checked.Files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		astFile := checked.Files[filePath]
+		tokenFile := checked.FileSet.File(astFile.Pos())
+		references := service.FindReferences(checked.FileSet, astFile, checked.Info, filePath, analysis.Files[filePath].Content, target)
+		for _, reference := range references {
+			if ref := nodes.at(filePath, astFile, tokenFile.Pos(reference.Position.Offset)); ref != nil {
+				reference.NodeID = ref.ID
+			}
+			result.Kinds[reference.Kind]++
+		}
+		result.References = append(result.References, references...)
+	}
+	result.Total = len(result.References)
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"node_id":    nodeID,
+		"references": result.Total,
+	}).Info("References found")
+
+	return result, nil
+}
+
+// FindDefinition resolves the identifier at a 1-based line and column of a project
+// file to its declaration, and to the code node holding it when declared in the project
+func (u *AnalyzerUsecase) FindDefinition(projectID, filePath string, line, column int) (*entity.SymbolDefinition, error) {
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := u.typeChecker.Check(analysis)
+	file := checked.Files[filePath]
+	if file == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("file not found: %s", filePath))
+	}
+
+	ident, err := service.IdentAt(checked.FileSet, file, line, column)
+	if err != nil {
+		return nil, err
+	}
+	obj := checked.Info.Uses[ident]
+	if obj == nil {
+		obj = checked.Info.Defs[ident]
+	}
+	if obj == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("identifier %s at %d:%d has no declaration", ident.Name, line, column))
+	}
+
+	return u.describeObject(checked, newDeclarationNodes(u, projectID, analysis), obj), nil
+}
+
+func (u *AnalyzerUsecase) describeObject(checked *parser.CheckedProject, nodes *declarationNodes, obj types.Object) *entity.SymbolDefinition {
+	definition := &entity.SymbolDefinition{
+		Name: obj.Name(),
+		Kind: service.ObjectKind(obj),
+	}
+	if obj.Pkg() != nil {
+		definition.Package = obj.Pkg().Path()
+	}
+
+	// Types are written as in the source of the declaring package
+	qualifier := func(pkg *types.Package) string {
+		if pkg == obj.Pkg() {
+			return ""
+		}
+		return pkg.Name()
+	}
+	switch obj := obj.(type) {
+	case *types.PkgName:
+		definition.Package = obj.Imported().Path()
+	case *types.TypeName:
+		definition.Type = types.TypeString(obj.Type().Underlying(), qualifier)
+	case *types.Func, *types.Var, *types.Const:
+		definition.Type = types.TypeString(obj.Type(), qualifier)
+	}
+
+	filePath, position, ok := checked.Declaration(obj)
+	if !ok {
+		definition.External = true
+		return definition
+	}
+
+	definition.File = filePath
+	definition.Position = &entity.Position{Line: position.Line, Column: position.Column, Offset: position.Offset}
+	definition.End = &entity.Position{
+		Line:   position.Line,
+		Column: position.Column + len(obj.Name()),
+		Offset: position.Offset + len(obj.Name()),
+	}
+	definition.Node = nodes.at(filePath, checked.Files[filePath], obj.Pos())
+
+	return definition
+}
+
+// declarationNodes finds the code node of the top-level declaration containing a
+// position, remembering the nodes of each symbol looked up
+type declarationNodes struct {
+	usecase   *AnalyzerUsecase
+	projectID string
+	analysis  *entity.ProjectAnalysis
+	bySymbol  map[string][]*entity.CodeNode
+}
+
+func newDeclarationNodes(u *AnalyzerUsecase, projectID string, analysis *entity.ProjectAnalysis) *declarationNodes {
+	return &declarationNodes{
+		usecase:   u,
+		projectID: projectID,
+		analysis:  analysis,
+		bySymbol:  make(map[string][]*entity.CodeNode),
+	}
+}
+
+func (d *declarationNodes) at(filePath string, file *ast.File, pos token.Pos) *entity.CodeNodeRef {
+	fileInfo := d.analysis.Files[filePath]
+	if file == nil || fileInfo == nil {
+		return nil
+	}
+	receiver, name, ok := service.DeclarationAt(file, pos)
+	if !ok {
+		return nil
+	}
+
+	key := entity.SymbolKey(filePackagePath(fileInfo), receiver, name)
+	nodes, cached := d.bySymbol[key]
+	if !cached {
+		nodes, _ = d.usecase.repo.GetCodeNodesBySymbol(d.projectID, key)
+		d.bySymbol[key] = nodes
+	}
+
+	for _, node := range nodes {
+		if node.File == filePath {
+			return &entity.CodeNodeRef{ID: node.ID, Name: node.Name, Type: node.Type, File: node.File}
+		}
+	}
+	return nil
+}
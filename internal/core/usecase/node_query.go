@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+	"goapianalyzer/pkg/errors"
+)
+
+// QueryNodes runs a node query such as `type:function complexity>10 -file:*_test.go`.
+// Queries made of plain words only are answered by the ranked full-text search;
+// other results are ordered by file and position.
+func (u *FilterUsecase) QueryNodes(projectID, query string, page, limit int) ([]*entity.CodeNode, int64, error) {
+	parsed, err := service.ParseNodeQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if parsed.IsFreeText() {
+		return u.repo.SearchNodes(projectID, query, page, limit)
+	}
+
+	matcher, err := u.newNodeMatcher(projectID, parsed)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	allNodes, _, err := u.repo.GetAllNodes(projectID, 1, math.MaxInt32, "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matchedNodes := make([]*entity.CodeNode, 0)
+	for _, node := range allNodes {
+		if matcher.match(parsed, node) {
+			matchedNodes = append(matchedNodes, node)
+		}
+	}
+	sortNodesByLocation(matchedNodes)
+
+	total := int64(len(matchedNodes))
+
+	// Apply pagination
+	start := (page - 1) * limit
+	if start >= len(matchedNodes) {
+		return []*entity.CodeNode{}, total, nil
+	}
+
+	end := start + limit
+	if end > len(matchedNodes) {
+		end = len(matchedNodes)
+	}
+
+	return matchedNodes[start:end], total, nil
+}
+
+// SaveQuery validates and stores a named query of a project, replacing the query with the same ID
+func (u *FilterUsecase) SaveQuery(projectID string, query *entity.SavedQuery) (*entity.SavedQuery, error) {
+	query.Name = strings.TrimSpace(query.Name)
+	if query.Name == "" {
+		return nil, errors.NewValidationError("query name is required")
+	}
+	if _, err := service.ParseNodeQuery(query.Query); err != nil {
+		return nil, err
+	}
+
+	if query.ID != "" {
+		existing, err := u.repo.GetSavedQuery(projectID, query.ID)
+		if err != nil {
+			return nil, err
+		}
+		query.CreatedAt = existing.CreatedAt
+	}
+
+	if err := u.repo.StoreSavedQuery(projectID, query); err != nil {
+		return nil, err
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"query_id":   query.ID,
+		"name":       query.Name,
+	}).Info("Saved query stored")
+
+	return query, nil
+}
+
+func (u *FilterUsecase) GetSavedQuery(projectID, queryID string) (*entity.SavedQuery, error) {
+	return u.repo.GetSavedQuery(projectID, queryID)
+}
+
+func (u *FilterUsecase) ListSavedQueries(projectID string) ([]*entity.SavedQuery, error) {
+	return u.repo.ListSavedQueries(projectID)
+}
+
+func (u *FilterUsecase) DeleteSavedQuery(projectID, queryID string) error {
+	return u.repo.DeleteSavedQuery(projectID, queryID)
+}
+
+// RunSavedQuery runs a saved query against the current nodes of its project
+func (u *FilterUsecase) RunSavedQuery(projectID, queryID string, page, limit int) (*entity.SavedQuery, []*entity.CodeNode, int64, error) {
+	query, err := u.repo.GetSavedQuery(projectID, queryID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	nodes, total, err := u.QueryNodes(projectID, query.Query, page, limit)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return query, nodes, total, nil
+}
+
+// nodeMatcher evaluates a parsed query; free-text terms are looked up in the
+// search index once, before any node is visited
+type nodeMatcher struct {
+	filter *FilterUsecase
+	text   map[*service.QueryTerm]map[string]bool
+}
+
+func (u *FilterUsecase) newNodeMatcher(projectID string, query *service.NodeQuery) (*nodeMatcher, error) {
+	matcher := &nodeMatcher{filter: u, text: make(map[*service.QueryTerm]map[string]bool)}
+
+	for _, term := range query.Terms() {
+		if term.Field != service.FieldText {
+			continue
+		}
+		nodes, _, err := u.repo.SearchNodes(projectID, term.Value, 1, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		ids := make(map[string]bool, len(nodes))
+		for _, node := range nodes {
+			ids[node.ID] = true
+		}
+		matcher.text[term] = ids
+	}
+
+	return matcher, nil
+}
+
+func (m *nodeMatcher) match(query *service.NodeQuery, node *entity.CodeNode) bool {
+	switch query.Op {
+	case service.QueryOpAnd:
+		for _, child := range query.Children {
+			if !m.match(child, node) {
+				return false
+			}
+		}
+		return true
+	case service.QueryOpOr:
+		for _, child := range query.Children {
+			if m.match(child, node) {
+				return true
+			}
+		}
+		return false
+	case service.QueryOpNot:
+		return !m.match(query.Children[0], node)
+	default:
+		return m.matchTerm(query.Term, node)
+	}
+}
+
+func (m *nodeMatcher) matchTerm(term *service.QueryTerm, node *entity.CodeNode) bool {
+	switch term.Field {
+	case service.FieldText:
+		return m.text[term][node.ID]
+	case service.FieldType:
+		return term.Matches(node.Type)
+	case service.FieldName:
+		return term.Matches(node.Name)
+	case service.FieldPackage:
+		return term.Matches(node.Package) || term.MatchesSuffix(node.PackagePath, "/")
+	case service.FieldFile:
+		return term.MatchesSuffix(node.File, "/")
+	case service.FieldReceiver:
+		receiver, _ := node.Metadata["receiver"].(string)
+		return receiver != "" && (term.Matches(receiver) || term.Matches(strings.TrimPrefix(receiver, "*")))
+	case service.FieldCalls:
+		for _, name := range nodeCallNames(node) {
+			if term.MatchesSuffix(name, ".") {
+				return true
+			}
+		}
+		return false
+	case service.FieldComplexity:
+		return term.Compare(m.filter.getNodeComplexity(node))
+	case service.FieldLines:
+		return term.Compare(strings.Count(node.Body, "\n") + 1)
+	case service.FieldDoc:
+		return term.Contains(node.Doc)
+	case service.FieldSymbol:
+		return term.MatchesSuffix(node.SymbolKey, "/")
+	case service.FieldGenerated:
+		return node.Generated == term.Bool()
+	case service.FieldExported:
+		exported := node.Name != "" && unicode.IsUpper([]rune(node.Name)[0])
+		return exported == term.Bool()
+	default:
+		return false
+	}
+}
+
+// nodeCallNames lists the functions a node calls. Nodes read back from disk
+// carry their calls as decoded JSON objects.
+func nodeCallNames(node *entity.CodeNode) []string {
+	var names []string
+	switch calls := node.Metadata["calls_to"].(type) {
+	case []*entity.FunctionCall:
+		for _, call := range calls {
+			names = append(names, call.Name)
+		}
+	case []interface{}:
+		for _, call := range calls {
+			if fields, ok := call.(map[string]interface{}); ok {
+				if name, ok := fields["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func sortNodesByLocation(nodes []*entity.CodeNode) {
+	line := func(node *entity.CodeNode) int {
+		if node.Position == nil {
+			return 0
+		}
+		return node.Position.Line
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if line(a) != line(b) {
+			return line(a) < line(b)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+}
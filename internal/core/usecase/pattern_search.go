@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/core/domain/service"
+	"goapianalyzer/pkg/errors"
+)
+
+const (
+	defaultPatternMatches = 100
+	maxPatternMatches     = 1000
+)
+
+// SearchPattern finds code shaped like a Go expression or statement pattern with
+// metavariables, such as `$x.JSON(http.StatusInternalServerError, $_)`, across
+// every file of a project. Metavariables can be restricted to a type.
+func (u *AnalyzerUsecase) SearchPattern(projectID, source string, constraints map[string]string, limit int) (*entity.PatternSearchResult, error) {
+	start := time.Now()
+
+	pattern, err := service.CompilePattern(source, constraints)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultPatternMatches
+	}
+	if limit > maxPatternMatches {
+		limit = maxPatternMatches
+	}
+
+	checked := u.typeChecker.Check(analysis)
+
+	// A type from a package that was not loaded can never match
+	packages := pattern.ConstraintPackages()
+	for _, name := range pattern.Metavariables {
+		for _, pkg := range packages[name] {
+			if !checked.Resolves(pkg) {
+				return nil, errors.NewValidationError(fmt.Sprintf(
+					"constraint on $%s refers to package %s, which cannot be resolved in the project", name, pkg))
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(checked.Files))
+	for filePath := range checked.Files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	result := &entity.PatternSearchResult{
+		ProjectID:     projectID,
+		Pattern:       source,
+		Matches:       make([]*entity.PatternMatch, 0),
+		FilesSearched: len(paths),
+		TypeErrors:    checked.Errors,
+	}
+	for _, filePath := range paths {
+		matches := pattern.Match(checked.FileSet, checked.Files[filePath], checked.Info, filePath, analysis.Files[filePath].Content)
+		result.Total += len(matches)
+		for _, match := range matches {
+			if len(result.Matches) < limit {
+				result.Matches = append(result.Matches, match)
+			}
+		}
+	}
+	result.Truncated = result.Total > len(result.Matches)
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.GeneratedAt = time.Now().UTC()
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id":  projectID,
+		"pattern":     source,
+		"matches":     result.Total,
+		"duration_ms": result.DurationMs,
+	}).Info("Pattern search completed")
+
+	return result, nil
+}
@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// ListProjectVersions lists the stored versions of a project, oldest first
+func (u *AnalyzerUsecase) ListProjectVersions(projectID string) ([]*entity.ProjectVersionInfo, error) {
+	return u.repo.ListProjectVersions(projectID)
+}
+
+// DiffProjectVersions compares two versions of a project. A zero to selects the
+// current version and a zero from the version before to.
+func (u *AnalyzerUsecase) DiffProjectVersions(projectID string, from, to int) (*entity.ProjectDiff, error) {
+	fromAnalysis, toAnalysis, err := u.resolveVersions(projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	from, to = fromAnalysis.Version, toAnalysis.Version
+
+	diff := &entity.ProjectDiff{
+		ProjectID:           projectID,
+		FromVersion:         from,
+		ToVersion:           to,
+		AddedEndpoints:      make([]*entity.APIEndpoint, 0),
+		RemovedEndpoints:    make([]*entity.APIEndpoint, 0),
+		ChangedEndpoints:    make([]*entity.EndpointChange, 0),
+		ChangedFunctions:    make([]*entity.FunctionChange, 0),
+		ChangedTypes:        make([]*entity.TypeChange, 0),
+		AddedDependencies:   make([]*entity.Dependency, 0),
+		RemovedDependencies: make([]*entity.Dependency, 0),
+		GeneratedAt:         time.Now().UTC(),
+	}
+
+	u.diffEndpoints(diff, fromAnalysis, toAnalysis)
+	u.diffFunctions(diff, fromAnalysis, toAnalysis)
+	u.diffStructs(diff, fromAnalysis, toAnalysis)
+	u.diffDependencies(diff, fromAnalysis, toAnalysis)
+
+	diff.Summary = &entity.DiffSummary{
+		AddedEndpoints:      len(diff.AddedEndpoints),
+		RemovedEndpoints:    len(diff.RemovedEndpoints),
+		ChangedEndpoints:    len(diff.ChangedEndpoints),
+		ChangedFunctions:    len(diff.ChangedFunctions),
+		ChangedTypes:        len(diff.ChangedTypes),
+		AddedDependencies:   len(diff.AddedDependencies),
+		RemovedDependencies: len(diff.RemovedDependencies),
+	}
+
+	return diff, nil
+}
+
+// resolveVersions loads the two analyses to compare, defaulting to the current
+// version and its predecessor
+func (u *AnalyzerUsecase) resolveVersions(projectID string, from, to int) (*entity.ProjectAnalysis, *entity.ProjectAnalysis, error) {
+	if from < 0 || to < 0 {
+		return nil, nil, errors.NewValidationError("versions must be positive")
+	}
+
+	if to == 0 {
+		current, err := u.repo.GetProjectAnalysis(projectID)
+		if err != nil {
+			return nil, nil, err
+		}
+		to = current.Version
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 {
+		return nil, nil, errors.NewValidationError(fmt.Sprintf("version %d has no predecessor to compare with", to))
+	}
+
+	fromAnalysis, err := u.repo.GetProjectVersion(projectID, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toAnalysis, err := u.repo.GetProjectVersion(projectID, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fromAnalysis, toAnalysis, nil
+}
+
+// diffEndpoints matches endpoints by their stable ID (method and normalized path)
+func (u *AnalyzerUsecase) diffEndpoints(diff *entity.ProjectDiff, from, to *entity.ProjectAnalysis) {
+	previous := make(map[string]*entity.APIEndpoint, len(from.APIEndpoints))
+	for _, endpoint := range from.APIEndpoints {
+		previous[endpoint.ID] = endpoint
+	}
+
+	for _, endpoint := range to.APIEndpoints {
+		old, exists := previous[endpoint.ID]
+		delete(previous, endpoint.ID)
+		if !exists {
+			diff.AddedEndpoints = append(diff.AddedEndpoints, endpoint)
+			continue
+		}
+
+		var changes []string
+		if old.Handler != endpoint.Handler {
+			changes = append(changes, "handler")
+		}
+		if strings.Join(old.Middlewares, ",") != strings.Join(endpoint.Middlewares, ",") {
+			changes = append(changes, "middlewares")
+		}
+		if old.File != endpoint.File {
+			changes = append(changes, "file")
+		}
+		if len(changes) > 0 {
+			diff.ChangedEndpoints = append(diff.ChangedEndpoints, &entity.EndpointChange{
+				ID:      endpoint.ID,
+				Method:  endpoint.Method,
+				Path:    endpoint.Path,
+				Changes: changes,
+				From:    old,
+				To:      endpoint,
+			})
+		}
+	}
+
+	for _, endpoint := range previous {
+		diff.RemovedEndpoints = append(diff.RemovedEndpoints, endpoint)
+	}
+
+	sortEndpoints(diff.AddedEndpoints)
+	sortEndpoints(diff.RemovedEndpoints)
+	sort.Slice(diff.ChangedEndpoints, func(i, j int) bool {
+		a, b := diff.ChangedEndpoints[i], diff.ChangedEndpoints[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+}
+
+// diffFunctions reports functions and methods present in both versions whose signature differs
+func (u *AnalyzerUsecase) diffFunctions(diff *entity.ProjectDiff, from, to *entity.ProjectAnalysis) {
+	type function struct {
+		info *entity.FunctionInfo
+		file string
+	}
+	collect := func(analysis *entity.ProjectAnalysis) map[string]function {
+		functions := make(map[string]function)
+		for path, fileInfo := range analysis.Files {
+			for _, funcInfo := range fileInfo.Functions {
+				key := entity.SymbolKey(filePackagePath(fileInfo), funcInfo.Receiver, funcInfo.Name)
+				// Keys declared more than once (init, per-platform files) keep the first file in path order
+				if existing, exists := functions[key]; exists && existing.file < path {
+					continue
+				}
+				functions[key] = function{info: funcInfo, file: path}
+			}
+		}
+		return functions
+	}
+
+	previous := collect(from)
+	for key, current := range collect(to) {
+		old, exists := previous[key]
+		if !exists {
+			continue
+		}
+
+		oldSignature, newSignature := functionSignature(old.info), functionSignature(current.info)
+		if oldSignature == newSignature {
+			continue
+		}
+		diff.ChangedFunctions = append(diff.ChangedFunctions, &entity.FunctionChange{
+			SymbolKey:     key,
+			Name:          current.info.Name,
+			Receiver:      current.info.Receiver,
+			File:          current.file,
+			FromSignature: oldSignature,
+			ToSignature:   newSignature,
+		})
+	}
+
+	sort.Slice(diff.ChangedFunctions, func(i, j int) bool {
+		return diff.ChangedFunctions[i].SymbolKey < diff.ChangedFunctions[j].SymbolKey
+	})
+}
+
+// diffStructs reports structs present in both versions whose fields were added, removed or changed
+func (u *AnalyzerUsecase) diffStructs(diff *entity.ProjectDiff, from, to *entity.ProjectAnalysis) {
+	type structType struct {
+		info *entity.StructInfo
+		file string
+	}
+	collect := func(analysis *entity.ProjectAnalysis) map[string]structType {
+		structs := make(map[string]structType)
+		for path, fileInfo := range analysis.Files {
+			for _, structInfo := range fileInfo.Structs {
+				key := entity.SymbolKey(filePackagePath(fileInfo), "", structInfo.Name)
+				if existing, exists := structs[key]; exists && existing.file < path {
+					continue
+				}
+				structs[key] = structType{info: structInfo, file: path}
+			}
+		}
+		return structs
+	}
+
+	previous := collect(from)
+	for key, current := range collect(to) {
+		old, exists := previous[key]
+		if !exists {
+			continue
+		}
+
+		change := &entity.TypeChange{
+			SymbolKey:     key,
+			Name:          current.info.Name,
+			File:          current.file,
+			AddedFields:   make([]*entity.StructField, 0),
+			RemovedFields: make([]*entity.StructField, 0),
+			ChangedFields: make([]*entity.FieldChange, 0),
+		}
+
+		oldFields := make(map[string]*entity.StructField, len(old.info.Fields))
+		for _, field := range old.info.Fields {
+			oldFields[fieldKey(field)] = field
+		}
+		for _, field := range current.info.Fields {
+			key := fieldKey(field)
+			oldField, exists := oldFields[key]
+			delete(oldFields, key)
+
+			switch {
+			case !exists:
+				change.AddedFields = append(change.AddedFields, field)
+			case !reflect.DeepEqual(oldField, field):
+				change.ChangedFields = append(change.ChangedFields, &entity.FieldChange{
+					Name: key,
+					From: oldField,
+					To:   field,
+				})
+			}
+		}
+		for _, field := range old.info.Fields {
+			if _, removed := oldFields[fieldKey(field)]; removed {
+				change.RemovedFields = append(change.RemovedFields, field)
+			}
+		}
+
+		if len(change.AddedFields)+len(change.RemovedFields)+len(change.ChangedFields) > 0 {
+			diff.ChangedTypes = append(diff.ChangedTypes, change)
+		}
+	}
+
+	sort.Slice(diff.ChangedTypes, func(i, j int) bool {
+		return diff.ChangedTypes[i].SymbolKey < diff.ChangedTypes[j].SymbolKey
+	})
+}
+
+// diffDependencies reports dependency edges only one of the versions has
+func (u *AnalyzerUsecase) diffDependencies(diff *entity.ProjectDiff, from, to *entity.ProjectAnalysis) {
+	edges := func(analysis *entity.ProjectAnalysis) map[string]*entity.Dependency {
+		result := make(map[string]*entity.Dependency)
+		if analysis.DependencyGraph == nil {
+			return result
+		}
+		for _, dependency := range analysis.DependencyGraph.Dependencies {
+			result[dependency.From+"|"+dependency.Type+"|"+dependency.To] = dependency
+		}
+		return result
+	}
+
+	previous, current := edges(from), edges(to)
+	for key, dependency := range current {
+		if _, exists := previous[key]; !exists {
+			diff.AddedDependencies = append(diff.AddedDependencies, dependency)
+		}
+	}
+	for key, dependency := range previous {
+		if _, exists := current[key]; !exists {
+			diff.RemovedDependencies = append(diff.RemovedDependencies, dependency)
+		}
+	}
+
+	sortDependencies(diff.AddedDependencies)
+	sortDependencies(diff.RemovedDependencies)
+}
+
+// functionSignature renders the receiver, parameter and result types of a function;
+// parameter names are left out because renaming them does not change the signature
+func functionSignature(funcInfo *entity.FunctionInfo) string {
+	var builder strings.Builder
+	builder.WriteString("func ")
+	if funcInfo.Receiver != "" {
+		builder.WriteString("(" + funcInfo.Receiver + ") ")
+	}
+	builder.WriteString(funcInfo.Name)
+
+	params := make([]string, 0, len(funcInfo.Parameters))
+	for _, param := range funcInfo.Parameters {
+		params = append(params, param.Type)
+	}
+	builder.WriteString("(" + strings.Join(params, ", ") + ")")
+
+	results := make([]string, 0, len(funcInfo.Returns))
+	for _, result := range funcInfo.Returns {
+		results = append(results, result.Type)
+	}
+	switch len(results) {
+	case 0:
+	case 1:
+		builder.WriteString(" " + results[0])
+	default:
+		builder.WriteString(" (" + strings.Join(results, ", ") + ")")
+	}
+
+	return builder.String()
+}
+
+// fieldKey identifies a struct field; embedded fields are named after their type
+func fieldKey(field *entity.StructField) string {
+	if field.Name != "" {
+		return field.Name
+	}
+	return strings.TrimPrefix(field.Type, "*")
+}
+
+func filePackagePath(fileInfo *entity.FileInfo) string {
+	if fileInfo.ImportPath != "" {
+		return fileInfo.ImportPath
+	}
+	return fileInfo.PackageName
+}
+
+func sortEndpoints(endpoints []*entity.APIEndpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+}
+
+func sortDependencies(dependencies []*entity.Dependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		a, b := dependencies[i], dependencies[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Type < b.Type
+	})
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"net/url"
+	"strings"
+
+	"goapianalyzer/internal/adapter/exporter"
+	"goapianalyzer/pkg/errors"
+)
+
+// Request collection formats
+const (
+	CollectionFormatPostman = "postman" // Postman v2.1 collection
+	CollectionFormatHTTP    = "http"    // .http file of REST clients
+	CollectionFormatREST    = "rest"    // The same file with the .rest extension
+)
+
+// DefaultCollectionBaseURL is the base URL of request collections when none is given
+const DefaultCollectionBaseURL = "http://localhost:8080"
+
+// ExportRequestCollection turns the endpoints of a project into ready to send
+// requests, with example JSON bodies built from the request types. Requests are
+// grouped by router group and address baseURL through a variable.
+func (u *AnalyzerUsecase) ExportRequestCollection(projectID, format, baseURL string) ([]byte, error) {
+	format = strings.ToLower(format)
+	if format != CollectionFormatPostman && format != CollectionFormatHTTP && format != CollectionFormatREST {
+		return nil, errors.NewValidationError("unsupported collection format: " + format)
+	}
+
+	if baseURL == "" {
+		baseURL = DefaultCollectionBaseURL
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.NewValidationError("base URL must be an absolute http or https URL: " + baseURL)
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	analysis, err := u.repo.GetProjectAnalysis(projectID)
+	if err != nil {
+		return nil, err
+	}
+	docs := u.buildAPIDocs(analysis)
+
+	var data []byte
+	if format == CollectionFormatPostman {
+		if data, err = exporter.RenderPostmanCollection(docs, baseURL); err != nil {
+			return nil, err
+		}
+	} else {
+		data = exporter.RenderHTTPFile(docs, baseURL)
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"format":     format,
+		"endpoints":  len(analysis.APIEndpoints),
+		"bytes":      len(data),
+	}).Info("Request collection exported")
+
+	return data, nil
+}
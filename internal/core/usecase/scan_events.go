@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+const (
+	fileEventReplaySize   = 1024 // Most recent file_parsed events kept per job for late subscribers
+	eventSubscriberBuffer = 256  // Events a slow subscriber may fall behind before it is dropped
+)
+
+// eventLog keeps the events of a job and fans new ones out to subscribers.
+// Every event other than file_parsed is kept for replay, so late subscribers
+// always see the phases, endpoints, diagnostics and completion of a job; of
+// the per-file events only the most recent ones are kept.
+type eventLog struct {
+	jobID       string
+	events      []entity.ScanEvent
+	fileEvents  int // file_parsed events in events
+	nextID      int64
+	subscribers map[chan entity.ScanEvent]struct{}
+	closed      bool
+	mutex       sync.Mutex
+}
+
+func newEventLog(jobID string) *eventLog {
+	return &eventLog{
+		jobID:       jobID,
+		nextID:      1,
+		subscribers: make(map[chan entity.ScanEvent]struct{}),
+	}
+}
+
+func (l *eventLog) publish(eventType string, data interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return
+	}
+
+	event := entity.ScanEvent{
+		ID:    l.nextID,
+		JobID: l.jobID,
+		Type:  eventType,
+		Time:  time.Now().UTC(),
+		Data:  data,
+	}
+	l.nextID++
+
+	l.events = append(l.events, event)
+	if eventType == entity.ScanEventFileParsed {
+		l.fileEvents++
+		// Dropping in batches keeps publishing cheap on large projects
+		if l.fileEvents > 2*fileEventReplaySize {
+			l.dropFileEvents(l.fileEvents - fileEventReplaySize)
+		}
+	}
+
+	for subscriber := range l.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// The subscriber can reconnect with its last event ID and replay what it missed
+			delete(l.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// dropFileEvents removes the oldest count file_parsed events. Callers must hold the mutex.
+func (l *eventLog) dropFileEvents(count int) {
+	kept := l.events[:0]
+	for _, event := range l.events {
+		if count > 0 && event.Type == entity.ScanEventFileParsed {
+			count--
+			l.fileEvents--
+			continue
+		}
+		kept = append(kept, event)
+	}
+	l.events = kept
+}
+
+// subscribe returns the buffered events after lastEventID and a channel of the
+// following ones. The channel is closed once the job finishes.
+func (l *eventLog) subscribe(lastEventID int64) ([]entity.ScanEvent, <-chan entity.ScanEvent, func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	replay := make([]entity.ScanEvent, 0)
+	for _, event := range l.events {
+		if event.ID > lastEventID {
+			replay = append(replay, event)
+		}
+	}
+
+	subscriber := make(chan entity.ScanEvent, eventSubscriberBuffer)
+	if l.closed {
+		close(subscriber)
+		return replay, subscriber, func() {}
+	}
+	l.subscribers[subscriber] = struct{}{}
+
+	unsubscribe := func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		if _, exists := l.subscribers[subscriber]; exists {
+			delete(l.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+
+	return replay, subscriber, unsubscribe
+}
+
+// close ends all subscriptions; the buffered events remain available for replay
+func (l *eventLog) close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return
+	}
+	l.closed = true
+	for subscriber := range l.subscribers {
+		delete(l.subscribers, subscriber)
+		close(subscriber)
+	}
+}
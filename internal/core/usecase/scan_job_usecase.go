@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/infrastructure/logger"
+	"goapianalyzer/pkg/errors"
+	"goapianalyzer/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// ScanJobConfig bounds how many scans run at once and how many may wait
+type ScanJobConfig struct {
+	Concurrency int           // Scans running in parallel
+	QueueSize   int           // Scans waiting for a worker before submissions are rejected
+	Timeout     time.Duration // Limit of a single scan, 0 disables it
+	MaxRetained int           // Finished jobs kept for status queries
+}
+
+type ScanJobUsecase struct {
+	analyzerUsecase *AnalyzerUsecase
+	config          ScanJobConfig
+	queue           chan *scanJob
+	jobs            map[string]*scanJob
+	order           []string // Job IDs in submission order
+	mutex           sync.RWMutex
+	projectLocks    map[string]*projectLock // Project ID -> lock held by the job storing a new version
+	lockMutex       sync.Mutex
+	ctx             context.Context
+	cancel          context.CancelFunc
+	workers         sync.WaitGroup
+	logger          logger.Logger
+}
+
+// projectLock serializes the jobs re-scanning one project; held has room for one holder
+type projectLock struct {
+	held    chan struct{}
+	waiters int // Jobs holding or waiting for the lock
+}
+
+// scanJob is the internal state of a job; job is guarded by the usecase mutex
+type scanJob struct {
+	job    *entity.ScanJob
+	events *eventLog
+	err    error
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewScanJobUsecase(analyzerUsecase *AnalyzerUsecase, config *ScanJobConfig) *ScanJobUsecase {
+	cfg := ScanJobConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	if cfg.MaxRetained < 1 {
+		cfg.MaxRetained = 100
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ScanJobUsecase{
+		analyzerUsecase: analyzerUsecase,
+		config:          cfg,
+		queue:           make(chan *scanJob, cfg.QueueSize),
+		jobs:            make(map[string]*scanJob),
+		projectLocks:    make(map[string]*projectLock),
+		ctx:             ctx,
+		cancel:          cancel,
+		logger:          logger.GetLogger(),
+	}
+}
+
+// Start launches the scan workers
+func (u *ScanJobUsecase) Start() {
+	for i := 0; i < u.config.Concurrency; i++ {
+		u.workers.Add(1)
+		go u.worker()
+	}
+
+	u.logger.WithFields(map[string]interface{}{
+		"concurrency": u.config.Concurrency,
+		"queue_size":  u.config.QueueSize,
+	}).Info("Scan job workers started")
+}
+
+// Shutdown cancels queued and running jobs and waits for the workers to stop
+func (u *ScanJobUsecase) Shutdown(ctx context.Context) error {
+	u.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		u.workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Submit queues a project analysis and returns its job immediately. A non-empty
+// projectID stores the result as a new version of that project.
+func (u *ScanJobUsecase) Submit(projectPath, projectID string, config *entity.AnalysisConfig) (*entity.ScanJob, error) {
+	if !utils.IsValidPath(projectPath) {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid project path: %s", projectPath))
+	}
+	if projectID != "" {
+		if _, err := u.analyzerUsecase.GetProjectAnalysis(projectID); err != nil {
+			return nil, err
+		}
+	}
+	if u.ctx.Err() != nil {
+		return nil, errors.NewSystemError("server is shutting down")
+	}
+
+	ctx, cancel := context.WithCancel(u.ctx)
+	jobID := uuid.New().String()
+	j := &scanJob{
+		events: newEventLog(jobID),
+		job: &entity.ScanJob{
+			ID:          jobID,
+			ProjectPath: projectPath,
+			ProjectID:   projectID,
+			Status:      entity.ScanJobQueued,
+			Errors:      make([]string, 0),
+			Config:      config,
+			CreatedAt:   time.Now().UTC(),
+		},
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	select {
+	case u.queue <- j:
+	default:
+		cancel()
+		return nil, errors.NewRateLimitError("scan queue is full, retry later")
+	}
+
+	u.jobs[j.job.ID] = j
+	u.order = append(u.order, j.job.ID)
+
+	u.logger.WithFields(map[string]interface{}{
+		"job_id":       j.job.ID,
+		"project_path": projectPath,
+		"project_id":   projectID,
+	}).Info("Scan job queued")
+
+	return u.snapshot(j), nil
+}
+
+// GetJob returns the current state of a job
+func (u *ScanJobUsecase) GetJob(jobID string) (*entity.ScanJob, error) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+
+	j, exists := u.jobs[jobID]
+	if !exists {
+		return nil, errors.NewNotFoundError("scan job not found")
+	}
+	return u.snapshot(j), nil
+}
+
+// ListJobs returns all known jobs, newest first
+func (u *ScanJobUsecase) ListJobs() []*entity.ScanJob {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+
+	jobs := make([]*entity.ScanJob, 0, len(u.order))
+	for i := len(u.order) - 1; i >= 0; i-- {
+		jobs = append(jobs, u.snapshot(u.jobs[u.order[i]]))
+	}
+	return jobs
+}
+
+// CancelJob stops a queued or running job
+func (u *ScanJobUsecase) CancelJob(jobID string) (*entity.ScanJob, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	j, exists := u.jobs[jobID]
+	if !exists {
+		return nil, errors.NewNotFoundError("scan job not found")
+	}
+	if j.job.Finished() {
+		return nil, errors.NewValidationError("scan job already " + j.job.Status)
+	}
+
+	j.cancel()
+	if j.job.Status == entity.ScanJobQueued {
+		// Workers skip cancelled jobs when they dequeue them
+		u.finish(j, entity.ScanJobCancelled, context.Canceled)
+	}
+
+	u.logger.WithField("job_id", jobID).Info("Scan job cancelled")
+	return u.snapshot(j), nil
+}
+
+// SubscribeJob returns the buffered events of a job published after lastEventID
+// and a channel of the following ones, closed when the job finishes. Callers
+// must call the returned function once they stop reading.
+func (u *ScanJobUsecase) SubscribeJob(jobID string, lastEventID int64) ([]entity.ScanEvent, <-chan entity.ScanEvent, func(), error) {
+	u.mutex.RLock()
+	j, exists := u.jobs[jobID]
+	u.mutex.RUnlock()
+	if !exists {
+		return nil, nil, nil, errors.NewNotFoundError("scan job not found")
+	}
+
+	replay, events, unsubscribe := j.events.subscribe(lastEventID)
+	return replay, events, unsubscribe, nil
+}
+
+// WaitJob blocks until the job finishes or ctx is done. A finished job is
+// returned together with the error its analysis failed with, if any.
+func (u *ScanJobUsecase) WaitJob(ctx context.Context, jobID string) (*entity.ScanJob, error) {
+	u.mutex.RLock()
+	j, exists := u.jobs[jobID]
+	u.mutex.RUnlock()
+	if !exists {
+		return nil, errors.NewNotFoundError("scan job not found")
+	}
+
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	return u.snapshot(j), j.err
+}
+
+func (u *ScanJobUsecase) worker() {
+	defer u.workers.Done()
+
+	for {
+		select {
+		case j := <-u.queue:
+			u.run(j)
+		case <-u.ctx.Done():
+			u.drain()
+			return
+		}
+	}
+}
+
+// drain marks jobs still waiting in the queue as cancelled during shutdown
+func (u *ScanJobUsecase) drain() {
+	for {
+		select {
+		case j := <-u.queue:
+			u.mutex.Lock()
+			if !j.job.Finished() {
+				u.finish(j, entity.ScanJobCancelled, context.Canceled)
+			}
+			u.mutex.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+func (u *ScanJobUsecase) run(j *scanJob) {
+	u.mutex.Lock()
+	if j.job.Finished() || j.ctx.Err() != nil {
+		if !j.job.Finished() {
+			u.finish(j, entity.ScanJobCancelled, context.Canceled)
+		}
+		u.mutex.Unlock()
+		return
+	}
+	startedAt := time.Now().UTC()
+	j.job.Status = entity.ScanJobRunning
+	j.job.StartedAt = &startedAt
+	config := j.job.Config
+	u.mutex.Unlock()
+
+	ctx := j.ctx
+	if u.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.config.Timeout)
+		defer cancel()
+	}
+
+	if config == nil {
+		config = &entity.AnalysisConfig{}
+	}
+	// Versions of a project are scanned one at a time so that each builds on the last
+	var analysis *entity.ProjectAnalysis
+	unlock, err := u.lockProject(ctx, j.job.ProjectID)
+	if err == nil {
+		analysis, err = u.analyzerUsecase.AnalyzeProjectContext(ctx, j.job.ProjectPath, j.job.ProjectID, config, &jobObserver{usecase: u, job: j})
+		unlock()
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		j.job.ProjectID = analysis.ID
+		u.finish(j, entity.ScanJobCompleted, nil)
+	case stderrors.Is(err, context.DeadlineExceeded):
+		err = errors.NewSystemError(fmt.Sprintf("scan timed out after %s", u.config.Timeout))
+		u.finish(j, entity.ScanJobFailed, err)
+	case stderrors.Is(err, context.Canceled):
+		u.finish(j, entity.ScanJobCancelled, err)
+	default:
+		u.finish(j, entity.ScanJobFailed, err)
+	}
+}
+
+// lockProject waits until no other job scans projectID or ctx ends and returns
+// the function releasing the lock. Jobs without a project ID do not lock.
+func (u *ScanJobUsecase) lockProject(ctx context.Context, projectID string) (func(), error) {
+	if projectID == "" {
+		return func() {}, nil
+	}
+
+	u.lockMutex.Lock()
+	lock := u.projectLocks[projectID]
+	if lock == nil {
+		lock = &projectLock{held: make(chan struct{}, 1)}
+		u.projectLocks[projectID] = lock
+	}
+	lock.waiters++
+	u.lockMutex.Unlock()
+
+	release := func() {
+		u.lockMutex.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(u.projectLocks, projectID)
+		}
+		u.lockMutex.Unlock()
+	}
+
+	select {
+	case lock.held <- struct{}{}:
+		return func() {
+			<-lock.held
+			release()
+		}, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+// finish moves a job to a terminal state. Callers must hold the write lock.
+func (u *ScanJobUsecase) finish(j *scanJob, status string, err error) {
+	finishedAt := time.Now().UTC()
+	j.job.Status = status
+	j.job.FinishedAt = &finishedAt
+	j.err = err
+	if err != nil && status == entity.ScanJobFailed {
+		j.job.Errors = append(j.job.Errors, err.Error())
+	}
+	j.cancel()
+	close(j.done)
+
+	j.events.publish(entity.ScanEventCompleted, u.snapshot(j))
+	j.events.close()
+
+	u.logger.WithFields(map[string]interface{}{
+		"job_id":     j.job.ID,
+		"status":     status,
+		"project_id": j.job.ProjectID,
+	}).Info("Scan job finished")
+
+	u.prune()
+}
+
+// prune forgets the oldest finished jobs beyond MaxRetained. Callers must hold the write lock.
+func (u *ScanJobUsecase) prune() {
+	finished := 0
+	for _, jobID := range u.order {
+		if u.jobs[jobID].job.Finished() {
+			finished++
+		}
+	}
+
+	kept := u.order[:0]
+	for _, jobID := range u.order {
+		if finished > u.config.MaxRetained && u.jobs[jobID].job.Finished() {
+			delete(u.jobs, jobID)
+			finished--
+			continue
+		}
+		kept = append(kept, jobID)
+	}
+	u.order = kept
+}
+
+// snapshot copies a job so callers never observe concurrent updates. Callers must hold the lock.
+func (u *ScanJobUsecase) snapshot(j *scanJob) *entity.ScanJob {
+	job := *j.job
+	job.Errors = append([]string{}, j.job.Errors...)
+	return &job
+}
+
+// jobObserver records analysis progress on its job
+type jobObserver struct {
+	usecase *ScanJobUsecase
+	job     *scanJob
+}
+
+func (o *jobObserver) PhaseChanged(phase string) {
+	o.usecase.mutex.Lock()
+	defer o.usecase.mutex.Unlock()
+	o.job.job.Phase = phase
+	o.job.events.publish(entity.ScanEventPhaseChanged, map[string]interface{}{
+		"phase": phase,
+	})
+}
+
+func (o *jobObserver) FilesFound(total int) {
+	o.usecase.mutex.Lock()
+	defer o.usecase.mutex.Unlock()
+	o.job.job.FilesTotal = total
+}
+
+func (o *jobObserver) FileScanned(path string, err error) {
+	o.usecase.mutex.Lock()
+	defer o.usecase.mutex.Unlock()
+	o.job.job.FilesProcessed++
+	path = o.relativePath(path)
+
+	data := map[string]interface{}{
+		"path":            path,
+		"files_processed": o.job.job.FilesProcessed,
+		"files_total":     o.job.job.FilesTotal,
+	}
+	if err != nil {
+		o.job.job.Errors = append(o.job.job.Errors, fmt.Sprintf("%s: %v", path, err))
+		data["error"] = err.Error()
+	}
+	o.job.events.publish(entity.ScanEventFileParsed, data)
+}
+
+func (o *jobObserver) Diagnostic(path string, err error) {
+	o.usecase.mutex.Lock()
+	defer o.usecase.mutex.Unlock()
+	message := err.Error()
+	if path != "" {
+		message = fmt.Sprintf("%s: %v", path, err)
+	}
+	o.job.job.Errors = append(o.job.job.Errors, message)
+	o.job.events.publish(entity.ScanEventDiagnostic, map[string]interface{}{
+		"path":    path,
+		"message": err.Error(),
+	})
+}
+
+func (o *jobObserver) EndpointDiscovered(endpoint *entity.APIEndpoint) {
+	o.job.events.publish(entity.ScanEventEndpointDiscovered, endpoint)
+}
+
+func (o *jobObserver) relativePath(path string) string {
+	if rel, err := filepath.Rel(o.job.job.ProjectPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
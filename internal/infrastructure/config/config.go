@@ -19,9 +19,11 @@ type Config struct {
 	EnableRequestLogging bool
 
 	// Analysis configuration
-	MaxProjectSize int64
-	MaxFileSize    int64
-	ScanTimeout    int
+	MaxProjectSize  int64
+	MaxFileSize     int64
+	ScanTimeout     int
+	ScanConcurrency int
+	ScanQueueSize   int
 
 	// API configuration
 	APIVersion        string
@@ -34,8 +36,12 @@ type Config struct {
 	AllowCredentials bool
 
 	// Storage configuration
-	StorageType       string
-	MaxStoredProjects int
+	StorageType        string
+	StoragePath        string
+	MaxStoredProjects  int
+	ProjectTTL         int   // seconds since last access, 0 keeps projects indefinitely
+	MaxProjectBytes    int64 // estimated bytes per project, 0 disables the cap
+	MaxProjectVersions int   // archived versions kept per project, 0 keeps all
 }
 
 func Load() (*Config, error) {
@@ -53,9 +59,11 @@ func Load() (*Config, error) {
 		EnableRequestLogging: getEnvBool("ENABLE_REQUEST_LOGGING", true),
 
 		// Analysis defaults
-		MaxProjectSize: getEnvInt64("MAX_PROJECT_SIZE", 100*1024*1024), // 100MB
-		MaxFileSize:    getEnvInt64("MAX_FILE_SIZE", 1024*1024),        // 1MB
-		ScanTimeout:    getEnvInt("SCAN_TIMEOUT", 300),                 // 5 minutes
+		MaxProjectSize:  getEnvInt64("MAX_PROJECT_SIZE", 100*1024*1024), // 100MB
+		MaxFileSize:     getEnvInt64("MAX_FILE_SIZE", 1024*1024),        // 1MB
+		ScanTimeout:     getEnvInt("SCAN_TIMEOUT", 300),                 // 5 minutes
+		ScanConcurrency: getEnvInt("SCAN_CONCURRENCY", 2),
+		ScanQueueSize:   getEnvInt("SCAN_QUEUE_SIZE", 16),
 
 		// API defaults
 		APIVersion:        getEnv("API_VERSION", "v1"),
@@ -68,8 +76,12 @@ func Load() (*Config, error) {
 		AllowCredentials: getEnvBool("ALLOW_CREDENTIALS", true),
 
 		// Storage defaults
-		StorageType:       getEnv("STORAGE_TYPE", "memory"),
-		MaxStoredProjects: getEnvInt("MAX_STORED_PROJECTS", 10),
+		StorageType:        getEnv("STORAGE_TYPE", "memory"),
+		StoragePath:        getEnv("STORAGE_PATH", "./data"),
+		MaxStoredProjects:  getEnvInt("MAX_STORED_PROJECTS", 10),
+		ProjectTTL:         getEnvInt("PROJECT_TTL", 0),
+		MaxProjectBytes:    getEnvInt64("MAX_PROJECT_BYTES", 0),
+		MaxProjectVersions: getEnvInt("MAX_PROJECT_VERSIONS", 10),
 	}
 
 	return config, nil
@@ -0,0 +1,768 @@
+package repository
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+const (
+	snapshotVersion   = 1
+	snapshotExtension = ".json.gz"
+)
+
+// FileRepositoryConfig configures the on-disk analysis repository
+type FileRepositoryConfig struct {
+	Path      string // Directory holding one snapshot per project
+	Retention *RetentionConfig
+}
+
+// FileAnalysisRepository keeps analyses in memory and persists every project
+// as a compressed snapshot. Snapshots are written to a temporary file, synced
+// and renamed into place, so a crash leaves either the old or the new state.
+//
+// Scans write their project when they complete through FlushProject; pinning,
+// deletion and saved queries are written before they return. Projects evicted
+// for the project limit only leave memory: their snapshots stay on disk, listed
+// in an index, and are loaded again on first access. Expired projects are
+// deleted from disk as well. Listings and storage stats cover every project.
+type FileAnalysisRepository struct {
+	*MemoryAnalysisRepository
+	dir        string
+	dirty      map[string]bool             // Projects changed since they were last written
+	evicted    map[string]*projectSnapshot // Changes of dirty projects evicted before being written
+	onDisk     map[string]*diskProject     // Projects stored on disk but not loaded
+	expired    map[string]bool             // Projects whose snapshots are yet to be removed
+	dirtyMutex sync.Mutex
+	diskMutex  sync.Mutex // Guards onDisk and expired
+	loadMutex  sync.Mutex
+	writeMutex sync.Mutex // Serializes snapshot writes and removals
+}
+
+// diskProject indexes a project that is not loaded, as it was when it left memory
+type diskProject struct {
+	summary    *entity.ProjectSummary
+	bytes      int64
+	versions   int
+	lastAccess time.Time
+}
+
+// projectSnapshot is the on-disk format of a single project
+type projectSnapshot struct {
+	Version  int                       `json:"version"`
+	SavedAt  time.Time                 `json:"saved_at"`
+	Analysis *entity.ProjectAnalysis   `json:"analysis"`
+	Nodes    []*entity.CodeNode        `json:"nodes"`
+	APIs     []*entity.APIEndpoint     `json:"apis"`
+	Versions []*entity.ProjectAnalysis `json:"versions,omitempty"` // Archived versions, oldest first
+	Queries  []*entity.SavedQuery      `json:"queries,omitempty"`
+}
+
+func NewFileAnalysisRepository(config *FileRepositoryConfig) (*FileAnalysisRepository, error) {
+	if config == nil || config.Path == "" {
+		return nil, errors.NewValidationError("storage path cannot be empty")
+	}
+
+	if err := os.MkdirAll(config.Path, 0o755); err != nil {
+		return nil, errors.NewSystemError(fmt.Sprintf("failed to create storage directory: %v", err))
+	}
+
+	r := &FileAnalysisRepository{
+		MemoryAnalysisRepository: NewMemoryAnalysisRepository(config.Retention),
+		dir:                      config.Path,
+		dirty:                    make(map[string]bool),
+		evicted:                  make(map[string]*projectSnapshot),
+		onDisk:                   make(map[string]*diskProject),
+		expired:                  make(map[string]bool),
+	}
+	r.onEvict = r.keepEvicted
+	r.onExpire = r.removeExpired
+
+	if err := r.loadSnapshots(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Project Analysis Methods
+
+func (r *FileAnalysisRepository) StoreProjectAnalysis(analysis *entity.ProjectAnalysis) error {
+	if analysis != nil {
+		// Keep the versions and saved queries of an evicted project
+		r.ensureLoaded(analysis.ID)
+	}
+	if err := r.MemoryAnalysisRepository.StoreProjectAnalysis(analysis); err != nil {
+		return err
+	}
+	r.markDirty(analysis.ID)
+	return nil
+}
+
+func (r *FileAnalysisRepository) GetProjectAnalysis(projectID string) (*entity.ProjectAnalysis, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetProjectAnalysis(projectID)
+}
+
+func (r *FileAnalysisRepository) DeleteProjectAnalysis(projectID string) error {
+	r.ensureLoaded(projectID)
+
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	if err := r.MemoryAnalysisRepository.DeleteProjectAnalysis(projectID); err != nil {
+		return err
+	}
+
+	r.diskMutex.Lock()
+	delete(r.onDisk, projectID)
+	r.diskMutex.Unlock()
+
+	r.dirtyMutex.Lock()
+	delete(r.dirty, projectID)
+	delete(r.evicted, projectID)
+	r.dirtyMutex.Unlock()
+
+	if err := os.Remove(r.snapshotPath(projectID)); err != nil && !os.IsNotExist(err) {
+		return errors.NewSystemError(fmt.Sprintf("failed to remove project snapshot: %v", err))
+	}
+	if err := syncDir(r.dir); err != nil {
+		return errors.NewSystemError(fmt.Sprintf("failed to remove project snapshot: %v", err))
+	}
+	return nil
+}
+
+// ListProjectSummaries returns the summaries of loaded projects and of those only stored on disk
+func (r *FileAnalysisRepository) ListProjectSummaries() ([]*entity.ProjectSummary, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	r.diskMutex.Lock()
+	defer r.diskMutex.Unlock()
+
+	summaries := make([]*entity.ProjectSummary, 0, len(r.projects)+len(r.onDisk))
+	for projectID := range r.projects {
+		summaries = append(summaries, r.projectSummary(projectID))
+	}
+	for _, stored := range r.onDisk {
+		summary := *stored.summary
+		summaries = append(summaries, &summary)
+	}
+	return summaries, nil
+}
+
+// GetStorageStats covers loaded projects and those only stored on disk; the
+// estimated bytes of the latter are what they hold once loaded again
+func (r *FileAnalysisRepository) GetStorageStats() (*entity.StorageStats, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	r.diskMutex.Lock()
+	defer r.diskMutex.Unlock()
+
+	stats := r.storageStats()
+	for projectID, stored := range r.onDisk {
+		info := &entity.ProjectStorageInfo{
+			ProjectID:      projectID,
+			ProjectPath:    stored.summary.ProjectPath,
+			Bytes:          stored.bytes,
+			Files:          stored.summary.FileCount,
+			Nodes:          stored.summary.NodeCount,
+			Endpoints:      stored.summary.EndpointCount,
+			Pinned:         stored.summary.Pinned,
+			LastAccessedAt: stored.lastAccess,
+			Versions:       stored.versions,
+		}
+		r.setExpiry(info)
+		stats.Projects = append(stats.Projects, info)
+	}
+	stats.TotalProjects = len(stats.Projects)
+
+	sort.Slice(stats.Projects, func(i, j int) bool {
+		return stats.Projects[i].Bytes > stats.Projects[j].Bytes
+	})
+	return stats, nil
+}
+
+func (r *FileAnalysisRepository) PinProject(projectID string, pinned bool) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.PinProject(projectID, pinned); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return r.FlushProject(projectID)
+}
+
+func (r *FileAnalysisRepository) ReplaceProject(analysis *entity.ProjectAnalysis, nodes []*entity.CodeNode, archive bool) error {
+	if analysis != nil {
+		// Keep the versions and saved queries of an evicted project; versions count against the limit
+		r.ensureLoaded(analysis.ID)
+	}
+	if err := r.MemoryAnalysisRepository.ReplaceProject(analysis, nodes, archive); err != nil {
+		return err
+	}
+	r.markDirty(analysis.ID)
+	return nil
+}
+
+// Project Version Methods
+
+func (r *FileAnalysisRepository) StoreProjectVersion(analysis *entity.ProjectAnalysis) error {
+	if analysis != nil {
+		r.ensureLoaded(analysis.ID)
+	}
+	if err := r.MemoryAnalysisRepository.StoreProjectVersion(analysis); err != nil {
+		return err
+	}
+	r.markDirty(analysis.ID)
+	return nil
+}
+
+func (r *FileAnalysisRepository) GetProjectVersion(projectID string, version int) (*entity.ProjectAnalysis, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetProjectVersion(projectID, version)
+}
+
+func (r *FileAnalysisRepository) ListProjectVersions(projectID string) ([]*entity.ProjectVersionInfo, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.ListProjectVersions(projectID)
+}
+
+// Code Node Methods
+
+func (r *FileAnalysisRepository) StoreCodeNode(projectID string, node *entity.CodeNode) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.StoreCodeNode(projectID, node); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return nil
+}
+
+func (r *FileAnalysisRepository) GetCodeNode(projectID, nodeID string) (*entity.CodeNode, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetCodeNode(projectID, nodeID)
+}
+
+func (r *FileAnalysisRepository) GetCodeNodesBySymbol(projectID, symbolKey string) ([]*entity.CodeNode, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetCodeNodesBySymbol(projectID, symbolKey)
+}
+
+func (r *FileAnalysisRepository) GetAllNodes(projectID string, page, limit int, nodeType string) ([]*entity.CodeNode, int64, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetAllNodes(projectID, page, limit, nodeType)
+}
+
+func (r *FileAnalysisRepository) SearchNodes(projectID, query string, page, limit int) ([]*entity.CodeNode, int64, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.SearchNodes(projectID, query, page, limit)
+}
+
+func (r *FileAnalysisRepository) DeleteCodeNode(projectID, nodeID string) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.DeleteCodeNode(projectID, nodeID); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return nil
+}
+
+// API Endpoint Methods
+
+func (r *FileAnalysisRepository) StoreAPIEndpoint(projectID string, endpoint *entity.APIEndpoint) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.StoreAPIEndpoint(projectID, endpoint); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return nil
+}
+
+func (r *FileAnalysisRepository) GetAPIEndpoint(projectID, apiID string) (*entity.APIEndpoint, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetAPIEndpoint(projectID, apiID)
+}
+
+func (r *FileAnalysisRepository) GetAPIEndpoints(projectID string) ([]*entity.APIEndpoint, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetAPIEndpoints(projectID)
+}
+
+func (r *FileAnalysisRepository) GetAPINodes(projectID, apiID string) ([]*entity.CodeNode, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetAPINodes(projectID, apiID)
+}
+
+func (r *FileAnalysisRepository) DeleteAPIEndpoint(projectID, apiID string) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.DeleteAPIEndpoint(projectID, apiID); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return nil
+}
+
+// Saved Query Methods
+
+func (r *FileAnalysisRepository) StoreSavedQuery(projectID string, query *entity.SavedQuery) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.StoreSavedQuery(projectID, query); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return r.FlushProject(projectID)
+}
+
+func (r *FileAnalysisRepository) GetSavedQuery(projectID, queryID string) (*entity.SavedQuery, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.GetSavedQuery(projectID, queryID)
+}
+
+func (r *FileAnalysisRepository) ListSavedQueries(projectID string) ([]*entity.SavedQuery, error) {
+	r.ensureLoaded(projectID)
+	return r.MemoryAnalysisRepository.ListSavedQueries(projectID)
+}
+
+func (r *FileAnalysisRepository) DeleteSavedQuery(projectID, queryID string) error {
+	r.ensureLoaded(projectID)
+	if err := r.MemoryAnalysisRepository.DeleteSavedQuery(projectID, queryID); err != nil {
+		return err
+	}
+	r.markDirty(projectID)
+	return r.FlushProject(projectID)
+}
+
+// Persistence
+
+// FlushProject writes a project to disk if it changed since it was last written,
+// along with projects evicted before their changes were written
+func (r *FileAnalysisRepository) FlushProject(projectID string) error {
+	r.dirtyMutex.Lock()
+	projectIDs := []string{projectID}
+	for evictedID := range r.evicted {
+		if evictedID != projectID {
+			projectIDs = append(projectIDs, evictedID)
+		}
+	}
+	r.dirtyMutex.Unlock()
+
+	return r.persistAll(projectIDs)
+}
+
+// Flush writes every project changed since it was last written
+func (r *FileAnalysisRepository) Flush() error {
+	r.dirtyMutex.Lock()
+	projectIDs := make([]string, 0, len(r.dirty))
+	for projectID := range r.dirty {
+		projectIDs = append(projectIDs, projectID)
+	}
+	r.dirtyMutex.Unlock()
+
+	return r.persistAll(projectIDs)
+}
+
+// Close stops the background expiry and writes all pending changes
+func (r *FileAnalysisRepository) Close() error {
+	if err := r.MemoryAnalysisRepository.Close(); err != nil {
+		return err
+	}
+	return r.Flush()
+}
+
+func (r *FileAnalysisRepository) markDirty(projectID string) {
+	r.dirtyMutex.Lock()
+	r.dirty[projectID] = true
+	r.dirtyMutex.Unlock()
+}
+
+// keepEvicted indexes a project evicted for the project limit and holds on to
+// its unwritten changes so that they are still written. Expired projects are
+// dropped instead, and their snapshots removed by removeExpired. It runs with
+// the write lock held.
+func (r *FileAnalysisRepository) keepEvicted(projectID, reason string) {
+	r.dirtyMutex.Lock()
+	defer r.dirtyMutex.Unlock()
+	r.diskMutex.Lock()
+	defer r.diskMutex.Unlock()
+
+	if reason == "expired" {
+		delete(r.dirty, projectID)
+		delete(r.evicted, projectID)
+		r.expired[projectID] = true
+		return
+	}
+
+	if r.dirty[projectID] {
+		r.evicted[projectID] = r.snapshot(projectID)
+	}
+	r.onDisk[projectID] = &diskProject{
+		summary:    r.projectSummary(projectID),
+		bytes:      r.sizes[projectID],
+		versions:   len(r.versions[projectID]) + 1,
+		lastAccess: r.lastAccess(projectID),
+	}
+}
+
+// removeExpired deletes the snapshots of expired projects, including those that
+// expired while only stored on disk
+func (r *FileAnalysisRepository) removeExpired() {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	now := time.Now().UTC()
+	r.diskMutex.Lock()
+	var projectIDs []string
+	for projectID := range r.expired {
+		projectIDs = append(projectIDs, projectID)
+	}
+	for projectID, stored := range r.onDisk {
+		if !stored.summary.Pinned && now.Sub(stored.lastAccess) > r.retention.TTL {
+			projectIDs = append(projectIDs, projectID)
+			delete(r.onDisk, projectID)
+		}
+	}
+	r.expired = make(map[string]bool)
+	r.diskMutex.Unlock()
+
+	if len(projectIDs) == 0 {
+		return
+	}
+
+	for _, projectID := range projectIDs {
+		r.dirtyMutex.Lock()
+		delete(r.dirty, projectID)
+		delete(r.evicted, projectID)
+		r.dirtyMutex.Unlock()
+
+		if err := os.Remove(r.snapshotPath(projectID)); err != nil && !os.IsNotExist(err) {
+			r.logger.WithError(err).WithField("project_id", projectID).Warn("Failed to remove expired snapshot")
+			continue
+		}
+		r.logger.WithField("project_id", projectID).Info("Removed expired project snapshot")
+	}
+	if err := syncDir(r.dir); err != nil {
+		r.logger.WithError(err).Warn("Failed to remove expired snapshots")
+	}
+}
+
+// ensureLoaded brings an evicted project back into memory from its unwritten
+// changes or its snapshot
+func (r *FileAnalysisRepository) ensureLoaded(projectID string) {
+	if projectID == "" || r.resident(projectID) {
+		return
+	}
+
+	r.loadMutex.Lock()
+	defer r.loadMutex.Unlock()
+	if r.resident(projectID) || !r.stored(projectID) {
+		return
+	}
+
+	r.dirtyMutex.Lock()
+	snapshot := r.evicted[projectID]
+	delete(r.evicted, projectID)
+	r.dirtyMutex.Unlock()
+
+	if snapshot == nil {
+		var err error
+		if snapshot, err = readSnapshot(r.snapshotPath(projectID)); err != nil {
+			if !os.IsNotExist(err) {
+				r.logger.WithError(err).WithField("project_id", projectID).Warn("Failed to load evicted project")
+			}
+			return
+		}
+	}
+
+	r.load(snapshot)
+	r.mutex.Lock()
+	r.enforceRetention(projectID)
+	r.mutex.Unlock()
+}
+
+// stored reports whether a project that is not loaded is indexed on disk
+func (r *FileAnalysisRepository) stored(projectID string) bool {
+	r.diskMutex.Lock()
+	defer r.diskMutex.Unlock()
+
+	_, exists := r.onDisk[projectID]
+	return exists
+}
+
+func (r *FileAnalysisRepository) resident(projectID string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, exists := r.projects[projectID]
+	return exists
+}
+
+func (r *FileAnalysisRepository) persistAll(projectIDs []string) error {
+	var firstErr error
+	for _, projectID := range projectIDs {
+		if err := r.persist(projectID); err != nil {
+			r.logger.WithError(err).WithField("project_id", projectID).Error("Failed to persist project analysis")
+			if firstErr == nil {
+				firstErr = errors.NewSystemError(fmt.Sprintf("failed to persist project %s: %v", projectID, err))
+			}
+		}
+	}
+	return firstErr
+}
+
+// persist writes the snapshot of a dirty project. The snapshot is copied under
+// the read lock and encoded after releasing it.
+func (r *FileAnalysisRepository) persist(projectID string) error {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	r.dirtyMutex.Lock()
+	if !r.dirty[projectID] {
+		r.dirtyMutex.Unlock()
+		return nil
+	}
+	delete(r.dirty, projectID)
+	snapshot, evicted := r.evicted[projectID]
+	delete(r.evicted, projectID)
+	r.dirtyMutex.Unlock()
+
+	if !evicted {
+		r.mutex.RLock()
+		snapshot = r.snapshot(projectID)
+		r.mutex.RUnlock()
+	}
+	if snapshot == nil {
+		// Deleted, or evicted after it was written
+		return nil
+	}
+
+	err := writeFileAtomic(r.snapshotPath(projectID), func(file *os.File) error {
+		gz := gzip.NewWriter(file)
+		if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+			return err
+		}
+		return gz.Close()
+	})
+	if err != nil {
+		// Keep the changes so that the next flush retries them
+		keep := evicted && !r.resident(projectID)
+		r.dirtyMutex.Lock()
+		r.dirty[projectID] = true
+		if keep {
+			r.evicted[projectID] = snapshot
+		}
+		r.dirtyMutex.Unlock()
+	}
+	return err
+}
+
+// snapshot copies the state of a project for writing, or returns nil if the
+// project is not loaded. Callers must hold the lock.
+func (r *FileAnalysisRepository) snapshot(projectID string) *projectSnapshot {
+	analysis, exists := r.projects[projectID]
+	if !exists {
+		return nil
+	}
+
+	// Pinning changes the stored analysis in place
+	copied := *analysis
+	snapshot := &projectSnapshot{
+		Version:  snapshotVersion,
+		SavedAt:  time.Now().UTC(),
+		Analysis: &copied,
+		Nodes:    make([]*entity.CodeNode, 0, len(r.nodes[projectID])),
+		APIs:     make([]*entity.APIEndpoint, 0, len(r.apis[projectID])),
+	}
+	for _, node := range r.nodes[projectID] {
+		snapshot.Nodes = append(snapshot.Nodes, node)
+	}
+	for _, endpoint := range r.apis[projectID] {
+		snapshot.APIs = append(snapshot.APIs, endpoint)
+	}
+	for _, archived := range r.versions[projectID] {
+		snapshot.Versions = append(snapshot.Versions, archived)
+	}
+	for _, query := range r.queries[projectID] {
+		snapshot.Queries = append(snapshot.Queries, query)
+	}
+	sort.Slice(snapshot.Nodes, func(i, j int) bool { return snapshot.Nodes[i].ID < snapshot.Nodes[j].ID })
+	sort.Slice(snapshot.APIs, func(i, j int) bool { return snapshot.APIs[i].ID < snapshot.APIs[j].ID })
+	sort.Slice(snapshot.Versions, func(i, j int) bool { return snapshot.Versions[i].Version < snapshot.Versions[j].Version })
+	sort.Slice(snapshot.Queries, func(i, j int) bool { return snapshot.Queries[i].ID < snapshot.Queries[j].ID })
+	return snapshot
+}
+
+// loadSnapshots loads every readable snapshot and clears leftovers of interrupted writes
+func (r *FileAnalysisRepository) loadSnapshots() error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return errors.NewSystemError(fmt.Sprintf("failed to read storage directory: %v", err))
+	}
+
+	loaded := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(r.dir, entry.Name())
+
+		switch {
+		case strings.HasSuffix(entry.Name(), ".tmp"):
+			// A write that never reached its rename; the previous snapshot is still intact
+			if err := os.Remove(path); err != nil {
+				r.logger.WithError(err).WithField("file", path).Warn("Failed to remove incomplete snapshot")
+			}
+		case strings.HasSuffix(entry.Name(), snapshotExtension):
+			snapshot, err := readSnapshot(path)
+			if err != nil {
+				r.logger.WithError(err).WithField("file", path).Warn("Skipping unreadable snapshot")
+				if err := os.Rename(path, path+".corrupt"); err != nil {
+					r.logger.WithError(err).WithField("file", path).Warn("Failed to set aside unreadable snapshot")
+				}
+				continue
+			}
+			r.load(snapshot)
+			loaded++
+		}
+	}
+
+	// Limits may have been lowered since the snapshots were written
+	r.mutex.Lock()
+	r.enforceRetention("")
+	r.mutex.Unlock()
+
+	r.logger.WithFields(map[string]interface{}{
+		"path":     r.dir,
+		"projects": loaded,
+	}).Info("Loaded persisted project analyses")
+
+	return nil
+}
+
+// load restores a snapshot without touching its timestamps, unless the project is already loaded
+func (r *FileAnalysisRepository) load(snapshot *projectSnapshot) {
+	projectID := snapshot.Analysis.ID
+	if snapshot.Analysis.Version <= 0 {
+		// Written before projects were versioned
+		snapshot.Analysis.Version = 1
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.projects[projectID]; exists {
+		return
+	}
+	r.diskMutex.Lock()
+	delete(r.onDisk, projectID)
+	r.diskMutex.Unlock()
+
+	r.projects[projectID] = snapshot.Analysis
+	r.nodes[projectID] = make(map[string]*entity.CodeNode, len(snapshot.Nodes))
+	r.apis[projectID] = make(map[string]*entity.APIEndpoint, len(snapshot.APIs))
+
+	for _, node := range snapshot.Nodes {
+		r.nodes[projectID][node.ID] = node
+		r.indexSymbol(projectID, node)
+		r.indexSearch(projectID, node)
+	}
+	for _, endpoint := range snapshot.APIs {
+		r.apis[projectID][endpoint.ID] = endpoint
+	}
+	if len(snapshot.Queries) > 0 {
+		r.queries[projectID] = make(map[string]*entity.SavedQuery, len(snapshot.Queries))
+		for _, query := range snapshot.Queries {
+			r.queries[projectID][query.ID] = query
+		}
+	}
+	if len(snapshot.Versions) > 0 {
+		r.versions[projectID] = make(map[int]*entity.ProjectAnalysis, len(snapshot.Versions))
+		for _, archived := range snapshot.Versions {
+			r.versions[projectID][archived.Version] = archived
+		}
+	}
+	r.sizes[projectID] = estimateAnalysisBytes(snapshot.Analysis) + r.itemBytes(projectID)
+
+	// Restored projects count as accessed at startup so that downtime does not expire them
+	r.accessMutex.Lock()
+	r.accessed[projectID] = time.Now().UTC()
+	r.accessMutex.Unlock()
+}
+
+func (r *FileAnalysisRepository) snapshotPath(projectID string) string {
+	return filepath.Join(r.dir, url.PathEscape(projectID)+snapshotExtension)
+}
+
+func readSnapshot(path string) (*projectSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var snapshot projectSnapshot
+	if err := json.NewDecoder(gz).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	if snapshot.Analysis == nil || snapshot.Analysis.ID == "" {
+		return nil, fmt.Errorf("snapshot has no project analysis")
+	}
+
+	return &snapshot, nil
+}
+
+// writeFileAtomic writes a file through a synced temporary file and a rename
+func writeFileAtomic(path string, write func(file *os.File) error) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir makes a rename or removal in dir durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
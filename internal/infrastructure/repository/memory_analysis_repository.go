@@ -1,28 +1,68 @@
 package repository
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/internal/infrastructure/logger"
 	"goapianalyzer/pkg/errors"
 
 	"github.com/google/uuid"
 )
 
 type MemoryAnalysisRepository struct {
-	projects map[string]*entity.ProjectAnalysis
-	nodes    map[string]map[string]*entity.CodeNode    // projectID -> nodeID -> CodeNode
-	apis     map[string]map[string]*entity.APIEndpoint // projectID -> apiID -> APIEndpoint
-	mutex    sync.RWMutex
+	projects    map[string]*entity.ProjectAnalysis
+	nodes       map[string]map[string]*entity.CodeNode     // projectID -> nodeID -> CodeNode
+	apis        map[string]map[string]*entity.APIEndpoint  // projectID -> apiID -> APIEndpoint
+	symbols     map[string]map[string]map[string]bool      // projectID -> symbol key -> node IDs
+	search      map[string]*searchIndex                    // projectID -> inverted index of nodes
+	versions    map[string]map[int]*entity.ProjectAnalysis // projectID -> version -> archived analysis
+	queries     map[string]map[string]*entity.SavedQuery   // projectID -> queryID -> saved query
+	sizes       map[string]int64                           // projectID -> estimated bytes
+	accessed    map[string]time.Time                       // projectID -> last access
+	retention   *RetentionConfig
+	onEvict     func(projectID, reason string) // Called before a project is evicted, with the write lock held
+	onExpire    func()                         // Called after each periodic expiry, without the lock
+	mutex       sync.RWMutex
+	accessMutex sync.Mutex
+	stop        chan struct{}
+	closeOnce   sync.Once
+	logger      logger.Logger
 }
 
-func NewMemoryAnalysisRepository() *MemoryAnalysisRepository {
-	return &MemoryAnalysisRepository{
-		projects: make(map[string]*entity.ProjectAnalysis),
-		nodes:    make(map[string]map[string]*entity.CodeNode),
-		apis:     make(map[string]map[string]*entity.APIEndpoint),
+// NewMemoryAnalysisRepository creates an in-memory repository; a nil retention keeps every project
+func NewMemoryAnalysisRepository(retention *RetentionConfig) *MemoryAnalysisRepository {
+	r := &MemoryAnalysisRepository{
+		projects:  make(map[string]*entity.ProjectAnalysis),
+		nodes:     make(map[string]map[string]*entity.CodeNode),
+		apis:      make(map[string]map[string]*entity.APIEndpoint),
+		symbols:   make(map[string]map[string]map[string]bool),
+		search:    make(map[string]*searchIndex),
+		versions:  make(map[string]map[int]*entity.ProjectAnalysis),
+		queries:   make(map[string]map[string]*entity.SavedQuery),
+		sizes:     make(map[string]int64),
+		accessed:  make(map[string]time.Time),
+		retention: retention,
+		stop:      make(chan struct{}),
+		logger:    logger.GetLogger(),
 	}
+
+	if retention != nil && retention.TTL > 0 {
+		interval := retention.TTL / 10
+		if interval < time.Second {
+			interval = time.Second
+		}
+		if interval > time.Minute {
+			interval = time.Minute
+		}
+		go r.expireLoop(interval)
+	}
+
+	return r
 }
 
 // Project Analysis Methods
@@ -36,6 +76,9 @@ func (r *MemoryAnalysisRepository) StoreProjectAnalysis(analysis *entity.Project
 		analysis.ID = uuid.New().String()
 	}
 
+	if analysis.Version <= 0 {
+		analysis.Version = 1
+	}
 	if analysis.CreatedAt.IsZero() {
 		analysis.CreatedAt = time.Now().UTC()
 	}
@@ -44,7 +87,21 @@ func (r *MemoryAnalysisRepository) StoreProjectAnalysis(analysis *entity.Project
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	size := estimateAnalysisBytes(analysis) + r.itemBytes(analysis.ID)
+	if r.checkProjectBytes(size) != nil {
+		// Syntax trees are the largest part and can be rebuilt from the file content
+		releaseASTs(analysis)
+		size = estimateAnalysisBytes(analysis) + r.itemBytes(analysis.ID)
+		if err := r.checkProjectBytes(size); err != nil {
+			return err
+		}
+	}
+
 	r.projects[analysis.ID] = analysis
+	r.sizes[analysis.ID] = size
+	r.accessMutex.Lock()
+	r.accessed[analysis.ID] = time.Now().UTC()
+	r.accessMutex.Unlock()
 
 	// Initialize nodes map for this project
 	if r.nodes[analysis.ID] == nil {
@@ -56,6 +113,103 @@ func (r *MemoryAnalysisRepository) StoreProjectAnalysis(analysis *entity.Project
 		r.apis[analysis.ID] = make(map[string]*entity.APIEndpoint)
 	}
 
+	r.enforceRetention(analysis.ID)
+	return nil
+}
+
+// ReplaceProject stores an analysis with its nodes and the endpoints it lists in
+// place of the current analysis, nodes and endpoints of the project, keeping its
+// archived versions and saved queries. With archive the current analysis becomes
+// an archived version and analysis its successor. The replacement is sized as a
+// whole and swapped in one step, so a rejected one leaves the stored project untouched.
+func (r *MemoryAnalysisRepository) ReplaceProject(analysis *entity.ProjectAnalysis, nodes []*entity.CodeNode, archive bool) error {
+	if analysis == nil {
+		return errors.NewValidationError("analysis cannot be nil")
+	}
+	for _, node := range nodes {
+		if node == nil {
+			return errors.NewValidationError("node cannot be nil")
+		}
+	}
+	for _, endpoint := range analysis.APIEndpoints {
+		if endpoint == nil {
+			return errors.NewValidationError("endpoint cannot be nil")
+		}
+	}
+
+	if archive && analysis.ID == "" {
+		return errors.NewValidationError("project ID cannot be empty")
+	}
+
+	if analysis.ID == "" {
+		analysis.ID = uuid.New().String()
+	}
+	for _, node := range nodes {
+		if node.ID == "" {
+			node.ID = uuid.New().String()
+		}
+	}
+	for _, endpoint := range analysis.APIEndpoints {
+		if endpoint.ID == "" {
+			endpoint.ID = uuid.New().String()
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	versions := r.versions[analysis.ID]
+	if archive {
+		current, exists := r.projects[analysis.ID]
+		if !exists {
+			return errors.NewNotFoundError("project analysis not found")
+		}
+		versions = r.withVersion(analysis.ID, current)
+		analysis.Version = current.Version + 1
+		analysis.CreatedAt = current.CreatedAt
+		analysis.Pinned = current.Pinned
+	}
+	if analysis.Version <= 0 {
+		analysis.Version = 1
+	}
+	if analysis.CreatedAt.IsZero() {
+		analysis.CreatedAt = time.Now().UTC()
+	}
+	analysis.UpdatedAt = time.Now().UTC()
+
+	size := r.replacementBytes(analysis, nodes, versions)
+	if r.checkProjectBytes(size+estimateASTBytes(analysis)) != nil {
+		if err := r.checkProjectBytes(size); err != nil {
+			return err
+		}
+		// Syntax trees are the largest part and can be rebuilt from the file content
+		releaseASTs(analysis)
+	}
+	size += estimateASTBytes(analysis)
+
+	projectID := analysis.ID
+	r.projects[projectID] = analysis
+	r.nodes[projectID] = make(map[string]*entity.CodeNode, len(nodes))
+	r.apis[projectID] = make(map[string]*entity.APIEndpoint, len(analysis.APIEndpoints))
+	delete(r.symbols, projectID)
+	r.search[projectID] = newSearchIndex()
+	for _, node := range nodes {
+		r.nodes[projectID][node.ID] = node
+		r.indexSymbol(projectID, node)
+		r.search[projectID].add(node)
+	}
+	for _, endpoint := range analysis.APIEndpoints {
+		r.apis[projectID][endpoint.ID] = endpoint
+	}
+	if len(versions) > 0 {
+		r.versions[projectID] = versions
+	}
+	r.sizes[projectID] = size
+	r.accessMutex.Lock()
+	r.accessed[projectID] = time.Now().UTC()
+	r.accessMutex.Unlock()
+
+	r.enforceRetention(projectID)
 	return nil
 }
 
@@ -72,6 +226,7 @@ func (r *MemoryAnalysisRepository) GetProjectAnalysis(projectID string) (*entity
 		return nil, errors.NewNotFoundError("project analysis not found")
 	}
 
+	r.touch(projectID)
 	return analysis, nil
 }
 
@@ -87,13 +242,106 @@ func (r *MemoryAnalysisRepository) DeleteProjectAnalysis(projectID string) error
 		return errors.NewNotFoundError("project analysis not found")
 	}
 
+	r.removeProject(projectID)
+	return nil
+}
+
+// removeProject drops a project with everything stored for it. Callers must hold the write lock.
+func (r *MemoryAnalysisRepository) removeProject(projectID string) {
 	delete(r.projects, projectID)
 	delete(r.nodes, projectID)
 	delete(r.apis, projectID)
+	delete(r.symbols, projectID)
+	delete(r.search, projectID)
+	delete(r.versions, projectID)
+	delete(r.queries, projectID)
+	delete(r.sizes, projectID)
+
+	r.accessMutex.Lock()
+	delete(r.accessed, projectID)
+	r.accessMutex.Unlock()
+}
 
+// itemBytes estimates the memory held by the nodes, endpoints and archived versions of a project
+func (r *MemoryAnalysisRepository) itemBytes(projectID string) int64 {
+	var size int64
+	for _, archived := range r.versions[projectID] {
+		size += estimateAnalysisBytes(archived)
+	}
+	for _, node := range r.nodes[projectID] {
+		size += estimateNodeBytes(node)
+	}
+	for _, endpoint := range r.apis[projectID] {
+		size += estimateEndpointBytes(endpoint)
+	}
+	return size
+}
+
+func (r *MemoryAnalysisRepository) PinProject(projectID string, pinned bool) error {
+	if projectID == "" {
+		return errors.NewValidationError("project ID cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	analysis, exists := r.projects[projectID]
+	if !exists {
+		return errors.NewNotFoundError("project analysis not found")
+	}
+
+	analysis.Pinned = pinned
+	if !pinned {
+		r.enforceRetention("")
+	}
 	return nil
 }
 
+func (r *MemoryAnalysisRepository) GetStorageStats() (*entity.StorageStats, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.storageStats(), nil
+}
+
+// storageStats describes the loaded projects. Callers must hold the lock.
+func (r *MemoryAnalysisRepository) storageStats() *entity.StorageStats {
+	stats := &entity.StorageStats{
+		TotalProjects: len(r.projects),
+		Projects:      make([]*entity.ProjectStorageInfo, 0, len(r.projects)),
+		GeneratedAt:   time.Now().UTC(),
+	}
+	if r.retention != nil {
+		stats.MaxProjects = r.retention.MaxProjects
+		stats.MaxProjectBytes = r.retention.MaxProjectBytes
+		stats.TTLSeconds = int64(r.retention.TTL / time.Second)
+	}
+
+	for projectID, analysis := range r.projects {
+		info := &entity.ProjectStorageInfo{
+			ProjectID:      projectID,
+			ProjectPath:    analysis.ProjectPath,
+			Bytes:          r.sizes[projectID],
+			Files:          len(analysis.Files),
+			Nodes:          len(r.nodes[projectID]),
+			Endpoints:      len(r.apis[projectID]),
+			Pinned:         analysis.Pinned,
+			LastAccessedAt: r.lastAccess(projectID),
+			Versions:       len(r.versions[projectID]) + 1,
+			Loaded:         true,
+		}
+		r.setExpiry(info)
+		stats.TotalBytes += info.Bytes
+		stats.Projects = append(stats.Projects, info)
+	}
+
+	sort.Slice(stats.Projects, func(i, j int) bool {
+		return stats.Projects[i].Bytes > stats.Projects[j].Bytes
+	})
+
+	return stats
+}
+
 func (r *MemoryAnalysisRepository) ListProjects() ([]*entity.ProjectAnalysis, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
@@ -106,6 +354,173 @@ func (r *MemoryAnalysisRepository) ListProjects() ([]*entity.ProjectAnalysis, er
 	return projects, nil
 }
 
+// ListProjectSummaries returns counts and metadata of every project without marking them as accessed
+func (r *MemoryAnalysisRepository) ListProjectSummaries() ([]*entity.ProjectSummary, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	summaries := make([]*entity.ProjectSummary, 0, len(r.projects))
+	for projectID := range r.projects {
+		summaries = append(summaries, r.projectSummary(projectID))
+	}
+
+	return summaries, nil
+}
+
+// projectSummary summarizes a loaded project. Callers must hold the lock.
+func (r *MemoryAnalysisRepository) projectSummary(projectID string) *entity.ProjectSummary {
+	project := r.projects[projectID]
+	return &entity.ProjectSummary{
+		ID:             projectID,
+		Version:        project.Version,
+		ProjectPath:    project.ProjectPath,
+		FileCount:      len(project.Files),
+		PackageCount:   len(project.Packages),
+		NodeCount:      len(r.nodes[projectID]),
+		EndpointCount:  len(r.apis[projectID]),
+		ScanDurationMs: project.ScanDurationMs,
+		Config:         project.Config,
+		Pinned:         project.Pinned,
+		CreatedAt:      project.CreatedAt,
+		UpdatedAt:      project.UpdatedAt,
+	}
+}
+
+// Project Version Methods
+
+// StoreProjectVersion archives an analysis as a past version of its project.
+// Sources and syntax trees are dropped; the oldest versions beyond the retention limit are removed.
+func (r *MemoryAnalysisRepository) StoreProjectVersion(analysis *entity.ProjectAnalysis) error {
+	if analysis == nil {
+		return errors.NewValidationError("analysis cannot be nil")
+	}
+	if analysis.ID == "" {
+		return errors.NewValidationError("project ID cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.projects[analysis.ID]; !exists {
+		return errors.NewNotFoundError("project analysis not found")
+	}
+
+	versions := r.withVersion(analysis.ID, analysis)
+	r.sizes[analysis.ID] += versionsBytes(versions) - versionsBytes(r.versions[analysis.ID])
+	r.versions[analysis.ID] = versions
+	return nil
+}
+
+// withVersion returns the archived versions of a project with analysis archived
+// among them and the oldest beyond the retention limit dropped. The stored
+// versions are left as they are. Callers must hold the lock.
+func (r *MemoryAnalysisRepository) withVersion(projectID string, analysis *entity.ProjectAnalysis) map[int]*entity.ProjectAnalysis {
+	archived := archivedAnalysis(analysis)
+
+	versions := make(map[int]*entity.ProjectAnalysis, len(r.versions[projectID])+1)
+	for version, stored := range r.versions[projectID] {
+		versions[version] = stored
+	}
+	versions[archived.Version] = archived
+
+	if r.retention != nil && r.retention.MaxVersions > 0 {
+		numbers := make([]int, 0, len(versions))
+		for version := range versions {
+			numbers = append(numbers, version)
+		}
+		sort.Ints(numbers)
+		for _, version := range numbers[:max(0, len(numbers)-r.retention.MaxVersions)] {
+			delete(versions, version)
+		}
+	}
+	return versions
+}
+
+// GetProjectVersion returns the current analysis or an archived version of a project
+func (r *MemoryAnalysisRepository) GetProjectVersion(projectID string, version int) (*entity.ProjectAnalysis, error) {
+	if projectID == "" {
+		return nil, errors.NewValidationError("project ID cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	current, exists := r.projects[projectID]
+	if !exists {
+		return nil, errors.NewNotFoundError("project analysis not found")
+	}
+
+	r.touch(projectID)
+	if current.Version == version {
+		return current, nil
+	}
+
+	archived, exists := r.versions[projectID][version]
+	if !exists {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("project version %d not found", version))
+	}
+	return archived, nil
+}
+
+// ListProjectVersions lists the archived and current versions of a project, oldest first
+func (r *MemoryAnalysisRepository) ListProjectVersions(projectID string) ([]*entity.ProjectVersionInfo, error) {
+	if projectID == "" {
+		return nil, errors.NewValidationError("project ID cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	current, exists := r.projects[projectID]
+	if !exists {
+		return nil, errors.NewNotFoundError("project analysis not found")
+	}
+
+	infos := make([]*entity.ProjectVersionInfo, 0, len(r.versions[projectID])+1)
+	for _, archived := range r.versions[projectID] {
+		infos = append(infos, versionInfo(archived, false))
+	}
+	infos = append(infos, versionInfo(current, true))
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Version < infos[j].Version
+	})
+
+	return infos, nil
+}
+
+// archivedAnalysis copies an analysis without file contents and syntax trees
+func archivedAnalysis(analysis *entity.ProjectAnalysis) *entity.ProjectAnalysis {
+	archived := *analysis
+	archived.FileSet = nil
+	archived.Files = make(map[string]*entity.FileInfo, len(analysis.Files))
+	for path, fileInfo := range analysis.Files {
+		file := *fileInfo
+		file.Content = ""
+		file.AST = nil
+		archived.Files[path] = &file
+	}
+	if archived.Version <= 0 {
+		archived.Version = 1
+	}
+	return &archived
+}
+
+func versionInfo(analysis *entity.ProjectAnalysis, current bool) *entity.ProjectVersionInfo {
+	scannedAt := analysis.UpdatedAt
+	if scannedAt.IsZero() {
+		scannedAt = analysis.CreatedAt
+	}
+	return &entity.ProjectVersionInfo{
+		Version:       analysis.Version,
+		ProjectPath:   analysis.ProjectPath,
+		FileCount:     len(analysis.Files),
+		EndpointCount: len(analysis.APIEndpoints),
+		Current:       current,
+		ScannedAt:     scannedAt,
+	}
+}
+
 // Code Node Methods
 
 func (r *MemoryAnalysisRepository) StoreCodeNode(projectID string, node *entity.CodeNode) error {
@@ -133,7 +548,22 @@ func (r *MemoryAnalysisRepository) StoreCodeNode(projectID string, node *entity.
 		r.nodes[projectID] = make(map[string]*entity.CodeNode)
 	}
 
+	size := r.sizes[projectID] + estimateNodeBytes(node)
+	previous, exists := r.nodes[projectID][node.ID]
+	if exists {
+		size -= estimateNodeBytes(previous)
+	}
+	if err := r.checkProjectBytes(size); err != nil {
+		return err
+	}
+
+	if exists {
+		r.unindexSymbol(projectID, previous)
+	}
 	r.nodes[projectID][node.ID] = node
+	r.indexSymbol(projectID, node)
+	r.indexSearch(projectID, node)
+	r.sizes[projectID] = size
 	return nil
 }
 
@@ -150,6 +580,8 @@ func (r *MemoryAnalysisRepository) GetCodeNode(projectID, nodeID string) (*entit
 		return nil, errors.NewNotFoundError("project not found")
 	}
 
+	r.touch(projectID)
+
 	node, exists := projectNodes[nodeID]
 	if !exists {
 		return nil, errors.NewNotFoundError("code node not found")
@@ -158,6 +590,113 @@ func (r *MemoryAnalysisRepository) GetCodeNode(projectID, nodeID string) (*entit
 	return node, nil
 }
 
+func (r *MemoryAnalysisRepository) DeleteCodeNode(projectID, nodeID string) error {
+	if projectID == "" || nodeID == "" {
+		return errors.NewValidationError("project ID and node ID cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	projectNodes, exists := r.nodes[projectID]
+	if !exists {
+		return errors.NewNotFoundError("project not found")
+	}
+
+	node, exists := projectNodes[nodeID]
+	if !exists {
+		return errors.NewNotFoundError("code node not found")
+	}
+
+	r.unindexSymbol(projectID, node)
+	r.search[projectID].remove(nodeID)
+	delete(projectNodes, nodeID)
+	r.sizes[projectID] -= estimateNodeBytes(node)
+	return nil
+}
+
+func (r *MemoryAnalysisRepository) GetCodeNodesBySymbol(projectID, symbolKey string) ([]*entity.CodeNode, error) {
+	if projectID == "" || symbolKey == "" {
+		return nil, errors.NewValidationError("project ID and symbol key cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	projectNodes, exists := r.nodes[projectID]
+	if !exists {
+		return nil, errors.NewNotFoundError("project not found")
+	}
+
+	r.touch(projectID)
+
+	nodeIDs := r.symbols[projectID][symbolKey]
+	if len(nodeIDs) == 0 {
+		return nil, errors.NewNotFoundError("symbol not found")
+	}
+
+	nodes := make([]*entity.CodeNode, 0, len(nodeIDs))
+	for nodeID := range nodeIDs {
+		if node, exists := projectNodes[nodeID]; exists {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].File != nodes[j].File {
+			return nodes[i].File < nodes[j].File
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	return nodes, nil
+}
+
+// symbolKeys returns the keys a node can be looked up by: its full symbol key
+// and the short form qualified by package name instead of import path
+func (r *MemoryAnalysisRepository) symbolKeys(node *entity.CodeNode) []string {
+	if node.SymbolKey == "" {
+		return nil
+	}
+
+	keys := []string{node.SymbolKey}
+	if node.PackagePath != "" && node.PackagePath != node.Package && strings.HasPrefix(node.SymbolKey, node.PackagePath+".") {
+		keys = append(keys, node.Package+strings.TrimPrefix(node.SymbolKey, node.PackagePath))
+	}
+	return keys
+}
+
+func (r *MemoryAnalysisRepository) indexSymbol(projectID string, node *entity.CodeNode) {
+	if r.symbols[projectID] == nil {
+		r.symbols[projectID] = make(map[string]map[string]bool)
+	}
+	for _, key := range r.symbolKeys(node) {
+		if r.symbols[projectID][key] == nil {
+			r.symbols[projectID][key] = make(map[string]bool)
+		}
+		r.symbols[projectID][key][node.ID] = true
+	}
+}
+
+func (r *MemoryAnalysisRepository) unindexSymbol(projectID string, node *entity.CodeNode) {
+	for _, key := range r.symbolKeys(node) {
+		delete(r.symbols[projectID][key], node.ID)
+		if len(r.symbols[projectID][key]) == 0 {
+			delete(r.symbols[projectID], key)
+		}
+	}
+}
+
+// indexSearch adds a node to the project's search index, replacing what was indexed under its ID
+func (r *MemoryAnalysisRepository) indexSearch(projectID string, node *entity.CodeNode) {
+	index := r.search[projectID]
+	if index == nil {
+		index = newSearchIndex()
+		r.search[projectID] = index
+	}
+	index.remove(node.ID)
+	index.add(node)
+}
+
 func (r *MemoryAnalysisRepository) GetAllNodes(projectID string, page, limit int, nodeType string) ([]*entity.CodeNode, int64, error) {
 	if projectID == "" {
 		return nil, 0, errors.NewValidationError("project ID cannot be empty")
@@ -171,6 +710,8 @@ func (r *MemoryAnalysisRepository) GetAllNodes(projectID string, page, limit int
 		return nil, 0, errors.NewNotFoundError("project not found")
 	}
 
+	r.touch(projectID)
+
 	// Filter nodes by type if specified
 	var filteredNodes []*entity.CodeNode
 	for _, node := range projectNodes {
@@ -208,13 +749,20 @@ func (r *MemoryAnalysisRepository) SearchNodes(projectID, query string, page, li
 		return nil, 0, errors.NewNotFoundError("project not found")
 	}
 
-	// Simple search implementation - can be enhanced with better search algorithms
-	var matchedNodes []*entity.CodeNode
-	for _, node := range projectNodes {
-		if r.nodeMatchesQuery(node, query) {
+	r.touch(projectID)
+
+	var scores map[string]int
+	if index := r.search[projectID]; index != nil {
+		scores = index.search(query)
+	}
+
+	matchedNodes := make([]*entity.CodeNode, 0, len(scores))
+	for nodeID := range scores {
+		if node, exists := projectNodes[nodeID]; exists {
 			matchedNodes = append(matchedNodes, node)
 		}
 	}
+	rankNodes(matchedNodes, scores)
 
 	total := int64(len(matchedNodes))
 
@@ -232,45 +780,6 @@ func (r *MemoryAnalysisRepository) SearchNodes(projectID, query string, page, li
 	return matchedNodes[start:end], total, nil
 }
 
-func (r *MemoryAnalysisRepository) nodeMatchesQuery(node *entity.CodeNode, query string) bool {
-	// Simple case-insensitive search in name and body
-	return r.containsIgnoreCase(node.Name, query) ||
-		r.containsIgnoreCase(node.Body, query) ||
-		r.containsIgnoreCase(node.File, query) ||
-		r.containsIgnoreCase(node.Package, query)
-}
-
-func (r *MemoryAnalysisRepository) containsIgnoreCase(text, substr string) bool {
-	return len(text) >= len(substr) &&
-		r.toLower(text) != text &&
-		r.toLower(substr) != substr &&
-		r.contains(r.toLower(text), r.toLower(substr))
-}
-
-func (r *MemoryAnalysisRepository) toLower(s string) string {
-	result := make([]rune, len(s))
-	for i, r := range s {
-		if r >= 'A' && r <= 'Z' {
-			result[i] = r + 32
-		} else {
-			result[i] = r
-		}
-	}
-	return string(result)
-}
-
-func (r *MemoryAnalysisRepository) contains(text, substr string) bool {
-	if len(substr) > len(text) {
-		return false
-	}
-	for i := 0; i <= len(text)-len(substr); i++ {
-		if text[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
-
 // API Endpoint Methods
 
 func (r *MemoryAnalysisRepository) StoreAPIEndpoint(projectID string, endpoint *entity.APIEndpoint) error {
@@ -298,7 +807,11 @@ func (r *MemoryAnalysisRepository) StoreAPIEndpoint(projectID string, endpoint *
 		r.apis[projectID] = make(map[string]*entity.APIEndpoint)
 	}
 
+	if previous, exists := r.apis[projectID][endpoint.ID]; exists {
+		r.sizes[projectID] -= estimateEndpointBytes(previous)
+	}
 	r.apis[projectID][endpoint.ID] = endpoint
+	r.sizes[projectID] += estimateEndpointBytes(endpoint)
 	return nil
 }
 
@@ -315,6 +828,8 @@ func (r *MemoryAnalysisRepository) GetAPIEndpoint(projectID, apiID string) (*ent
 		return nil, errors.NewNotFoundError("project not found")
 	}
 
+	r.touch(projectID)
+
 	endpoint, exists := projectAPIs[apiID]
 	if !exists {
 		return nil, errors.NewNotFoundError("API endpoint not found")
@@ -323,6 +838,29 @@ func (r *MemoryAnalysisRepository) GetAPIEndpoint(projectID, apiID string) (*ent
 	return endpoint, nil
 }
 
+func (r *MemoryAnalysisRepository) DeleteAPIEndpoint(projectID, apiID string) error {
+	if projectID == "" || apiID == "" {
+		return errors.NewValidationError("project ID and API ID cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	projectAPIs, exists := r.apis[projectID]
+	if !exists {
+		return errors.NewNotFoundError("project not found")
+	}
+
+	endpoint, exists := projectAPIs[apiID]
+	if !exists {
+		return errors.NewNotFoundError("API endpoint not found")
+	}
+
+	delete(projectAPIs, apiID)
+	r.sizes[projectID] -= estimateEndpointBytes(endpoint)
+	return nil
+}
+
 func (r *MemoryAnalysisRepository) GetAPIEndpoints(projectID string) ([]*entity.APIEndpoint, error) {
 	if projectID == "" {
 		return nil, errors.NewValidationError("project ID cannot be empty")
@@ -336,6 +874,8 @@ func (r *MemoryAnalysisRepository) GetAPIEndpoints(projectID string) ([]*entity.
 		return nil, errors.NewNotFoundError("project not found")
 	}
 
+	r.touch(projectID)
+
 	endpoints := make([]*entity.APIEndpoint, 0, len(projectAPIs))
 	for _, endpoint := range projectAPIs {
 		endpoints = append(endpoints, endpoint)
@@ -374,3 +914,118 @@ func (r *MemoryAnalysisRepository) GetAPINodes(projectID, apiID string) ([]*enti
 
 	return apiNodes, nil
 }
+
+// Saved Query Methods
+
+func (r *MemoryAnalysisRepository) StoreSavedQuery(projectID string, query *entity.SavedQuery) error {
+	if projectID == "" {
+		return errors.NewValidationError("project ID cannot be empty")
+	}
+	if query == nil {
+		return errors.NewValidationError("saved query cannot be nil")
+	}
+
+	if query.ID == "" {
+		query.ID = uuid.New().String()
+	}
+	query.ProjectID = projectID
+	if query.CreatedAt.IsZero() {
+		query.CreatedAt = time.Now().UTC()
+	}
+	query.UpdatedAt = time.Now().UTC()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.projects[projectID]; !exists {
+		return errors.NewNotFoundError("project not found")
+	}
+
+	if r.queries[projectID] == nil {
+		r.queries[projectID] = make(map[string]*entity.SavedQuery)
+	}
+	for _, existing := range r.queries[projectID] {
+		if existing.ID != query.ID && strings.EqualFold(existing.Name, query.Name) {
+			return errors.NewValidationError(fmt.Sprintf("a saved query named %q already exists", query.Name))
+		}
+	}
+
+	r.queries[projectID][query.ID] = query
+	return nil
+}
+
+func (r *MemoryAnalysisRepository) GetSavedQuery(projectID, queryID string) (*entity.SavedQuery, error) {
+	if projectID == "" || queryID == "" {
+		return nil, errors.NewValidationError("project ID and query ID cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, exists := r.projects[projectID]; !exists {
+		return nil, errors.NewNotFoundError("project not found")
+	}
+
+	query, exists := r.queries[projectID][queryID]
+	if !exists {
+		return nil, errors.NewNotFoundError("saved query not found")
+	}
+
+	return query, nil
+}
+
+// ListSavedQueries returns the saved queries of a project ordered by name
+func (r *MemoryAnalysisRepository) ListSavedQueries(projectID string) ([]*entity.SavedQuery, error) {
+	if projectID == "" {
+		return nil, errors.NewValidationError("project ID cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, exists := r.projects[projectID]; !exists {
+		return nil, errors.NewNotFoundError("project not found")
+	}
+
+	queries := make([]*entity.SavedQuery, 0, len(r.queries[projectID]))
+	for _, query := range r.queries[projectID] {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].Name != queries[j].Name {
+			return queries[i].Name < queries[j].Name
+		}
+		return queries[i].ID < queries[j].ID
+	})
+
+	return queries, nil
+}
+
+func (r *MemoryAnalysisRepository) DeleteSavedQuery(projectID, queryID string) error {
+	if projectID == "" || queryID == "" {
+		return errors.NewValidationError("project ID and query ID cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.queries[projectID][queryID]; !exists {
+		return errors.NewNotFoundError("saved query not found")
+	}
+
+	delete(r.queries[projectID], queryID)
+	return nil
+}
+
+// FlushProject does nothing; memory storage has nothing to write
+func (r *MemoryAnalysisRepository) FlushProject(projectID string) error {
+	return nil
+}
+
+// Close stops the background expiry of projects
+func (r *MemoryAnalysisRepository) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+	})
+	return nil
+}
@@ -0,0 +1,561 @@
+package repository
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	domainrepository "goapianalyzer/internal/core/domain/repository"
+	"goapianalyzer/pkg/errors"
+)
+
+// repositoryFactory opens an implementation of the analysis repository under test
+type repositoryFactory struct {
+	name string
+	open func(t *testing.T, dir string, retention *RetentionConfig) domainrepository.AnalysisRepository
+	// persistent implementations keep their data across Close and open
+	persistent bool
+}
+
+var repositoryFactories = []repositoryFactory{
+	{
+		name: "memory",
+		open: func(t *testing.T, _ string, retention *RetentionConfig) domainrepository.AnalysisRepository {
+			return NewMemoryAnalysisRepository(retention)
+		},
+	},
+	{
+		name: "file",
+		open: func(t *testing.T, dir string, retention *RetentionConfig) domainrepository.AnalysisRepository {
+			repo, err := NewFileAnalysisRepository(&FileRepositoryConfig{Path: dir, Retention: retention})
+			if err != nil {
+				t.Fatalf("open file repository: %v", err)
+			}
+			return repo
+		},
+		persistent: true,
+	},
+}
+
+// forEachRepository runs a conformance case against every implementation
+func forEachRepository(t *testing.T, run func(t *testing.T, factory repositoryFactory, dir string)) {
+	for _, factory := range repositoryFactories {
+		t.Run(factory.name, func(t *testing.T) {
+			run(t, factory, t.TempDir())
+		})
+	}
+}
+
+func openRepository(t *testing.T, factory repositoryFactory, dir string) domainrepository.AnalysisRepository {
+	repo := factory.open(t, dir, nil)
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo
+}
+
+func testAnalysis(projectID string) *entity.ProjectAnalysis {
+	return &entity.ProjectAnalysis{
+		ID:          projectID,
+		ProjectPath: "/src/" + projectID,
+		Files: map[string]*entity.FileInfo{
+			"main.go": {Path: "main.go", PackageName: "main", Content: "package main\n"},
+		},
+		APIEndpoints: []*entity.APIEndpoint{testEndpoint()},
+	}
+}
+
+func testNode(id, name string) *entity.CodeNode {
+	return &entity.CodeNode{
+		ID:        id,
+		Name:      name,
+		Type:      "function",
+		File:      "main.go",
+		Package:   "main",
+		SymbolKey: "main." + name,
+		Body:      "func " + name + "() {}",
+	}
+}
+
+func testEndpoint() *entity.APIEndpoint {
+	return &entity.APIEndpoint{
+		ID:      entity.EndpointID("GET", "/health"),
+		Method:  "GET",
+		Path:    "/health",
+		Handler: "health",
+		File:    "main.go",
+	}
+}
+
+func expectNotFound(t *testing.T, err error, what string) {
+	t.Helper()
+	if !errors.IsNotFoundError(err) {
+		t.Fatalf("%s: expected not found error, got %v", what, err)
+	}
+}
+
+func TestRepositoryProjectAnalysis(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := openRepository(t, factory, dir)
+
+		_, err := repo.GetProjectAnalysis("p1")
+		expectNotFound(t, err, "get before store")
+
+		if err := repo.StoreProjectAnalysis(testAnalysis("p1")); err != nil {
+			t.Fatalf("store: %v", err)
+		}
+		analysis, err := repo.GetProjectAnalysis("p1")
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if analysis.ProjectPath != "/src/p1" || analysis.Version != 1 || analysis.CreatedAt.IsZero() {
+			t.Fatalf("unexpected analysis: path %q, version %d, created %v", analysis.ProjectPath, analysis.Version, analysis.CreatedAt)
+		}
+
+		projects, err := repo.ListProjects()
+		if err != nil || len(projects) != 1 {
+			t.Fatalf("list: %d projects, %v", len(projects), err)
+		}
+
+		if err := repo.PinProject("p1", true); err != nil {
+			t.Fatalf("pin: %v", err)
+		}
+		if analysis, _ := repo.GetProjectAnalysis("p1"); !analysis.Pinned {
+			t.Fatalf("project not pinned")
+		}
+
+		if err := repo.DeleteProjectAnalysis("p1"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		_, err = repo.GetProjectAnalysis("p1")
+		expectNotFound(t, err, "get after delete")
+		expectNotFound(t, repo.DeleteProjectAnalysis("p1"), "delete twice")
+	})
+}
+
+func TestRepositoryCodeNodes(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := openRepository(t, factory, dir)
+
+		expectNotFound(t, repo.StoreCodeNode("p1", testNode("n1", "Run")), "store node without project")
+
+		if err := repo.StoreProjectAnalysis(testAnalysis("p1")); err != nil {
+			t.Fatalf("store analysis: %v", err)
+		}
+		for _, node := range []*entity.CodeNode{testNode("n1", "Run"), testNode("n2", "Stop")} {
+			if err := repo.StoreCodeNode("p1", node); err != nil {
+				t.Fatalf("store node %s: %v", node.ID, err)
+			}
+		}
+
+		node, err := repo.GetCodeNode("p1", "n1")
+		if err != nil || node.Name != "Run" {
+			t.Fatalf("get node: %v, %v", node, err)
+		}
+		nodes, err := repo.GetCodeNodesBySymbol("p1", "main.Stop")
+		if err != nil || len(nodes) != 1 || nodes[0].ID != "n2" {
+			t.Fatalf("get by symbol: %v, %v", nodes, err)
+		}
+		nodes, total, err := repo.GetAllNodes("p1", 1, 10, "function")
+		if err != nil || total != 2 || len(nodes) != 2 {
+			t.Fatalf("get all: %d of %d, %v", len(nodes), total, err)
+		}
+		nodes, total, err = repo.SearchNodes("p1", "stop", 1, 10)
+		if err != nil || total != 1 || nodes[0].ID != "n2" {
+			t.Fatalf("search: %v (%d), %v", nodes, total, err)
+		}
+
+		if err := repo.DeleteCodeNode("p1", "n1"); err != nil {
+			t.Fatalf("delete node: %v", err)
+		}
+		_, err = repo.GetCodeNode("p1", "n1")
+		expectNotFound(t, err, "get deleted node")
+		if nodes, _ := repo.GetCodeNodesBySymbol("p1", "main.Run"); len(nodes) != 0 {
+			t.Fatalf("deleted node still indexed by symbol")
+		}
+	})
+}
+
+func TestRepositoryAPIEndpoints(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := openRepository(t, factory, dir)
+
+		if err := repo.StoreProjectAnalysis(testAnalysis("p1")); err != nil {
+			t.Fatalf("store analysis: %v", err)
+		}
+		endpoint := testEndpoint()
+		if err := repo.StoreAPIEndpoint("p1", endpoint); err != nil {
+			t.Fatalf("store endpoint: %v", err)
+		}
+		if err := repo.StoreCodeNode("p1", testNode("n1", "health")); err != nil {
+			t.Fatalf("store node: %v", err)
+		}
+
+		stored, err := repo.GetAPIEndpoint("p1", endpoint.ID)
+		if err != nil || stored.Path != "/health" {
+			t.Fatalf("get endpoint: %v, %v", stored, err)
+		}
+		endpoints, err := repo.GetAPIEndpoints("p1")
+		if err != nil || len(endpoints) != 1 {
+			t.Fatalf("get endpoints: %d, %v", len(endpoints), err)
+		}
+		nodes, err := repo.GetAPINodes("p1", endpoint.ID)
+		if err != nil || len(nodes) != 1 {
+			t.Fatalf("get endpoint nodes: %d, %v", len(nodes), err)
+		}
+
+		if err := repo.DeleteAPIEndpoint("p1", endpoint.ID); err != nil {
+			t.Fatalf("delete endpoint: %v", err)
+		}
+		_, err = repo.GetAPIEndpoint("p1", endpoint.ID)
+		expectNotFound(t, err, "get deleted endpoint")
+	})
+}
+
+func TestRepositoryVersions(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := openRepository(t, factory, dir)
+
+		first := testAnalysis("p1")
+		if err := repo.StoreProjectAnalysis(first); err != nil {
+			t.Fatalf("store first: %v", err)
+		}
+		if err := repo.StoreProjectVersion(first); err != nil {
+			t.Fatalf("archive first: %v", err)
+		}
+		second := testAnalysis("p1")
+		second.Version = 2
+		second.ProjectPath = "/src/p1-v2"
+		if err := repo.StoreProjectAnalysis(second); err != nil {
+			t.Fatalf("store second: %v", err)
+		}
+
+		versions, err := repo.ListProjectVersions("p1")
+		if err != nil || len(versions) != 2 || versions[0].Version != 1 || !versions[1].Current {
+			t.Fatalf("list versions: %+v, %v", versions, err)
+		}
+		archived, err := repo.GetProjectVersion("p1", 1)
+		if err != nil || archived.ProjectPath != "/src/p1" {
+			t.Fatalf("get archived version: %v, %v", archived, err)
+		}
+		if archived.Files["main.go"].Content != "" {
+			t.Fatalf("archived version keeps file content")
+		}
+		current, err := repo.GetProjectVersion("p1", 2)
+		if err != nil || current.ProjectPath != "/src/p1-v2" {
+			t.Fatalf("get current version: %v, %v", current, err)
+		}
+		_, err = repo.GetProjectVersion("p1", 3)
+		expectNotFound(t, err, "get unknown version")
+
+		if err := repo.DeleteProjectAnalysis("p1"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		_, err = repo.ListProjectVersions("p1")
+		expectNotFound(t, err, "list versions after delete")
+	})
+}
+
+func TestRepositoryReloadAfterClose(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		if !factory.persistent {
+			t.Skip("storage does not persist")
+		}
+
+		repo := factory.open(t, dir, nil)
+		first := testAnalysis("p1")
+		if err := repo.StoreProjectAnalysis(first); err != nil {
+			t.Fatalf("store: %v", err)
+		}
+		if err := repo.StoreProjectVersion(first); err != nil {
+			t.Fatalf("archive: %v", err)
+		}
+		second := testAnalysis("p1")
+		second.Version = 2
+		if err := repo.StoreProjectAnalysis(second); err != nil {
+			t.Fatalf("store second: %v", err)
+		}
+		if err := repo.StoreCodeNode("p1", testNode("n1", "Run")); err != nil {
+			t.Fatalf("store node: %v", err)
+		}
+		if err := repo.StoreAPIEndpoint("p1", testEndpoint()); err != nil {
+			t.Fatalf("store endpoint: %v", err)
+		}
+		if err := repo.StoreProjectAnalysis(testAnalysis("p2")); err != nil {
+			t.Fatalf("store p2: %v", err)
+		}
+		if err := repo.DeleteProjectAnalysis("p2"); err != nil {
+			t.Fatalf("delete p2: %v", err)
+		}
+		if err := repo.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		repo = openRepository(t, factory, dir)
+		analysis, err := repo.GetProjectAnalysis("p1")
+		if err != nil || analysis.Version != 2 || analysis.Files["main.go"].Content != "package main\n" {
+			t.Fatalf("reloaded analysis: %v, %v", analysis, err)
+		}
+		if _, err := repo.GetCodeNode("p1", "n1"); err != nil {
+			t.Fatalf("reloaded node: %v", err)
+		}
+		if nodes, _ := repo.GetCodeNodesBySymbol("p1", "main.Run"); len(nodes) != 1 {
+			t.Fatalf("reloaded node is not indexed by symbol")
+		}
+		if _, err := repo.GetAPIEndpoint("p1", testEndpoint().ID); err != nil {
+			t.Fatalf("reloaded endpoint: %v", err)
+		}
+		if _, err := repo.GetProjectVersion("p1", 1); err != nil {
+			t.Fatalf("reloaded version: %v", err)
+		}
+		_, err = repo.GetProjectAnalysis("p2")
+		expectNotFound(t, err, "deleted project after reload")
+	})
+}
+
+// Eviction must not lose persisted projects: they are loaded again on access
+func TestFileRepositoryEvictionKeepsSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFileAnalysisRepository(&FileRepositoryConfig{Path: dir, Retention: &RetentionConfig{MaxProjects: 1}})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer repo.Close()
+
+	for _, projectID := range []string{"p1", "p2"} {
+		if err := repo.StoreProjectAnalysis(testAnalysis(projectID)); err != nil {
+			t.Fatalf("store %s: %v", projectID, err)
+		}
+		if err := repo.StoreCodeNode(projectID, testNode("n1", "Run")); err != nil {
+			t.Fatalf("store node of %s: %v", projectID, err)
+		}
+		if err := repo.FlushProject(projectID); err != nil {
+			t.Fatalf("flush %s: %v", projectID, err)
+		}
+	}
+
+	if projects, _ := repo.ListProjects(); len(projects) != 1 {
+		t.Fatalf("expected one loaded project, got %d", len(projects))
+	}
+	for _, projectID := range []string{"p1", "p2"} {
+		if _, err := os.Stat(filepath.Join(dir, projectID+snapshotExtension)); err != nil {
+			t.Fatalf("snapshot of %s: %v", projectID, err)
+		}
+	}
+
+	summaries, err := repo.ListProjectSummaries()
+	if err != nil || len(summaries) != 2 {
+		t.Fatalf("list summaries: %d, %v", len(summaries), err)
+	}
+	stats, err := repo.GetStorageStats()
+	if err != nil || stats.TotalProjects != 2 {
+		t.Fatalf("storage stats: %+v, %v", stats, err)
+	}
+	for _, info := range stats.Projects {
+		if info.Loaded != (info.ProjectID == "p2") || info.Nodes != 1 {
+			t.Fatalf("storage info of %s: loaded %v, %d nodes", info.ProjectID, info.Loaded, info.Nodes)
+		}
+	}
+
+	if _, err := repo.GetCodeNode("p1", "n1"); err != nil {
+		t.Fatalf("evicted project was not loaded again: %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// Projects evicted again at startup are still listed
+	repo, err = NewFileAnalysisRepository(&FileRepositoryConfig{Path: dir, Retention: &RetentionConfig{MaxProjects: 1}})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if projects, _ := repo.ListProjects(); len(projects) != 1 {
+		t.Fatalf("expected one loaded project after reopening, got %d", len(projects))
+	}
+	if summaries, err := repo.ListProjectSummaries(); err != nil || len(summaries) != 2 {
+		t.Fatalf("list summaries after reopening: %d, %v", len(summaries), err)
+	}
+}
+
+// Expiry removes snapshots, of loaded projects and of those evicted to disk alike
+func TestFileRepositoryExpiryRemovesSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFileAnalysisRepository(&FileRepositoryConfig{Path: dir, Retention: &RetentionConfig{MaxProjects: 1, TTL: time.Hour}})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer repo.Close()
+
+	for _, projectID := range []string{"p1", "p2"} {
+		if err := repo.StoreProjectAnalysis(testAnalysis(projectID)); err != nil {
+			t.Fatalf("store %s: %v", projectID, err)
+		}
+		if err := repo.FlushProject(projectID); err != nil {
+			t.Fatalf("flush %s: %v", projectID, err)
+		}
+	}
+	snapshotExists := func(projectID string) bool {
+		_, err := os.Stat(filepath.Join(dir, projectID+snapshotExtension))
+		return err == nil
+	}
+
+	// p1 was evicted to disk; let both projects expire
+	repo.retention.TTL = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+
+	repo.removeExpired()
+	if snapshotExists("p1") || !snapshotExists("p2") {
+		t.Fatalf("after expiry on disk: p1 kept %v, p2 kept %v", snapshotExists("p1"), snapshotExists("p2"))
+	}
+
+	repo.mutex.Lock()
+	repo.enforceRetention("")
+	repo.mutex.Unlock()
+	repo.removeExpired()
+	if snapshotExists("p2") {
+		t.Fatalf("snapshot of expired project p2 kept")
+	}
+
+	if summaries, _ := repo.ListProjectSummaries(); len(summaries) != 0 {
+		t.Fatalf("expired projects still listed: %d", len(summaries))
+	}
+	_, err = repo.GetProjectAnalysis("p1")
+	expectNotFound(t, err, "get expired project")
+}
+
+func TestRepositoryReplaceProject(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := openRepository(t, factory, dir)
+
+		if err := repo.ReplaceProject(testAnalysis("p1"), []*entity.CodeNode{testNode("n1", "Run"), testNode("n2", "Stop")}, false); err != nil {
+			t.Fatalf("replace new project: %v", err)
+		}
+		if nodes, total, err := repo.GetAllNodes("p1", 1, 10, ""); err != nil || total != 2 || len(nodes) != 2 {
+			t.Fatalf("get all: %d of %d, %v", len(nodes), total, err)
+		}
+		if _, err := repo.GetAPIEndpoint("p1", testEndpoint().ID); err != nil {
+			t.Fatalf("get endpoint: %v", err)
+		}
+
+		replacement := testAnalysis("p1")
+		replacement.APIEndpoints = nil
+		if err := repo.ReplaceProject(replacement, []*entity.CodeNode{testNode("n3", "Start")}, false); err != nil {
+			t.Fatalf("replace: %v", err)
+		}
+		_, err := repo.GetCodeNode("p1", "n1")
+		expectNotFound(t, err, "get replaced node")
+		if nodes, _ := repo.GetCodeNodesBySymbol("p1", "main.Stop"); len(nodes) != 0 {
+			t.Fatalf("replaced node still indexed by symbol")
+		}
+		if _, total, _ := repo.SearchNodes("p1", "stop", 1, 10); total != 0 {
+			t.Fatalf("replaced node still found by search")
+		}
+		if nodes, _ := repo.GetCodeNodesBySymbol("p1", "main.Start"); len(nodes) != 1 {
+			t.Fatalf("replacement node not indexed by symbol")
+		}
+		_, err = repo.GetAPIEndpoint("p1", testEndpoint().ID)
+		expectNotFound(t, err, "get replaced endpoint")
+
+		next := testAnalysis("p1")
+		next.ProjectPath = "/src/p1-v2"
+		if err := repo.ReplaceProject(next, nil, true); err != nil {
+			t.Fatalf("replace with archive: %v", err)
+		}
+		versions, err := repo.ListProjectVersions("p1")
+		if err != nil || len(versions) != 2 || versions[1].Version != 2 || !versions[1].Current {
+			t.Fatalf("list versions: %+v, %v", versions, err)
+		}
+		if archived, err := repo.GetProjectVersion("p1", 1); err != nil || archived.ProjectPath != "/src/p1" {
+			t.Fatalf("get archived version: %v, %v", archived, err)
+		}
+		expectNotFound(t, repo.ReplaceProject(testAnalysis("p2"), nil, true), "archive unknown project")
+	})
+}
+
+func TestRepositoryReplaceProjectSize(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := factory.open(t, dir, &RetentionConfig{MaxProjectBytes: 4096})
+		t.Cleanup(func() { _ = repo.Close() })
+
+		nodes := make([]*entity.CodeNode, 0, 8)
+		for i := 0; i < cap(nodes); i++ {
+			nodes = append(nodes, testNode("n"+string(rune('a'+i)), "Run"))
+		}
+		// The nodes being replaced do not count towards the replacement
+		for i := 0; i < 2; i++ {
+			if err := repo.ReplaceProject(testAnalysis("p1"), nodes, false); err != nil {
+				t.Fatalf("replace %d rejected: %v", i+1, err)
+			}
+		}
+
+		oversized := make([]*entity.CodeNode, 0, 64)
+		for i := 0; i < cap(oversized); i++ {
+			oversized = append(oversized, testNode(string(rune('a'+i%26))+string(rune('a'+i/26)), "Run"))
+		}
+		replacement := testAnalysis("p1")
+		replacement.ProjectPath = "/src/p1-v2"
+		if err := repo.ReplaceProject(replacement, oversized, false); !errors.IsValidationError(err) {
+			t.Fatalf("expected validation error for oversized project, got %v", err)
+		}
+
+		// A rejected replacement leaves the stored project as it was
+		analysis, err := repo.GetProjectAnalysis("p1")
+		if err != nil || analysis.ProjectPath != "/src/p1" {
+			t.Fatalf("stored analysis changed: %v, %v", analysis, err)
+		}
+		if _, total, _ := repo.GetAllNodes("p1", 1, 100, ""); total != int64(len(nodes)) {
+			t.Fatalf("stored nodes changed: %d, want %d", total, len(nodes))
+		}
+	})
+}
+
+// The version archived by a replacement counts towards the size of the project
+func TestRepositoryReplaceProjectSizeWithArchive(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := factory.open(t, dir, &RetentionConfig{MaxProjectBytes: 4096})
+		t.Cleanup(func() { _ = repo.Close() })
+
+		analysis := func() *entity.ProjectAnalysis {
+			analysis := testAnalysis("p1")
+			analysis.Files["main.go"].Functions = []*entity.FunctionInfo{{Name: "main", Body: strings.Repeat("x", 1500)}}
+			return analysis
+		}
+		if err := repo.ReplaceProject(analysis(), nil, false); err != nil {
+			t.Fatalf("replace: %v", err)
+		}
+		if err := repo.ReplaceProject(analysis(), nil, true); !errors.IsValidationError(err) {
+			t.Fatalf("expected validation error for project with archived version, got %v", err)
+		}
+
+		versions, err := repo.ListProjectVersions("p1")
+		if err != nil || len(versions) != 1 || versions[0].Version != 1 {
+			t.Fatalf("rejected replacement changed versions: %+v, %v", versions, err)
+		}
+	})
+}
+
+// Releasing syntax trees to fit the limit must not change file infos that a
+// re-scan shares with the stored analysis
+func TestRepositoryReplaceProjectKeepsSharedFiles(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, factory repositoryFactory, dir string) {
+		repo := factory.open(t, dir, &RetentionConfig{MaxProjectBytes: 4096})
+		t.Cleanup(func() { _ = repo.Close() })
+
+		shared := &entity.FileInfo{Path: "main.go", PackageName: "main", Content: "package main\n" + strings.Repeat("//\n", 200), AST: &ast.File{}}
+		analysis := testAnalysis("p1")
+		analysis.Files["main.go"] = shared
+		if err := repo.ReplaceProject(analysis, nil, false); err != nil {
+			t.Fatalf("replace: %v", err)
+		}
+
+		if shared.AST == nil {
+			t.Fatalf("shared file info lost its syntax tree")
+		}
+		stored, err := repo.GetProjectAnalysis("p1")
+		if err != nil || stored.Files["main.go"].AST != nil {
+			t.Fatalf("stored analysis keeps its syntax trees beyond the limit: %v", err)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"goapianalyzer/internal/core/domain/entity"
+	"goapianalyzer/pkg/errors"
+)
+
+// Rough per-object overheads used by the memory estimate
+const (
+	fileOverheadBytes     = 512
+	nodeOverheadBytes     = 256
+	endpointOverheadBytes = 128
+	astBytesPerSourceByte = 6 // go/ast trees are several times larger than their source
+)
+
+// RetentionConfig limits how many analyses are kept and for how long.
+// Zero values disable the corresponding limit.
+type RetentionConfig struct {
+	MaxProjects     int           // Least recently accessed projects are evicted beyond this count
+	TTL             time.Duration // Projects not accessed for this long expire
+	MaxProjectBytes int64         // Estimated memory a single project may hold
+	MaxVersions     int           // Archived versions kept per project besides the current one
+}
+
+// touch records an access to a project for LRU eviction and TTL expiry
+func (r *MemoryAnalysisRepository) touch(projectID string) {
+	r.accessMutex.Lock()
+	defer r.accessMutex.Unlock()
+
+	if _, exists := r.accessed[projectID]; exists {
+		r.accessed[projectID] = time.Now().UTC()
+	}
+}
+
+func (r *MemoryAnalysisRepository) lastAccess(projectID string) time.Time {
+	r.accessMutex.Lock()
+	defer r.accessMutex.Unlock()
+
+	return r.accessed[projectID]
+}
+
+// enforceRetention evicts expired projects and then the least recently accessed
+// ones until the project limit holds. Pinned projects and keep are never evicted.
+// Callers must hold the write lock.
+func (r *MemoryAnalysisRepository) enforceRetention(keep string) {
+	if r.retention == nil {
+		return
+	}
+
+	type candidate struct {
+		projectID  string
+		lastAccess time.Time
+	}
+
+	now := time.Now().UTC()
+	var candidates []candidate
+	for projectID, analysis := range r.projects {
+		if projectID == keep || analysis.Pinned {
+			continue
+		}
+
+		lastAccess := r.lastAccess(projectID)
+		if r.retention.TTL > 0 && now.Sub(lastAccess) > r.retention.TTL {
+			r.evict(projectID, "expired")
+			continue
+		}
+		candidates = append(candidates, candidate{projectID: projectID, lastAccess: lastAccess})
+	}
+
+	if r.retention.MaxProjects <= 0 || len(r.projects) <= r.retention.MaxProjects {
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lastAccess.Before(candidates[j].lastAccess)
+	})
+	for _, c := range candidates {
+		if len(r.projects) <= r.retention.MaxProjects {
+			break
+		}
+		r.evict(c.projectID, "lru")
+	}
+}
+
+// evict notifies the eviction hook and removes a project. Callers must hold the write lock.
+func (r *MemoryAnalysisRepository) evict(projectID, reason string) {
+	r.logger.WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"reason":     reason,
+		"bytes":      r.sizes[projectID],
+	}).Info("Evicting project analysis")
+
+	if r.onEvict != nil {
+		r.onEvict(projectID, reason)
+	}
+	r.removeProject(projectID)
+}
+
+// expireLoop periodically removes projects whose TTL has passed
+func (r *MemoryAnalysisRepository) expireLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.mutex.Lock()
+			r.enforceRetention("")
+			r.mutex.Unlock()
+			if r.onExpire != nil {
+				r.onExpire()
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// setExpiry sets when an unpinned project expires without further access
+func (r *MemoryAnalysisRepository) setExpiry(info *entity.ProjectStorageInfo) {
+	if r.retention != nil && r.retention.TTL > 0 && !info.Pinned {
+		expiresAt := info.LastAccessedAt.Add(r.retention.TTL)
+		info.ExpiresAt = &expiresAt
+	}
+}
+
+// checkProjectBytes rejects a project whose estimated size exceeds the per-project cap
+func (r *MemoryAnalysisRepository) checkProjectBytes(size int64) error {
+	if r.retention == nil || r.retention.MaxProjectBytes <= 0 || size <= r.retention.MaxProjectBytes {
+		return nil
+	}
+	return errors.NewValidationError(fmt.Sprintf(
+		"project exceeds storage limit: %d bytes estimated, %d allowed", size, r.retention.MaxProjectBytes))
+}
+
+// replacementBytes estimates the size of a project replaced by analysis, nodes
+// and the archived versions it keeps, leaving out syntax trees
+func (r *MemoryAnalysisRepository) replacementBytes(analysis *entity.ProjectAnalysis, nodes []*entity.CodeNode, versions map[int]*entity.ProjectAnalysis) int64 {
+	size := estimateSourceBytes(analysis) + versionsBytes(versions)
+	for _, node := range nodes {
+		size += estimateNodeBytes(node)
+	}
+	for _, endpoint := range analysis.APIEndpoints {
+		size += estimateEndpointBytes(endpoint)
+	}
+	return size
+}
+
+// releaseASTs drops parsed syntax trees; the file content is kept and can be re-parsed.
+// File infos are replaced by copies, as re-scans share them with the stored analysis.
+func releaseASTs(analysis *entity.ProjectAnalysis) {
+	for path, fileInfo := range analysis.Files {
+		if fileInfo.AST != nil {
+			released := *fileInfo
+			released.AST = nil
+			analysis.Files[path] = &released
+		}
+	}
+	analysis.FileSet = nil
+}
+
+// versionsBytes estimates the memory held by archived versions
+func versionsBytes(versions map[int]*entity.ProjectAnalysis) int64 {
+	var size int64
+	for _, archived := range versions {
+		size += estimateAnalysisBytes(archived)
+	}
+	return size
+}
+
+func estimateAnalysisBytes(analysis *entity.ProjectAnalysis) int64 {
+	return estimateSourceBytes(analysis) + estimateASTBytes(analysis)
+}
+
+// estimateASTBytes estimates the memory held by the syntax trees of an analysis
+func estimateASTBytes(analysis *entity.ProjectAnalysis) int64 {
+	var size int64
+	for _, fileInfo := range analysis.Files {
+		if fileInfo.AST != nil {
+			size += int64(len(fileInfo.Content)) * astBytesPerSourceByte
+		}
+	}
+	return size
+}
+
+// estimateSourceBytes estimates the memory held by an analysis besides its syntax trees
+func estimateSourceBytes(analysis *entity.ProjectAnalysis) int64 {
+	var size int64
+	for path, fileInfo := range analysis.Files {
+		size += fileOverheadBytes + int64(len(path)+len(fileInfo.AbsolutePath)+len(fileInfo.Content))
+		for _, function := range fileInfo.Functions {
+			size += nodeOverheadBytes + int64(len(function.Body))
+		}
+	}
+	if analysis.DependencyGraph != nil {
+		size += int64(len(analysis.DependencyGraph.Nodes)+len(analysis.DependencyGraph.Dependencies)) * nodeOverheadBytes
+	}
+	size += int64(len(analysis.APIEndpoints)) * endpointOverheadBytes
+	return size
+}
+
+func estimateNodeBytes(node *entity.CodeNode) int64 {
+	return nodeOverheadBytes + int64(len(node.Body)+len(node.Doc)+len(node.SymbolKey)+len(node.File))
+}
+
+func estimateEndpointBytes(endpoint *entity.APIEndpoint) int64 {
+	return endpointOverheadBytes + int64(len(endpoint.Path)+len(endpoint.File))
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"goapianalyzer/internal/core/domain/entity"
+)
+
+// Field weights of the search index: a name match outranks a doc comment match,
+// which outranks a file, package or body match
+const (
+	exactNameWeight    = 200 // The whole identifier equals the query word
+	nameWeight         = 100
+	docWeight          = 10
+	locationWeight     = 6 // File path and package
+	bodyWeight         = 1
+	maxBodyOccurrences = 5 // Repetitions in a body stop adding relevance here
+	minTokenLength     = 2
+)
+
+var wordPattern = regexp.MustCompile(`[\pL\pN_]+`)
+
+// searchIndex is the inverted index of the code nodes of one project
+type searchIndex struct {
+	postings map[string]map[string]int // token -> node ID -> weight
+	terms    map[string][]string       // node ID -> indexed tokens, for removal
+
+	// vocabulary holds the tokens in order for prefix lookups. Changes only record
+	// the added tokens; searches, which run under the repository's read lock, merge
+	// them in under vocabMutex before the next lookup.
+	vocabulary []string
+	added      []string
+	removed    bool
+	vocabMutex sync.Mutex
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[string]int),
+		terms:    make(map[string][]string),
+	}
+}
+
+func (idx *searchIndex) add(node *entity.CodeNode) {
+	weights := nodeTokenWeights(node)
+	terms := make([]string, 0, len(weights))
+	for token, weight := range weights {
+		if idx.postings[token] == nil {
+			idx.postings[token] = make(map[string]int)
+			idx.added = append(idx.added, token)
+		}
+		idx.postings[token][node.ID] = weight
+		terms = append(terms, token)
+	}
+	idx.terms[node.ID] = terms
+}
+
+func (idx *searchIndex) remove(nodeID string) {
+	for _, token := range idx.terms[nodeID] {
+		delete(idx.postings[token], nodeID)
+		if len(idx.postings[token]) == 0 {
+			delete(idx.postings, token)
+			idx.removed = true
+		}
+	}
+	delete(idx.terms, nodeID)
+}
+
+// search scores the nodes containing every word of the query. Words without an
+// exact token match fall back to tokens they prefix, at half weight.
+func (idx *searchIndex) search(query string) map[string]int {
+	words := queryWords(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var scores map[string]int
+	for _, word := range words {
+		matches := idx.lookup(word)
+		if len(matches) == 0 {
+			return nil
+		}
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for nodeID := range scores {
+			weight, exists := matches[nodeID]
+			if !exists {
+				delete(scores, nodeID)
+				continue
+			}
+			scores[nodeID] += weight
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+	return scores
+}
+
+// lookup returns the weight of every node matching a query word, which matches
+// either as a whole identifier or through all of its camelCase and snake_case parts
+func (idx *searchIndex) lookup(word string) map[string]int {
+	matches := make(map[string]int)
+	for nodeID, weight := range idx.prefixed(strings.ToLower(word)) {
+		matches[nodeID] = weight
+	}
+
+	parts := identifierParts(word)
+	if len(parts) < 2 {
+		return matches
+	}
+	var partScores map[string]int
+	for _, part := range parts {
+		partMatches := idx.prefixed(part)
+		if partScores == nil {
+			partScores = partMatches
+			continue
+		}
+		for nodeID := range partScores {
+			weight, exists := partMatches[nodeID]
+			if !exists {
+				delete(partScores, nodeID)
+				continue
+			}
+			partScores[nodeID] += weight
+		}
+	}
+	// Matching the whole identifier as well ranks above matching its parts alone
+	for nodeID, weight := range partScores {
+		matches[nodeID] += weight
+	}
+	return matches
+}
+
+// prefixed returns the postings of a token, or of the tokens it prefixes at half weight
+func (idx *searchIndex) prefixed(token string) map[string]int {
+	matches := make(map[string]int)
+	if postings, exists := idx.postings[token]; exists {
+		for nodeID, weight := range postings {
+			matches[nodeID] = weight
+		}
+		return matches
+	}
+
+	for _, candidate := range idx.withPrefix(token) {
+		for nodeID, weight := range idx.postings[candidate] {
+			if weight/2 > matches[nodeID] {
+				matches[nodeID] = weight / 2
+			}
+		}
+	}
+	return matches
+}
+
+// withPrefix returns the indexed tokens starting with prefix, which are adjacent in the vocabulary
+func (idx *searchIndex) withPrefix(prefix string) []string {
+	idx.vocabMutex.Lock()
+	defer idx.vocabMutex.Unlock()
+	idx.refreshVocabulary()
+
+	start := sort.SearchStrings(idx.vocabulary, prefix)
+	end := start + sort.Search(len(idx.vocabulary)-start, func(i int) bool {
+		return !strings.HasPrefix(idx.vocabulary[start+i], prefix)
+	})
+	return idx.vocabulary[start:end]
+}
+
+// refreshVocabulary merges the tokens added since the last lookup into the vocabulary
+// and drops the removed ones. The caller holds vocabMutex.
+func (idx *searchIndex) refreshVocabulary() {
+	if len(idx.added) == 0 && !idx.removed {
+		return
+	}
+
+	sort.Strings(idx.added)
+	merged := make([]string, 0, len(idx.vocabulary)+len(idx.added))
+	keep := func(token string) {
+		// A token removed and added again is in both lists, or twice in added
+		if _, exists := idx.postings[token]; exists && (len(merged) == 0 || merged[len(merged)-1] != token) {
+			merged = append(merged, token)
+		}
+	}
+	i, j := 0, 0
+	for i < len(idx.vocabulary) || j < len(idx.added) {
+		if j == len(idx.added) || i < len(idx.vocabulary) && idx.vocabulary[i] < idx.added[j] {
+			keep(idx.vocabulary[i])
+			i++
+		} else {
+			keep(idx.added[j])
+			j++
+		}
+	}
+
+	idx.vocabulary = merged
+	idx.added = nil
+	idx.removed = false
+}
+
+// nodeTokenWeights collects the tokens of a node with the weight of the best field each occurs in
+func nodeTokenWeights(node *entity.CodeNode) map[string]int {
+	weights := make(map[string]int)
+	keep := func(token string, weight int) {
+		if weight > weights[token] {
+			weights[token] = weight
+		}
+	}
+
+	if node.Name != "" {
+		keep(strings.ToLower(node.Name), exactNameWeight)
+		for _, part := range identifierParts(node.Name) {
+			keep(part, nameWeight)
+		}
+	}
+	for _, token := range textTokens(node.Doc) {
+		keep(token, docWeight)
+	}
+	for _, token := range textTokens(node.File + " " + node.Package + " " + node.PackagePath) {
+		keep(token, locationWeight)
+	}
+
+	occurrences := make(map[string]int)
+	for _, token := range textTokens(node.Body) {
+		occurrences[token]++
+	}
+	for token, count := range occurrences {
+		if count > maxBodyOccurrences {
+			count = maxBodyOccurrences
+		}
+		weights[token] += bodyWeight * count
+	}
+
+	return weights
+}
+
+// textTokens splits text into lower-cased words and the camelCase and snake_case parts of each word
+func textTokens(text string) []string {
+	var tokens []string
+	for _, word := range wordPattern.FindAllString(text, -1) {
+		if lower := strings.ToLower(word); len(lower) >= minTokenLength {
+			tokens = append(tokens, lower)
+		}
+		parts := identifierParts(word)
+		if len(parts) > 1 {
+			tokens = append(tokens, parts...)
+		}
+	}
+	return tokens
+}
+
+// queryWords splits a search query into its words, keeping their case for camelCase splitting
+func queryWords(query string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range wordPattern.FindAllString(query, -1) {
+		lower := strings.ToLower(word)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		words = append(words, word)
+	}
+	return words
+}
+
+// identifierParts splits an identifier on underscores and case changes, so
+// parseHTTPRequest yields parse, http and request
+func identifierParts(identifier string) []string {
+	var parts []string
+	for _, segment := range strings.FieldsFunc(identifier, func(r rune) bool { return r == '_' }) {
+		runes := []rune(segment)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+				unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) ||
+				unicode.IsLetter(prev) != unicode.IsLetter(cur)
+			if boundary {
+				parts = append(parts, string(runes[start:i]))
+				start = i
+			}
+		}
+		parts = append(parts, string(runes[start:]))
+	}
+
+	tokens := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if len(part) >= minTokenLength {
+			tokens = append(tokens, strings.ToLower(part))
+		}
+	}
+	return tokens
+}
+
+// rankNodes orders search results by score, then name, file and ID so pages stay stable
+func rankNodes(nodes []*entity.CodeNode, scores map[string]int) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSearchIndexPrefixes(t *testing.T) {
+	idx := newSearchIndex()
+	idx.add(testNode("n1", "StoreAnalysis"))
+	idx.add(testNode("n2", "StopScan"))
+	idx.add(testNode("n3", "Start"))
+
+	matching := func(query string) string {
+		var ids []string
+		for nodeID := range idx.search(query) {
+			ids = append(ids, nodeID)
+		}
+		sort.Strings(ids)
+		return strings.Join(ids, ",")
+	}
+
+	if got := matching("sto"); got != "n1,n2" {
+		t.Errorf("sto matched %q, want n1,n2", got)
+	}
+	if got := matching("st"); got != "n1,n2,n3" {
+		t.Errorf("st matched %q, want n1,n2,n3", got)
+	}
+
+	// Tokens removed and added again between searches are listed once
+	idx.remove("n2")
+	if got := matching("sto"); got != "n1" {
+		t.Errorf("sto matched %q after removing n2, want n1", got)
+	}
+	idx.add(testNode("n2", "StopScan"))
+	idx.remove("n2")
+	idx.add(testNode("n2", "StopScan"))
+	if got := matching("sto"); got != "n1,n2" {
+		t.Errorf("sto matched %q after adding n2 again, want n1,n2", got)
+	}
+	for i := 1; i < len(idx.vocabulary); i++ {
+		if idx.vocabulary[i-1] >= idx.vocabulary[i] {
+			t.Fatalf("vocabulary is not strictly sorted at %q, %q", idx.vocabulary[i-1], idx.vocabulary[i])
+		}
+	}
+}